@@ -23,7 +23,10 @@ type Args struct {
 	Positional []string
 }
 
-// ParseArgs parses command-line arguments into a structured format
+// ParseArgs parses command-line arguments into a structured format.
+// A "--" argument ends flag parsing: every argument after it is positional,
+// even if it starts with a dash, so it can be passed through to another
+// program. The "--" itself is not included.
 func ParseArgs(args []string) *Args {
 	result := &Args{
 		Flags:      make(map[string]string),
@@ -34,6 +37,12 @@ func ParseArgs(args []string) *Args {
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
 
+		if arg == "--" {
+			// End of flags
+			result.Positional = append(result.Positional, args[i+1:]...)
+			break
+		}
+
 		if after, ok := strings.CutPrefix(arg, "--"); ok {
 			// Long flag
 			flagName := after
@@ -206,8 +215,18 @@ func PromptPasswordWithIO(prompt string, in io.Reader, out io.Writer) string {
 
 	// If in is an *os.File and a terminal, use ReadPassword to disable echo
 	if f, ok := in.(*os.File); ok {
-		if term.IsTerminal(int(f.Fd())) {
-			b, err := term.ReadPassword(int(f.Fd()))
+		if fd := int(f.Fd()); term.IsTerminal(fd) {
+			// ReadPassword disables echo; restore it if interrupted mid-prompt
+			if state, err := term.GetState(fd); err == nil {
+				deregister := handleWidgetInterrupt(func() {
+					_ = term.Restore(fd, state)
+					_, _ = fmt.Fprintln(out)
+				})
+				if deregister != nil {
+					defer deregister()
+				}
+			}
+			b, err := term.ReadPassword(fd)
 			_, _ = fmt.Fprintln(out)
 			if err == nil {
 				return strings.TrimSpace(string(b))
@@ -350,10 +369,11 @@ func PrintTable(data [][]string) {
 
 // ProgressBar represents a console progress bar
 type ProgressBar struct {
-	total   int
-	current int
-	width   int
-	prefix  string
+	total      int
+	current    int
+	width      int
+	prefix     string
+	deregister func()
 }
 
 // NewProgressBar creates a new progress bar
@@ -384,6 +404,10 @@ func (pb *ProgressBar) Update(current int) {
 func (pb *ProgressBar) Finish() {
 	pb.current = pb.total
 	pb.render()
+	if pb.deregister != nil {
+		pb.deregister()
+		pb.deregister = nil
+	}
 	fmt.Println()
 }
 
@@ -393,41 +417,54 @@ func (pb *ProgressBar) render() {
 		return
 	}
 
-	// Ensure current is within bounds
-	current := pb.current
+	// End the bar's line if interrupted before it completes
+	if pb.current < pb.total && pb.deregister == nil {
+		pb.deregister = handleWidgetInterrupt(func() { fmt.Println() })
+	} else if pb.current >= pb.total && pb.deregister != nil {
+		pb.deregister()
+		pb.deregister = nil
+	}
+
+	fmt.Print("\r" + formatProgress(pb.prefix, pb.current, pb.total, pb.width))
+}
 
+// formatProgress renders one progress line without a trailing newline.
+// total must be positive.
+func formatProgress(prefix string, current, total, width int) string {
+	// Ensure current is within bounds
 	if current < 0 {
 		current = 0
 	}
 
-	if current > pb.total {
-		current = pb.total
+	if current > total {
+		current = total
 	}
 
-	percentage := float64(current) / float64(pb.total)
-	filled := int(percentage * float64(pb.width))
+	percentage := float64(current) / float64(total)
+	filled := int(percentage * float64(width))
 
 	// Ensure filled is within bounds
 	if filled < 0 {
 		filled = 0
 	}
-	if filled > pb.width {
-		filled = pb.width
+	if filled > width {
+		filled = width
 	}
 
-	bar := strings.Repeat("█", filled) + strings.Repeat("░", pb.width-filled)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
 
-	fmt.Printf("\r%s: [%s] %.1f%% (%d/%d)",
-		pb.prefix, bar, percentage*100, current, pb.total)
+	return fmt.Sprintf("%s: [%s] %.1f%% (%d/%d)",
+		prefix, bar, percentage*100, current, total)
 }
 
 // Spinner represents a console spinner
 type Spinner struct {
-	message  string
-	frames   []string
-	active   bool
-	stopChan chan bool
-	mu       sync.RWMutex
+	message    string
+	frames     []string
+	active     bool
+	stopChan   chan bool
+	deregister func()
+	mu         sync.RWMutex
 }
 
 // NewSpinner creates a new spinner
@@ -449,6 +486,13 @@ func (s *Spinner) Start() {
 	}
 
 	s.active = true
+	// Clear the spinner line if interrupted before Stop
+	s.deregister = handleWidgetInterrupt(func() {
+		s.mu.RLock()
+		width := len(s.message) + 2
+		s.mu.RUnlock()
+		fmt.Print("\r" + strings.Repeat(" ", width) + "\r")
+	})
 	s.mu.Unlock()
 
 	go func() {
@@ -478,8 +522,14 @@ func (s *Spinner) Stop() {
 	}
 
 	s.active = false
+	deregister := s.deregister
+	s.deregister = nil
 	s.mu.Unlock()
 
+	if deregister != nil {
+		deregister()
+	}
+
 	select {
 	case s.stopChan <- true:
 	default:
@@ -33,6 +33,24 @@ func TestParseArgs(t *testing.T) {
 				Positional: []string{},
 			},
 		},
+		{
+			name: "end of flags separator",
+			args: []string{"--verbose", "--", "--not-a-flag", "file"},
+			expected: &cliutil.Args{
+				Flags:      map[string]string{},
+				BoolFlags:  map[string]bool{"verbose": true},
+				Positional: []string{"--not-a-flag", "file"},
+			},
+		},
+		{
+			name: "pass-through command",
+			args: []string{"run", "-o", "out.log", "--", "prog", "-x", "--", "--flag=1"},
+			expected: &cliutil.Args{
+				Flags:      map[string]string{"o": "out.log"},
+				BoolFlags:  map[string]bool{},
+				Positional: []string{"run", "prog", "-x", "--", "--flag=1"},
+			},
+		},
 		{
 			name: "only positional",
 			args: []string{"file1.txt", "file2.txt"},
@@ -256,9 +274,9 @@ func TestParseArgsEdgeCases(t *testing.T) {
 	args := cliutil.ParseArgs([]string{"input.txt", "--flag"})
 	tst.AssertTrue(t, args.HasFlag("flag"), "flag should be parsed as boolean flag")
 
-	// Test empty flag name
+	// Test a lone end-of-flags separator
 	args = cliutil.ParseArgs([]string{"--"})
-	tst.AssertTrue(t, len(args.BoolFlags) == 1 && args.BoolFlags[""], "empty flag name should be handled")
+	tst.AssertTrue(t, len(args.BoolFlags) == 0 && len(args.Positional) == 0, "-- alone should produce nothing")
 
 	// Test single dash
 	args = cliutil.ParseArgs([]string{"-"})
@@ -0,0 +1,254 @@
+package cliutil
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Command describes a CLI command tree used for shell completion generation.
+type Command struct {
+	// Name is the command name as typed on the command line
+	Name string
+	// Description is a short, one-line description of the command
+	Description string
+	// Flags contains flag names without leading dashes (e.g., "verbose", "v")
+	Flags []string
+	// Subcommands contains nested commands
+	Subcommands []*Command
+}
+
+// Supported shells for completion script generation
+const (
+	ShellBash = "bash"
+	ShellZsh  = "zsh"
+	ShellFish = "fish"
+)
+
+// GenerateCompletion produces a shell completion script for the given command
+// tree. Supported shells are "bash", "zsh", and "fish".
+func GenerateCompletion(cmd *Command, shell string) (string, error) {
+	if cmd == nil {
+		return "", fmt.Errorf("command is nil")
+	}
+	if strings.TrimSpace(cmd.Name) == "" {
+		return "", fmt.Errorf("command name is empty")
+	}
+	if strings.ContainsFunc(cmd.Name, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) {
+		return "", fmt.Errorf("command name %q contains whitespace or control characters", cmd.Name)
+	}
+
+	switch strings.ToLower(shell) {
+	case ShellBash:
+		return generateBashCompletion(cmd), nil
+	case ShellZsh:
+		return generateZshCompletion(cmd), nil
+	case ShellFish:
+		return generateFishCompletion(cmd), nil
+	default:
+		return "", fmt.Errorf("unsupported shell: %s", shell)
+	}
+}
+
+// completionEntry holds the completion candidates for a subcommand path
+type completionEntry struct {
+	path  string
+	words []string
+}
+
+// collectCompletions walks the command tree and returns the candidates for
+// each subcommand path, keyed by the space-separated path below the root.
+func collectCompletions(cmd *Command, path string, entries *[]completionEntry) {
+	words := make([]string, 0, len(cmd.Subcommands)+len(cmd.Flags))
+	for _, sub := range cmd.Subcommands {
+		if sub != nil {
+			words = append(words, sub.Name)
+		}
+	}
+	for _, flag := range cmd.Flags {
+		words = append(words, flagPrefix(flag)+flag)
+	}
+	*entries = append(*entries, completionEntry{path: path, words: words})
+
+	for _, sub := range cmd.Subcommands {
+		if sub == nil {
+			continue
+		}
+		subPath := sub.Name
+		if path != "" {
+			subPath = path + " " + sub.Name
+		}
+		collectCompletions(sub, subPath, entries)
+	}
+}
+
+// flagPrefix returns the dash prefix for a flag name
+func flagPrefix(flag string) string {
+	if len(flag) == 1 {
+		return "-"
+	}
+	return "--"
+}
+
+// shellQuote quotes s as a single bash or zsh word. Nothing inside single
+// quotes is expanded; each embedded single quote closes the quoted string,
+// adds an escaped quote, and reopens it.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote quotes s as a single fish word. Inside fish single quotes only
+// backslash and single quote need escaping.
+func fishQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+// quoteWords quotes each word with quote and joins them with spaces
+func quoteWords(words []string, quote func(string) string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = quote(w)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// subcommandPaths returns the quoted, |-separated non-root paths of entries
+// for use as a case pattern, or "" if the command has no subcommands.
+func subcommandPaths(entries []completionEntry) string {
+	var paths []string
+	for _, e := range entries {
+		if e.path != "" {
+			paths = append(paths, shellQuote(e.path))
+		}
+	}
+	return strings.Join(paths, "|")
+}
+
+// completionFuncName returns a shell-safe function name for the command
+func completionFuncName(name string) string {
+	var b strings.Builder
+	b.WriteString("_")
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	b.WriteString("_completion")
+	return b.String()
+}
+
+func generateBashCompletion(cmd *Command) string {
+	var entries []completionEntry
+	collectCompletions(cmd, "", &entries)
+	fn := completionFuncName(cmd.Name)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# bash completion for %s\n", cmd.Name)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    local cmd=\"\" next w i\n")
+	// Only words that extend a known subcommand path are kept, so flags,
+	// flag values, and positional arguments are skipped.
+	b.WriteString("    for ((i=1; i<COMP_CWORD; i++)); do\n")
+	b.WriteString("        next=\"${cmd:+$cmd }${COMP_WORDS[i]}\"\n")
+	if paths := subcommandPaths(entries); paths != "" {
+		fmt.Fprintf(&b, "        case \"$next\" in %s) cmd=\"$next\" ;; esac\n", paths)
+	}
+	b.WriteString("    done\n")
+	b.WriteString("    local -a opts=()\n")
+	b.WriteString("    case \"$cmd\" in\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "        %s) opts=(%s) ;;\n", shellQuote(e.path), quoteWords(e.words, shellQuote))
+	}
+	b.WriteString("    esac\n")
+	// Candidates are filtered here rather than with compgen -W, which would
+	// expand the word list a second time.
+	b.WriteString("    COMPREPLY=()\n")
+	b.WriteString("    for w in \"${opts[@]}\"; do\n")
+	b.WriteString("        [[ \"$w\" == \"$cur\"* ]] && COMPREPLY+=(\"$w\")\n")
+	b.WriteString("    done\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "complete -F %s %s\n", fn, shellQuote(cmd.Name))
+	return b.String()
+}
+
+func generateZshCompletion(cmd *Command) string {
+	var entries []completionEntry
+	collectCompletions(cmd, "", &entries)
+	fn := completionFuncName(cmd.Name)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %s\n\n", cmd.Name)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local cmd=\"\" next i\n")
+	b.WriteString("    for ((i=2; i<CURRENT; i++)); do\n")
+	b.WriteString("        next=\"${cmd:+$cmd }${words[i]}\"\n")
+	if paths := subcommandPaths(entries); paths != "" {
+		fmt.Fprintf(&b, "        case \"$next\" in (%s) cmd=\"$next\" ;; esac\n", paths)
+	}
+	b.WriteString("    done\n")
+	b.WriteString("    local -a opts\n")
+	b.WriteString("    case \"$cmd\" in\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "        (%s) opts=(%s) ;;\n", shellQuote(e.path), quoteWords(e.words, shellQuote))
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("    compadd -- \"${opts[@]}\"\n")
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "compdef %s %s\n", fn, shellQuote(cmd.Name))
+	return b.String()
+}
+
+func generateFishCompletion(cmd *Command) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %s\n", cmd.Name)
+	fmt.Fprintf(&b, "complete -c %s -f\n", fishQuote(cmd.Name))
+	writeFishCommand(&b, fishQuote(cmd.Name), cmd, nil)
+	return b.String()
+}
+
+// writeFishCommand emits fish completions for the subcommands and flags of
+// cmd. root is the quoted root command name and parents holds the names of
+// the enclosing subcommands (excluding root).
+func writeFishCommand(b *strings.Builder, root string, cmd *Command, parents []string) {
+	// The -n condition and -a arguments are evaluated by fish, so the names
+	// inside them are quoted once for that evaluation and once as a word.
+	condition := "__fish_use_subcommand"
+	if len(parents) > 0 {
+		condition = "__fish_seen_subcommand_from " + fishQuote(parents[len(parents)-1])
+	}
+
+	for _, sub := range cmd.Subcommands {
+		if sub == nil {
+			continue
+		}
+		line := fmt.Sprintf("complete -c %s -n %s -a %s", root, fishQuote(condition), fishQuote(fishQuote(sub.Name)))
+		if sub.Description != "" {
+			line += " -d " + fishQuote(sub.Description)
+		}
+		b.WriteString(line + "\n")
+	}
+
+	flagCondition := ""
+	if len(parents) > 0 {
+		flagCondition = " -n " + fishQuote(condition)
+	}
+	for _, flag := range cmd.Flags {
+		opt := "-l"
+		if len(flag) == 1 {
+			opt = "-s"
+		}
+		fmt.Fprintf(b, "complete -c %s%s %s %s\n", root, flagCondition, opt, fishQuote(flag))
+	}
+
+	for _, sub := range cmd.Subcommands {
+		if sub == nil {
+			continue
+		}
+		subParents := append(append([]string{}, parents...), sub.Name)
+		writeFishCommand(b, root, sub, subParents)
+	}
+}
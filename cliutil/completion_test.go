@@ -0,0 +1,104 @@
+package cliutil_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/go-utils/cliutil"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func newTestCommand() *cliutil.Command {
+	return &cliutil.Command{
+		Name:  "myapp",
+		Flags: []string{"verbose", "v"},
+		Subcommands: []*cliutil.Command{
+			{Name: "serve", Description: "Start the server", Flags: []string{"port"}},
+			{
+				Name:        "migrate",
+				Description: "Run migrations",
+				Subcommands: []*cliutil.Command{
+					{Name: "up"},
+					{Name: "down"},
+				},
+			},
+		},
+	}
+}
+
+func TestGenerateCompletion_Bash(t *testing.T) {
+	script, err := cliutil.GenerateCompletion(newTestCommand(), "bash")
+	tst.RequireNoError(t, err)
+
+	for _, name := range []string{"serve", "migrate", "up", "down"} {
+		tst.AssertTrue(t, strings.Contains(script, name), "bash script should reference "+name)
+	}
+	tst.AssertTrue(t, strings.Contains(script, "--verbose"), "bash script should reference long flag")
+	tst.AssertTrue(t, strings.Contains(script, "-v"), "bash script should reference short flag")
+	tst.AssertTrue(t, strings.Contains(script, "complete -F _myapp_completion 'myapp'"), "bash script should register")
+}
+
+func TestGenerateCompletion_Zsh(t *testing.T) {
+	script, err := cliutil.GenerateCompletion(newTestCommand(), "zsh")
+	tst.RequireNoError(t, err)
+
+	tst.AssertTrue(t, strings.HasPrefix(script, "#compdef myapp"), "zsh script should start with compdef")
+	tst.AssertTrue(t, strings.Contains(script, "serve"), "zsh script should reference serve")
+	tst.AssertTrue(t, strings.Contains(script, "--port"), "zsh script should reference port flag")
+}
+
+func TestGenerateCompletion_Fish(t *testing.T) {
+	script, err := cliutil.GenerateCompletion(newTestCommand(), "fish")
+	tst.RequireNoError(t, err)
+
+	tst.AssertTrue(t, strings.Contains(script, `-a '\'serve\'' -d 'Start the server'`),
+		"fish script should describe serve")
+	tst.AssertTrue(t, strings.Contains(script, `__fish_seen_subcommand_from \'migrate\'`), "fish script should nest")
+	tst.AssertTrue(t, strings.Contains(script, "-s 'v'"), "fish script should include short flag")
+}
+
+func TestGenerateCompletion_Quoting(t *testing.T) {
+	cmd := &cliutil.Command{
+		Name: "myapp",
+		Subcommands: []*cliutil.Command{
+			{Name: "it's", Description: "Run $(rm -rf ~) and `id`"},
+		},
+	}
+
+	script, err := cliutil.GenerateCompletion(cmd, "bash")
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, strings.Contains(script, `'') opts=('it'\''s') ;;`), "bash should single-quote names")
+	tst.AssertFalse(t, strings.Contains(script, "compgen -W"), "bash should not re-expand the word list")
+
+	script, err = cliutil.GenerateCompletion(cmd, "zsh")
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, strings.Contains(script, `('') opts=('it'\''s') ;;`), "zsh should single-quote names")
+
+	script, err = cliutil.GenerateCompletion(cmd, "fish")
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, strings.Contains(script, `-a '\'it\\\'s\'' -d 'Run $(rm -rf ~) and `+"`id`'"),
+		"fish should single-quote names and descriptions")
+}
+
+func TestGenerateCompletion_BashSkipsFlagValues(t *testing.T) {
+	script, err := cliutil.GenerateCompletion(newTestCommand(), "bash")
+	tst.RequireNoError(t, err)
+
+	// Only words that extend a known subcommand path are added to the path
+	tst.AssertTrue(t, strings.Contains(script, `case "$next" in 'serve'|'migrate'|'migrate up'|'migrate down')`),
+		"bash should match words against subcommand paths")
+}
+
+func TestGenerateCompletion_Errors(t *testing.T) {
+	_, err := cliutil.GenerateCompletion(newTestCommand(), "powershell")
+	tst.AssertErrorContains(t, err, "unsupported shell")
+
+	_, err = cliutil.GenerateCompletion(nil, "bash")
+	tst.AssertErrorContains(t, err, "command is nil")
+
+	_, err = cliutil.GenerateCompletion(&cliutil.Command{}, "bash")
+	tst.AssertErrorContains(t, err, "command name is empty")
+
+	_, err = cliutil.GenerateCompletion(&cliutil.Command{Name: "my\napp"}, "zsh")
+	tst.AssertErrorContains(t, err, "whitespace or control characters")
+}
@@ -0,0 +1,271 @@
+package cliutil
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// diffMaxEdits caps the edit distance diffLines searches for. Texts that
+// differ by more lines are shown as a whole-file replacement instead.
+const diffMaxEdits = 2000
+
+// noNewlineMarker is appended to a final line that has no trailing newline, so
+// it differs from the same line with one. Lines never otherwise contain '\n'.
+const noNewlineMarker = "\n"
+
+// ConfirmDiff shows a unified diff of oldText and newText on stdout and asks
+// the user whether to apply the change. The diff is colorized only when stdout
+// is a terminal and NO_COLOR is unset. If the texts are identical it reports
+// that there is nothing to change and returns false without prompting.
+func ConfirmDiff(oldText, newText string) (bool, error) {
+	return ConfirmDiffWithIO(oldText, newText, os.Stdin, os.Stdout)
+}
+
+// ConfirmDiffWithIO is like ConfirmDiff but uses the provided reader and
+// writer. This is useful for testing where stdin/stdout can be simulated.
+// It returns an error if input ends before the user answers.
+func ConfirmDiffWithIO(oldText, newText string, in io.Reader, out io.Writer) (bool, error) {
+	diff := RenderDiff(oldText, newText, colorEnabled(out))
+	if diff == "" {
+		_, _ = fmt.Fprintln(out, "No changes.")
+		return false, nil
+	}
+	_, _ = fmt.Fprint(out, diff)
+
+	ok, err := promptYesNo(bufio.NewReader(in), out, "Apply these changes? [y/n]: ")
+	if err != nil {
+		_, _ = fmt.Fprintln(out)
+		return false, fmt.Errorf("confirmation input ended: %w", err)
+	}
+	return ok, nil
+}
+
+// RenderDiff returns a line-based unified diff from oldText to newText with
+// three lines of context, or "" if they are identical. When color is true,
+// removed lines are red, added lines green, and hunk headers cyan.
+func RenderDiff(oldText, newText string, color bool) string {
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	paint := func(s string, c Color) string {
+		if !color {
+			return s
+		}
+		return string(c) + s + string(ColorReset)
+	}
+
+	var b strings.Builder
+	for _, h := range diffHunks(ops) {
+		if b.Len() == 0 {
+			b.WriteString(paint("--- old", ColorBold) + "\n")
+			b.WriteString(paint("+++ new", ColorBold) + "\n")
+		}
+		b.WriteString(paint(h.header(), ColorCyan) + "\n")
+		for _, op := range ops[h.start:h.end] {
+			text, noNewline := strings.CutSuffix(op.text, noNewlineMarker)
+			line := string(op.kind) + text
+			switch op.kind {
+			case '-':
+				line = paint(line, ColorRed)
+			case '+':
+				line = paint(line, ColorGreen)
+			}
+			b.WriteString(line + "\n")
+			if noNewline {
+				b.WriteString("\\ No newline at end of file\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// colorEnabled reports whether colored output should be written to out.
+func colorEnabled(out io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := out.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// splitLines splits text into lines. If text does not end with a newline,
+// noNewlineMarker is appended to its last line.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	if last := len(lines) - 1; lines[last] == "" {
+		lines = lines[:last]
+	} else {
+		lines[last] += noNewlineMarker
+	}
+	return lines
+}
+
+// diffOp is one line of an edit script: ' ' keeps, '-' removes, and '+' adds
+// a line. oldLine and newLine are the 1-based positions of the line in each
+// text, or of the line it follows when it is absent from that text.
+type diffOp struct {
+	kind    byte
+	text    string
+	oldLine int
+	newLine int
+}
+
+// diffLines computes a shortest edit script from a to b using Myers'
+// algorithm. Only the diagonals reachable at each step are kept for the
+// backtrack, so memory grows with the square of the edit distance rather
+// than with the edit distance times the input size. If the texts differ by
+// more than diffMaxEdits lines it returns replaceLines(a, b).
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	maxD := n + m
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	var trace [][]int
+
+search:
+	for d := 0; d <= maxD; d++ {
+		if d > diffMaxEdits {
+			return replaceLines(a, b)
+		}
+		// Step d only reads diagonals -d-1 through d+1 of the previous step
+		trace = append(trace, append([]int(nil), v[offset-d-1:offset+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Walk the trace backwards to recover the edit script
+	var ops []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v, off := trace[d], d+1
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[off+prevK]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			ops = append(ops, diffOp{kind: ' ', text: a[x-1], oldLine: x, newLine: y})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				ops = append(ops, diffOp{kind: '+', text: b[y-1], oldLine: x, newLine: y})
+			} else {
+				ops = append(ops, diffOp{kind: '-', text: a[x-1], oldLine: x, newLine: y})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// replaceLines returns an edit script that removes every line of a and then
+// adds every line of b.
+func replaceLines(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for i, line := range a {
+		ops = append(ops, diffOp{kind: '-', text: line, oldLine: i + 1})
+	}
+	for j, line := range b {
+		ops = append(ops, diffOp{kind: '+', text: line, oldLine: len(a), newLine: j + 1})
+	}
+	return ops
+}
+
+// diffHunk is a range of ops rendered under one @@ header.
+type diffHunk struct {
+	ops        []diffOp
+	start, end int
+}
+
+// header returns the hunk's "@@ -l,s +l,s @@" line.
+func (h diffHunk) header() string {
+	oldStart, newStart, oldCount, newCount := 0, 0, 0, 0
+	for _, op := range h.ops[h.start:h.end] {
+		if op.kind != '+' {
+			if oldCount == 0 {
+				oldStart = op.oldLine
+			}
+			oldCount++
+		}
+		if op.kind != '-' {
+			if newCount == 0 {
+				newStart = op.newLine
+			}
+			newCount++
+		}
+	}
+	// An empty range is numbered by the line it follows
+	if oldCount == 0 {
+		oldStart = h.ops[h.start].oldLine
+	}
+	if newCount == 0 {
+		newStart = h.ops[h.start].newLine
+	}
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldStart, oldCount, newStart, newCount)
+}
+
+// diffHunks groups changes in ops into hunks with diffContext lines of
+// context, merging changes whose context would overlap.
+func diffHunks(ops []diffOp) []diffHunk {
+	var hunks []diffHunk
+	for i := 0; i < len(ops); i++ {
+		if ops[i].kind == ' ' {
+			continue
+		}
+
+		start := max(i-diffContext, 0)
+		end := i + 1
+		for end < len(ops) {
+			// Find the next change within reach of this hunk's trailing context
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*diffContext {
+				break
+			}
+			end = next + 1
+		}
+		end = min(end+diffContext, len(ops))
+
+		hunks = append(hunks, diffHunk{ops: ops, start: start, end: end})
+		i = end - 1
+	}
+	return hunks
+}
@@ -0,0 +1,152 @@
+package cliutil_test
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/go-utils/cliutil"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+const diffOld = `host: localhost
+port: 8080
+debug: false
+log_level: info
+workers: 4
+timeout: 30s
+retries: 3
+cache: true
+cache_ttl: 5m
+metrics: true
+region: us-east-1
+`
+
+const diffNew = `host: localhost
+port: 9090
+debug: false
+log_level: info
+workers: 4
+timeout: 30s
+retries: 3
+cache: true
+cache_ttl: 5m
+metrics: true
+region: eu-west-1
+replicas: 2
+`
+
+func TestRenderDiff(t *testing.T) {
+	want := `--- old
++++ new
+@@ -1,5 +1,5 @@
+ host: localhost
+-port: 8080
++port: 9090
+ debug: false
+ log_level: info
+ workers: 4
+@@ -8,4 +8,5 @@
+ cache: true
+ cache_ttl: 5m
+ metrics: true
+-region: us-east-1
++region: eu-west-1
++replicas: 2
+`
+	tst.AssertEqual(t, cliutil.RenderDiff(diffOld, diffNew, false), want)
+}
+
+func TestRenderDiff_MergesNearbyChanges(t *testing.T) {
+	want := `--- old
++++ new
+@@ -1,7 +1,7 @@
+ a
+-b
++B
+ c
+ d
+ e
+ f
+-g
++G
+`
+	tst.AssertEqual(t, cliutil.RenderDiff("a\nb\nc\nd\ne\nf\ng\n", "a\nB\nc\nd\ne\nf\nG\n", false), want)
+}
+
+func TestRenderDiff_Edges(t *testing.T) {
+	tst.AssertEqual(t, cliutil.RenderDiff(diffOld, diffOld, false), "")
+	tst.AssertEqual(t, cliutil.RenderDiff("", "a\nb\n", false), "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n")
+	tst.AssertEqual(t, cliutil.RenderDiff("a\nb\n", "", false), "--- old\n+++ new\n@@ -1,2 +0,0 @@\n-a\n-b\n")
+
+	colored := cliutil.RenderDiff("a\n", "b\n", true)
+	tst.AssertTrue(t, strings.Contains(colored, string(cliutil.ColorRed)+"-a"+string(cliutil.ColorReset)), "removed line should be red")
+	tst.AssertTrue(t, strings.Contains(colored, string(cliutil.ColorGreen)+"+b"+string(cliutil.ColorReset)), "added line should be green")
+}
+
+func TestRenderDiff_TrailingNewline(t *testing.T) {
+	tst.AssertEqual(t, cliutil.RenderDiff("a\nb", "a\nb\n", false),
+		"--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n")
+	tst.AssertEqual(t, cliutil.RenderDiff("a\n", "a", false),
+		"--- old\n+++ new\n@@ -1,1 +1,1 @@\n-a\n+a\n\\ No newline at end of file\n")
+	tst.AssertEqual(t, cliutil.RenderDiff("a", "a", false), "")
+
+	var out bytes.Buffer
+	ok, err := cliutil.ConfirmDiffWithIO("a", "a\n", strings.NewReader("y\n"), &out)
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, ok, "A trailing newline change should be confirmable")
+}
+
+func TestRenderDiff_LargeInputs(t *testing.T) {
+	lines := func(prefix string, n int) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = prefix + strconv.Itoa(i)
+		}
+		return out
+	}
+
+	// A small change in a large file still gets a minimal hunk
+	oldLines := lines("line", 20000)
+	newLines := append([]string(nil), oldLines...)
+	newLines[10000] = "changed"
+	diff := cliutil.RenderDiff(strings.Join(oldLines, "\n"), strings.Join(newLines, "\n"), false)
+	tst.AssertTrue(t, strings.Contains(diff, "@@ -9998,7 +9998,7 @@\n"), "expected a single 7-line hunk")
+	tst.AssertEqual(t, strings.Count(diff, "\n-"), 1)
+
+	// Texts that differ almost everywhere fall back to a whole-file replacement
+	oldText := "same\n" + strings.Join(lines("old", 3000), "\n")
+	newText := "same\n" + strings.Join(lines("new", 3000), "\n")
+	diff = cliutil.RenderDiff(oldText, newText, false)
+	tst.AssertTrue(t, strings.HasPrefix(diff, "--- old\n+++ new\n@@ -1,3001 +1,3001 @@\n-same\n"), "expected a whole-file hunk")
+	tst.AssertEqual(t, strings.Count(diff, "\n-old"), 3000)
+	tst.AssertEqual(t, strings.Count(diff, "\n+new"), 3000)
+}
+
+func TestConfirmDiffWithIO(t *testing.T) {
+	var out bytes.Buffer
+	ok, err := cliutil.ConfirmDiffWithIO("a\n", "b\n", strings.NewReader("maybe\ny\n"), &out)
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, ok, "answering y should confirm")
+
+	output := out.String()
+	tst.AssertTrue(t, strings.Contains(output, "-a\n+b\n"), "diff should be shown")
+	tst.AssertFalse(t, strings.Contains(output, string(cliutil.ColorGreen)+"+b"), "diff should not be colored for non-terminal output")
+	tst.AssertTrue(t, strings.Contains(output, "Apply these changes? [y/n]: "), "prompt should be shown")
+
+	ok, err = cliutil.ConfirmDiffWithIO("a\n", "b\n", strings.NewReader("n\n"), &out)
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, ok, "answering n should decline")
+
+	_, err = cliutil.ConfirmDiffWithIO("a\n", "b\n", strings.NewReader(""), &out)
+	tst.AssertNotNil(t, err, "ended input should return an error")
+}
+
+func TestConfirmDiffWithIO_NoChanges(t *testing.T) {
+	var out bytes.Buffer
+	ok, err := cliutil.ConfirmDiffWithIO("same\n", "same\n", strings.NewReader(""), &out)
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, ok, "identical texts should not need confirmation")
+	tst.AssertEqual(t, out.String(), "No changes.\n")
+}
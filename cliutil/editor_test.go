@@ -0,0 +1,57 @@
+package cliutil_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/julianstephens/go-utils/cliutil"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+// fakeEditor writes a shell script that runs body with the file path as $1.
+func fakeEditor(t *testing.T, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake editor script requires a POSIX shell")
+	}
+	path := filepath.Join(t.TempDir(), "editor.sh")
+	err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755)
+	tst.RequireNoError(t, err)
+	return path
+}
+
+func TestPromptEditor(t *testing.T) {
+	editor := fakeEditor(t, `printf 'added line\n' >> "$1"`)
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", editor)
+
+	result, err := cliutil.PromptEditor("# Describe the change\n")
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, result, "# Describe the change\nadded line\n")
+}
+
+func TestPromptEditor_RemovesTempFile(t *testing.T) {
+	// The script records the path it was given so the test can check cleanup
+	record := filepath.Join(t.TempDir(), "path.txt")
+	editor := fakeEditor(t, `printf '%s' "$1" > "`+record+`"`)
+	t.Setenv("VISUAL", editor)
+
+	_, err := cliutil.PromptEditor("text")
+	tst.RequireNoError(t, err)
+
+	edited, err := os.ReadFile(record)
+	tst.RequireNoError(t, err)
+	_, err = os.Stat(string(edited))
+	tst.AssertTrue(t, os.IsNotExist(err), "temp file should be removed")
+}
+
+func TestPromptEditor_EditorFails(t *testing.T) {
+	editor := fakeEditor(t, "exit 1")
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", editor)
+
+	_, err := cliutil.PromptEditor("text")
+	tst.AssertErrorContains(t, err, "failed")
+}
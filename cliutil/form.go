@@ -0,0 +1,167 @@
+package cliutil
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Form input conventions: entering FormBack returns to the previous field and
+// entering FormSkip leaves the current field out of the result.
+const (
+	FormBack = "<"
+	FormSkip = "-"
+)
+
+// ErrFormInputEnded is returned by Form.Run when input ends before all fields are answered
+var ErrFormInputEnded = errors.New("form input ended")
+
+type formFieldKind int
+
+const (
+	formString formFieldKind = iota
+	formBool
+	formChoice
+)
+
+type formField struct {
+	label    string
+	kind     formFieldKind
+	validate ValidationFunc
+	options  []string
+}
+
+// Form is a sequence of interactive prompts, such as a setup wizard. Fields
+// are prompted in the order they were added and the answers are returned by
+// Run keyed by label.
+type Form struct {
+	fields []formField
+	in     io.Reader
+	out    io.Writer
+}
+
+// NewForm creates an empty Form that reads from stdin and writes to stdout.
+func NewForm() *Form {
+	return NewFormWithIO(os.Stdin, os.Stdout)
+}
+
+// NewFormWithIO creates an empty Form using the provided reader and writer.
+// This is useful for testing where stdin/stdout can be simulated.
+func NewFormWithIO(in io.Reader, out io.Writer) *Form {
+	return &Form{in: in, out: out}
+}
+
+// AddString adds a free-text field. If validate is non-nil, the field is
+// re-prompted until validate returns nil. The answer is stored as a string.
+func (f *Form) AddString(label string, validate ValidationFunc) *Form {
+	f.fields = append(f.fields, formField{label: label, kind: formString, validate: validate})
+	return f
+}
+
+// AddBool adds a yes/no field. The answer is stored as a bool.
+func (f *Form) AddBool(label string) *Form {
+	f.fields = append(f.fields, formField{label: label, kind: formBool})
+	return f
+}
+
+// AddChoice adds a field answered by selecting one of options by number.
+// The selected option is stored as a string.
+func (f *Form) AddChoice(label string, options []string) *Form {
+	f.fields = append(f.fields, formField{label: label, kind: formChoice, options: options})
+	return f
+}
+
+// Run prompts each field in order and returns the answers keyed by label.
+// Invalid answers are re-prompted. Entering FormBack returns to the previous
+// field (discarding its answer) and FormSkip omits the current field from the
+// result. If input ends early or cannot be read, Run returns the answers
+// collected so far along with an error wrapping both ErrFormInputEnded and the
+// read error.
+func (f *Form) Run() (map[string]any, error) {
+	reader := bufio.NewReader(f.in)
+	result := make(map[string]any, len(f.fields))
+
+	for i := 0; i < len(f.fields); {
+		field := f.fields[i]
+		input, err := f.prompt(reader, field)
+		if err != nil {
+			return result, fmt.Errorf("%w at field %q: %w", ErrFormInputEnded, field.label, err)
+		}
+
+		switch input {
+		case FormBack:
+			if i > 0 {
+				i--
+				delete(result, f.fields[i].label)
+			}
+			continue
+		case FormSkip:
+			delete(result, field.label)
+			i++
+			continue
+		}
+
+		value, err := field.parse(input)
+		if err != nil {
+			_, _ = fmt.Fprintf(f.out, "%s✗ Invalid input: %v%s\n", ColorRed, err, ColorReset)
+			continue
+		}
+		result[field.label] = value
+		i++
+	}
+
+	return result, nil
+}
+
+// prompt writes the prompt for field and reads one trimmed line of input.
+func (f *Form) prompt(reader *bufio.Reader, field formField) (string, error) {
+	switch field.kind {
+	case formBool:
+		_, _ = fmt.Fprintf(f.out, "%s [y/n]: ", field.label)
+	case formChoice:
+		_, _ = fmt.Fprintln(f.out, field.label)
+		for i, option := range field.options {
+			_, _ = fmt.Fprintf(f.out, "  %d) %s\n", i+1, option)
+		}
+		_, _ = fmt.Fprint(f.out, "Enter your choice (number): ")
+	default:
+		_, _ = fmt.Fprintf(f.out, "%s: ", field.label)
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// parse converts raw input into the field's value type.
+func (field formField) parse(input string) (any, error) {
+	switch field.kind {
+	case formBool:
+		switch strings.ToLower(input) {
+		case "y", "yes", "true", "1":
+			return true, nil
+		case "n", "no", "false", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("please enter y/yes or n/no")
+	case formChoice:
+		choice, err := strconv.Atoi(input)
+		if err != nil || choice < 1 || choice > len(field.options) {
+			return nil, fmt.Errorf("please enter a number between 1 and %d", len(field.options))
+		}
+		return field.options[choice-1], nil
+	default:
+		if field.validate != nil {
+			if err := field.validate(input); err != nil {
+				return nil, err
+			}
+		}
+		return input, nil
+	}
+}
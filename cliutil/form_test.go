@@ -0,0 +1,76 @@
+package cliutil_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/julianstephens/go-utils/cliutil"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func notEmpty(s string) error {
+	if s == "" {
+		return errors.New("value is required")
+	}
+	return nil
+}
+
+func newSetupForm(input string, out *bytes.Buffer) *cliutil.Form {
+	return cliutil.NewFormWithIO(strings.NewReader(input), out).
+		AddString("Name", notEmpty).
+		AddBool("Enable TLS").
+		AddChoice("Environment", []string{"dev", "staging", "prod"}).
+		AddString("Description", nil)
+}
+
+func TestForm_Run(t *testing.T) {
+	var out bytes.Buffer
+	// Empty name fails validation, "maybe" is not a bool, 5 is out of range
+	input := "\nmy-app\nmaybe\ny\n5\n3\nprimary service\n"
+
+	result, err := newSetupForm(input, &out).Run()
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, result, map[string]any{
+		"Name":        "my-app",
+		"Enable TLS":  true,
+		"Environment": "prod",
+		"Description": "primary service",
+	})
+
+	output := out.String()
+	tst.AssertTrue(t, strings.Contains(output, "value is required"), "validation error should be shown")
+	tst.AssertTrue(t, strings.Contains(output, "please enter y/yes or n/no"), "bool error should be shown")
+	tst.AssertTrue(t, strings.Contains(output, "  3) prod"), "choices should be listed")
+}
+
+func TestForm_BackAndSkip(t *testing.T) {
+	var out bytes.Buffer
+	// Answer name, go back and change it, skip TLS, pick dev, skip description
+	input := "first\n<\nsecond\n-\n1\n-\n"
+
+	result, err := newSetupForm(input, &out).Run()
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, result, map[string]any{
+		"Name":        "second",
+		"Environment": "dev",
+	})
+}
+
+func TestForm_InputEnded(t *testing.T) {
+	var out bytes.Buffer
+	result, err := newSetupForm("my-app\n", &out).Run()
+	tst.AssertErrorIs(t, err, cliutil.ErrFormInputEnded)
+	tst.AssertErrorContains(t, err, `"Enable TLS"`)
+	tst.AssertErrorIs(t, err, io.EOF)
+	tst.AssertDeepEqual(t, result, map[string]any{"Name": "my-app"})
+
+	// Read failures are wrapped, not replaced
+	errRead := errors.New("read failed")
+	_, err = cliutil.NewFormWithIO(iotest.ErrReader(errRead), &out).AddString("Name", nil).Run()
+	tst.AssertErrorIs(t, err, cliutil.ErrFormInputEnded)
+	tst.AssertErrorIs(t, err, errRead)
+}
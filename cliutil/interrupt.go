@@ -0,0 +1,141 @@
+package cliutil
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+)
+
+// InterruptExitCode is the exit status used after an interrupt on platforms
+// where the signal cannot be re-raised, following the shell convention of
+// 128 + SIGINT.
+const InterruptExitCode = 130
+
+// raiseFunc re-delivers a signal to the process after interrupt cleanups have
+// run. Tests replace it to observe the signal without terminating.
+var raiseFunc = raiseSignal
+
+// raiseSignal sends sig to the current process, exiting with
+// InterruptExitCode where that is not supported (e.g., on Windows).
+func raiseSignal(sig os.Signal) {
+	p, err := os.FindProcess(os.Getpid())
+	if err == nil {
+		err = p.Signal(sig)
+	}
+	if err != nil {
+		os.Exit(InterruptExitCode)
+	}
+}
+
+// widgetCleanup reports whether spinners, progress bars, and password prompts
+// register their own interrupt cleanups. See EnableInterruptCleanup.
+var widgetCleanup atomic.Bool
+
+// EnableInterruptCleanup sets whether spinners, progress bars, and password
+// prompts register interrupt cleanups with HandleInterrupt while they are
+// active. It is off by default: the handler re-raises SIGINT after running the
+// cleanups, so a program that has called signal.Notify for os.Interrupt would
+// see each Ctrl-C twice. Enable it in programs that leave SIGINT to its default
+// behavior. Cleanups registered directly with HandleInterrupt are unaffected.
+func EnableInterruptCleanup(enabled bool) {
+	widgetCleanup.Store(enabled)
+}
+
+// handleWidgetInterrupt registers cleanup with HandleInterrupt if widget
+// cleanups are enabled, returning its deregister function, or nil otherwise.
+func handleWidgetInterrupt(cleanup func()) (deregister func()) {
+	if !widgetCleanup.Load() {
+		return nil
+	}
+	return HandleInterrupt(cleanup)
+}
+
+type interruptEntry struct {
+	id      int
+	cleanup func()
+}
+
+// interrupts tracks the registered cleanups and the active signal channel.
+// The channel is only subscribed while at least one cleanup is registered, so
+// Ctrl-C keeps its default behavior the rest of the time.
+var interrupts = struct {
+	sync.Mutex
+	entries []interruptEntry
+	nextID  int
+	sigCh   chan os.Signal
+}{}
+
+// HandleInterrupt registers cleanup to run if the process receives SIGINT
+// (Ctrl-C), e.g. to restore terminal state or print a trailing newline. On
+// interrupt every registered cleanup runs, most recent first. The handler then
+// stops listening and re-raises SIGINT, so the signal takes its usual course:
+// a program without its own handler terminates as it would on any Ctrl-C, and
+// one that has called signal.Notify for os.Interrupt handles it there. Such a
+// handler sees the interrupt twice, once as delivered and once re-raised.
+//
+// The returned function deregisters cleanup; call it once the protected
+// operation completes. Spinners, progress bars, and password prompts register
+// their own cleanups while active if EnableInterruptCleanup is on.
+func HandleInterrupt(cleanup func()) (deregister func()) {
+	interrupts.Lock()
+	defer interrupts.Unlock()
+
+	if interrupts.sigCh == nil {
+		interrupts.sigCh = make(chan os.Signal, 1)
+		signal.Notify(interrupts.sigCh, os.Interrupt)
+		go watchInterrupts(interrupts.sigCh)
+	}
+
+	interrupts.nextID++
+	id := interrupts.nextID
+	interrupts.entries = append(interrupts.entries, interruptEntry{id: id, cleanup: cleanup})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { removeInterruptHandler(id) })
+	}
+}
+
+// removeInterruptHandler deregisters the cleanup with the given id and stops
+// listening for signals once none remain.
+func removeInterruptHandler(id int) {
+	interrupts.Lock()
+	defer interrupts.Unlock()
+
+	for i, e := range interrupts.entries {
+		if e.id == id {
+			interrupts.entries = append(interrupts.entries[:i], interrupts.entries[i+1:]...)
+			break
+		}
+	}
+
+	if len(interrupts.entries) == 0 && interrupts.sigCh != nil {
+		signal.Stop(interrupts.sigCh)
+		close(interrupts.sigCh)
+		interrupts.sigCh = nil
+	}
+}
+
+// watchInterrupts runs the registered cleanups and re-raises the signal when
+// one arrives on ch. It returns when ch is closed.
+func watchInterrupts(ch chan os.Signal) {
+	sig, ok := <-ch
+	if !ok {
+		return
+	}
+
+	interrupts.Lock()
+	entries := interrupts.entries
+	interrupts.entries = nil
+	signal.Stop(ch)
+	interrupts.sigCh = nil
+	interrupts.Unlock()
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].cleanup != nil {
+			entries[i].cleanup()
+		}
+	}
+	raiseFunc(sig)
+}
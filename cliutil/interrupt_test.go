@@ -0,0 +1,159 @@
+package cliutil
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// enableWidgetCleanup turns on EnableInterruptCleanup for the duration of the
+// test.
+func enableWidgetCleanup(t *testing.T) {
+	t.Helper()
+	EnableInterruptCleanup(true)
+	t.Cleanup(func() { EnableInterruptCleanup(false) })
+}
+
+// simulateInterrupt replaces raiseFunc, delivers SIGINT to the active handler,
+// and returns the signal it re-raises once the handler finishes.
+func simulateInterrupt(t *testing.T) os.Signal {
+	t.Helper()
+	raised := make(chan os.Signal, 1)
+	raiseFunc = func(sig os.Signal) { raised <- sig }
+	t.Cleanup(func() { raiseFunc = raiseSignal })
+
+	interrupts.Lock()
+	ch := interrupts.sigCh
+	interrupts.Unlock()
+	if ch == nil {
+		t.Fatal("no interrupt handler is installed")
+	}
+	ch <- os.Interrupt
+
+	select {
+	case sig := <-raised:
+		return sig
+	case <-time.After(2 * time.Second):
+		t.Fatal("interrupt handler did not re-raise the signal")
+		return nil
+	}
+}
+
+func TestHandleInterrupt_RunsCleanups(t *testing.T) {
+	var order []string
+	HandleInterrupt(func() { order = append(order, "first") })
+	HandleInterrupt(func() { order = append(order, "second") })
+
+	if sig := simulateInterrupt(t); sig != os.Interrupt {
+		t.Errorf("expected SIGINT to be re-raised, got %v", sig)
+	}
+	interrupts.Lock()
+	listening := interrupts.sigCh != nil
+	interrupts.Unlock()
+	if listening {
+		t.Error("handler should stop listening before re-raising")
+	}
+	if len(order) != 2 || order[0] != "second" || order[1] != "first" {
+		t.Errorf("expected cleanups to run most recent first, got %v", order)
+	}
+}
+
+func TestHandleInterrupt_Deregister(t *testing.T) {
+	called := false
+	deregister := HandleInterrupt(func() { called = true })
+	keep := HandleInterrupt(func() {})
+	deregister()
+	deregister() // safe to call twice
+
+	simulateInterrupt(t)
+	keep()
+	if called {
+		t.Error("deregistered cleanup should not run")
+	}
+}
+
+func TestHandleInterrupt_StopsListeningWhenEmpty(t *testing.T) {
+	deregister := HandleInterrupt(func() {})
+	deregister()
+
+	interrupts.Lock()
+	defer interrupts.Unlock()
+	if interrupts.sigCh != nil {
+		t.Error("signal channel should be released once no cleanups remain")
+	}
+}
+
+func TestSpinner_DeregistersOnStop(t *testing.T) {
+	enableWidgetCleanup(t)
+	s := NewSpinner("working")
+	s.Start()
+
+	interrupts.Lock()
+	registered := len(interrupts.entries)
+	interrupts.Unlock()
+	if registered != 1 {
+		t.Fatalf("expected spinner to register an interrupt cleanup, got %d", registered)
+	}
+
+	s.Stop()
+	interrupts.Lock()
+	defer interrupts.Unlock()
+	if len(interrupts.entries) != 0 {
+		t.Errorf("expected spinner to deregister on Stop, got %d entries", len(interrupts.entries))
+	}
+}
+
+func TestProgressBar_DeregistersOnCompletion(t *testing.T) {
+	enableWidgetCleanup(t)
+	pb := NewProgressBar(2)
+	pb.Update(1)
+
+	interrupts.Lock()
+	registered := len(interrupts.entries)
+	interrupts.Unlock()
+	if registered != 1 {
+		t.Fatalf("expected progress bar to register an interrupt cleanup, got %d", registered)
+	}
+
+	pb.Update(2)
+	interrupts.Lock()
+	registered = len(interrupts.entries)
+	interrupts.Unlock()
+	if registered != 0 {
+		t.Errorf("expected progress bar to deregister on completion, got %d entries", registered)
+	}
+
+	pb.Finish()
+	interrupts.Lock()
+	defer interrupts.Unlock()
+	if len(interrupts.entries) != 0 {
+		t.Errorf("expected Finish not to register again, got %d entries", len(interrupts.entries))
+	}
+}
+
+func TestProgressBar_DeregistersOnFinish(t *testing.T) {
+	enableWidgetCleanup(t)
+	pb := NewProgressBar(4)
+	pb.Update(1)
+	pb.Finish()
+
+	interrupts.Lock()
+	defer interrupts.Unlock()
+	if len(interrupts.entries) != 0 {
+		t.Errorf("expected Finish to deregister, got %d entries", len(interrupts.entries))
+	}
+}
+
+func TestWidgets_NoInterruptCleanupByDefault(t *testing.T) {
+	s := NewSpinner("working")
+	s.Start()
+	defer s.Stop()
+	pb := NewProgressBar(2)
+	pb.Update(1)
+
+	interrupts.Lock()
+	defer interrupts.Unlock()
+	if len(interrupts.entries) != 0 || interrupts.sigCh != nil {
+		t.Errorf("expected no interrupt handler unless enabled, got %d entries", len(interrupts.entries))
+	}
+}
@@ -0,0 +1,89 @@
+package cliutil
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// KV is a key-value pair for structured output
+type KV struct {
+	Key   string
+	Value any
+}
+
+// PrintKV prints key-value pairs to stdout, one per line, with keys
+// right-aligned so the '=' separators line up. Each line is valid logfmt.
+func PrintKV(pairs ...KV) {
+	PrintKVWithIO(os.Stdout, pairs...)
+}
+
+// PrintKVWithIO is like PrintKV but writes to the provided io.Writer.
+func PrintKVWithIO(out io.Writer, pairs ...KV) {
+	width := 0
+	for _, pair := range pairs {
+		if n := len(formatLogfmtKey(pair.Key)); n > width {
+			width = n
+		}
+	}
+
+	for _, pair := range pairs {
+		_, _ = fmt.Fprintf(out, "%*s=%s\n", width, formatLogfmtKey(pair.Key), formatLogfmtValue(pair.Value))
+	}
+}
+
+// FormatLogfmt renders key-value pairs as a single logfmt line
+// (e.g., `level=info msg="server started" port=8080`).
+func FormatLogfmt(pairs ...KV) string {
+	parts := make([]string, len(pairs))
+	for i, pair := range pairs {
+		parts[i] = formatLogfmtKey(pair.Key) + "=" + formatLogfmtValue(pair.Value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatLogfmtKey replaces characters that are not allowed in logfmt keys
+func formatLogfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == unicode.ReplacementChar {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// formatLogfmtValue renders a value, quoting it when it is empty or contains
+// whitespace, '=', '"', or control characters
+func formatLogfmtValue(value any) string {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		s = v
+	case error:
+		s = v.Error()
+	case fmt.Stringer:
+		s = v.String()
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	if s == "" {
+		return `""`
+	}
+	if strings.IndexFunc(s, needsLogfmtQuote) >= 0 {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func needsLogfmtQuote(r rune) bool {
+	return r <= ' ' || r == '=' || r == '"' || r == unicode.ReplacementChar || unicode.IsControl(r)
+}
@@ -0,0 +1,73 @@
+package cliutil_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/go-utils/cliutil"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestPrintKVWithIO(t *testing.T) {
+	var out bytes.Buffer
+	cliutil.PrintKVWithIO(&out,
+		cliutil.KV{Key: "name", Value: "my app"},
+		cliutil.KV{Key: "version", Value: "1.2.0"},
+		cliutil.KV{Key: "port", Value: 8080},
+	)
+
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	tst.AssertDeepEqual(t, lines, []string{
+		`   name="my app"`,
+		`version=1.2.0`,
+		`   port=8080`,
+	})
+
+	// Keys align: every '=' is at the same column
+	col := strings.Index(lines[0], "=")
+	for _, line := range lines {
+		tst.AssertEqual(t, strings.Index(line, "="), col)
+	}
+}
+
+func TestFormatLogfmt(t *testing.T) {
+	tests := []struct {
+		name  string
+		pairs []cliutil.KV
+		want  string
+	}{
+		{
+			name:  "simple values",
+			pairs: []cliutil.KV{{Key: "level", Value: "info"}, {Key: "count", Value: 3}},
+			want:  "level=info count=3",
+		},
+		{
+			name:  "value with space",
+			pairs: []cliutil.KV{{Key: "msg", Value: "server started"}},
+			want:  `msg="server started"`,
+		},
+		{
+			name:  "value with quote and equals",
+			pairs: []cliutil.KV{{Key: "expr", Value: `a="b"`}},
+			want:  `expr="a=\"b\""`,
+		},
+		{
+			name:  "empty and nil values",
+			pairs: []cliutil.KV{{Key: "empty", Value: ""}, {Key: "none", Value: nil}},
+			want:  `empty="" none=`,
+		},
+		{
+			name:  "error value and invalid key",
+			pairs: []cliutil.KV{{Key: "the err", Value: errors.New("not found")}},
+			want:  `the_err="not found"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tst.AssertEqual(t, cliutil.FormatLogfmt(tt.pairs...), tt.want)
+		})
+	}
+}
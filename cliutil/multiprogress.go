@@ -0,0 +1,138 @@
+package cliutil
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// multiProgressWidth is the bar width used by MultiProgress.
+const multiProgressWidth = 40
+
+// MultiProgress displays several named progress bars at once, such as one
+// per worker in a parallel task. It is safe for concurrent use. On a terminal
+// all bars are redrawn in place as a single write on every update, so lines
+// never interleave; on other writers each update that changes a bar is
+// printed as a new line.
+type MultiProgress struct {
+	mu    sync.Mutex
+	out   io.Writer
+	tty   bool
+	bars  []*multiBar
+	index map[string]*multiBar
+	drawn int
+}
+
+type multiBar struct {
+	name    string
+	current int
+	total   int
+	last    string
+}
+
+// NewMultiProgress creates a MultiProgress that writes to stdout.
+func NewMultiProgress() *MultiProgress {
+	return NewMultiProgressWithIO(os.Stdout)
+}
+
+// NewMultiProgressWithIO is like NewMultiProgress but writes to the provided
+// io.Writer. Bars are redrawn in place only if out is a terminal.
+func NewMultiProgressWithIO(out io.Writer) *MultiProgress {
+	f, ok := out.(*os.File)
+	return &MultiProgress{
+		out:   out,
+		tty:   ok && term.IsTerminal(int(f.Fd())),
+		index: make(map[string]*multiBar),
+	}
+}
+
+// Add registers a bar named name that completes at total. Bars are displayed
+// in the order they were added. Adding an existing name resets its total.
+func (mp *MultiProgress) Add(name string, total int) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	if bar, ok := mp.index[name]; ok {
+		bar.total = total
+	} else {
+		bar = &multiBar{name: name, total: total}
+		mp.bars = append(mp.bars, bar)
+		mp.index[name] = bar
+	}
+	mp.render(mp.index[name])
+}
+
+// Update sets the progress of the bar named name and redraws. Updates to
+// names that were never added are ignored.
+func (mp *MultiProgress) Update(name string, current int) {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	bar, ok := mp.index[name]
+	if !ok {
+		return
+	}
+	bar.current = current
+	mp.render(bar)
+}
+
+// Finish marks every bar as complete and draws the final state.
+func (mp *MultiProgress) Finish() {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+
+	for _, bar := range mp.bars {
+		bar.current = bar.total
+		if !mp.tty {
+			mp.render(bar)
+		}
+	}
+	if mp.tty {
+		mp.render(nil)
+	}
+}
+
+// render draws the bars after changed has been updated. It must be called
+// with mu held.
+func (mp *MultiProgress) render(changed *multiBar) {
+	width := 0
+	for _, bar := range mp.bars {
+		width = max(width, len(bar.name))
+	}
+
+	if !mp.tty {
+		line := mp.format(changed, width)
+		if line == "" || line == changed.last {
+			return
+		}
+		changed.last = line
+		_, _ = io.WriteString(mp.out, line+"\n")
+		return
+	}
+
+	// Move back to the first bar and rewrite every line in one write
+	var b strings.Builder
+	if mp.drawn > 0 {
+		fmt.Fprintf(&b, "\033[%dA", mp.drawn)
+	}
+	for _, bar := range mp.bars {
+		b.WriteString("\r\033[2K")
+		b.WriteString(mp.format(bar, width))
+		b.WriteByte('\n')
+	}
+	mp.drawn = len(mp.bars)
+	_, _ = io.WriteString(mp.out, b.String())
+}
+
+// format renders bar with its name padded to width. Bars without a positive
+// total render as an empty string.
+func (mp *MultiProgress) format(bar *multiBar, width int) string {
+	if bar.total <= 0 {
+		return ""
+	}
+	return formatProgress(fmt.Sprintf("%-*s", width, bar.name), bar.current, bar.total, multiProgressWidth)
+}
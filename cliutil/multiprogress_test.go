@@ -0,0 +1,99 @@
+package cliutil
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var multiProgressLine = regexp.MustCompile(`^(alpha|beta |gamma): \[[█░]{40}\] \d+\.\d% \(\d+/100\)$`)
+
+// updateConcurrently drives three bars from separate goroutines.
+func updateConcurrently(mp *MultiProgress) {
+	names := []string{"alpha", "beta", "gamma"}
+	for _, name := range names {
+		mp.Add(name, 100)
+	}
+
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 1; i <= 100; i++ {
+				mp.Update(name, i)
+			}
+		}()
+	}
+	wg.Wait()
+	mp.Finish()
+}
+
+func TestMultiProgress_SequentialLines(t *testing.T) {
+	var out bytes.Buffer
+	mp := NewMultiProgressWithIO(&out)
+	updateConcurrently(mp)
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	// Each bar prints once per distinct value: 0 when added, then 1 through 100
+	if len(lines) != 303 {
+		t.Errorf("expected 303 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if !multiProgressLine.MatchString(line) {
+			t.Fatalf("corrupted progress line: %q", line)
+		}
+	}
+	for _, name := range []string{"alpha", "beta ", "gamma"} {
+		want := fmt.Sprintf("%s: [%s] 100.0%% (100/100)", name, strings.Repeat("█", 40))
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output should contain completed line %q", want)
+		}
+	}
+}
+
+func TestMultiProgress_TerminalRedraw(t *testing.T) {
+	var out bytes.Buffer
+	mp := NewMultiProgressWithIO(&out)
+	mp.tty = true
+	updateConcurrently(mp)
+
+	frames := strings.Split(out.String(), "\033[3A")
+	if len(frames) < 300 {
+		t.Errorf("expected a redraw per update, got %d frames", len(frames))
+	}
+	// Skip the frames drawn while bars were still being added
+	for i, frame := range frames[1:] {
+		lines := strings.Split(strings.TrimSuffix(frame, "\n"), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("frame %d should have 3 lines, got %q", i, frame)
+		}
+		for _, line := range lines {
+			line = strings.TrimPrefix(line, "\r\033[2K")
+			if !multiProgressLine.MatchString(line) {
+				t.Fatalf("frame %d has corrupted line %q", i, line)
+			}
+		}
+	}
+
+	last := frames[len(frames)-1]
+	if strings.Count(last, "100.0% (100/100)") != 3 {
+		t.Errorf("final frame should show every bar complete, got %q", last)
+	}
+}
+
+func TestMultiProgress_IgnoresUnknownAndEmptyBars(t *testing.T) {
+	var out bytes.Buffer
+	mp := NewMultiProgressWithIO(&out)
+	mp.Update("missing", 5)
+	mp.Add("empty", 0)
+	mp.Update("empty", 1)
+	mp.Finish()
+
+	if out.Len() != 0 {
+		t.Errorf("expected no output, got %q", out.String())
+	}
+}
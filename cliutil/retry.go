@@ -0,0 +1,72 @@
+package cliutil
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// WithRetryPrompt runs action and, if it fails, prints the error and asks the
+// user whether to retry. It loops until action succeeds, the user declines, or
+// maxRetries retries have been made, and returns the last error in the latter
+// two cases. A maxRetries of 0 runs action once without prompting.
+func WithRetryPrompt(action func() error, maxRetries int) error {
+	return WithRetryPromptIO(action, maxRetries, os.Stdin, os.Stdout)
+}
+
+// WithRetryPromptIO is like WithRetryPrompt but uses the provided reader and
+// writer. This is useful for testing where stdin/stdout can be simulated.
+// Input that ends while waiting for an answer is treated as declining.
+func WithRetryPromptIO(action func() error, maxRetries int, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+
+	for attempt := 0; ; attempt++ {
+		err := action()
+		if err == nil {
+			return nil
+		}
+
+		_, _ = fmt.Fprintf(out, "%s✗ %v%s\n", ColorRed, err, ColorReset)
+		if attempt >= maxRetries {
+			return err
+		}
+
+		if !promptRetry(reader, out, maxRetries-attempt) {
+			return err
+		}
+	}
+}
+
+// promptRetry asks whether to retry. remaining is the number of retries
+// left, shown in the prompt.
+func promptRetry(reader *bufio.Reader, out io.Writer, remaining int) bool {
+	retry, err := promptYesNo(reader, out, fmt.Sprintf("Retry? (%d left) [y/n]: ", remaining))
+	if err != nil {
+		_, _ = fmt.Fprintln(out)
+		return false
+	}
+	return retry
+}
+
+// promptYesNo writes prompt and reads lines from reader until it gets a
+// yes/no answer. It returns an error if input ends first.
+func promptYesNo(reader *bufio.Reader, out io.Writer, prompt string) (bool, error) {
+	for {
+		_, _ = fmt.Fprint(out, prompt)
+		line, err := reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return false, err
+		}
+
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "y", "yes", "true", "1":
+			return true, nil
+		case "n", "no", "false", "0":
+			return false, nil
+		default:
+			_, _ = fmt.Fprintf(out, "%s! Please enter y/yes or n/no%s\n", ColorYellow, ColorReset)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package cliutil_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/go-utils/cliutil"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+var errUnavailable = errors.New("service unavailable")
+
+// failTimes returns an action that fails n times before succeeding, and a
+// pointer to the number of calls made.
+func failTimes(n int) (func() error, *int) {
+	calls := 0
+	return func() error {
+		calls++
+		if calls <= n {
+			return errUnavailable
+		}
+		return nil
+	}, &calls
+}
+
+func TestWithRetryPromptIO_RetryThenSucceed(t *testing.T) {
+	var out bytes.Buffer
+	action, calls := failTimes(1)
+
+	err := cliutil.WithRetryPromptIO(action, 3, strings.NewReader("y\n"), &out)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, *calls, 2)
+
+	output := out.String()
+	tst.AssertTrue(t, strings.Contains(output, "service unavailable"), "error should be shown")
+	tst.AssertTrue(t, strings.Contains(output, "Retry? (3 left) [y/n]: "), "retry prompt should be shown")
+}
+
+func TestWithRetryPromptIO_Declined(t *testing.T) {
+	var out bytes.Buffer
+	action, calls := failTimes(5)
+
+	// An invalid answer is re-prompted before the user declines
+	err := cliutil.WithRetryPromptIO(action, 3, strings.NewReader("maybe\nn\n"), &out)
+	tst.AssertErrorIs(t, err, errUnavailable)
+	tst.AssertEqual(t, *calls, 1)
+	tst.AssertTrue(t, strings.Contains(out.String(), "Please enter y/yes or n/no"), "invalid answer should be reported")
+}
+
+func TestWithRetryPromptIO_MaxRetries(t *testing.T) {
+	var out bytes.Buffer
+	action, calls := failTimes(5)
+
+	err := cliutil.WithRetryPromptIO(action, 2, strings.NewReader("y\ny\ny\n"), &out)
+	tst.AssertErrorIs(t, err, errUnavailable)
+	tst.AssertEqual(t, *calls, 3)
+	tst.AssertEqual(t, strings.Count(out.String(), "Retry?"), 2)
+}
+
+func TestWithRetryPromptIO_InputEnds(t *testing.T) {
+	var out bytes.Buffer
+	action, calls := failTimes(5)
+
+	err := cliutil.WithRetryPromptIO(action, 3, strings.NewReader(""), &out)
+	tst.AssertErrorIs(t, err, errUnavailable)
+	tst.AssertEqual(t, *calls, 1)
+}
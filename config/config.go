@@ -2,14 +2,20 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"gopkg.in/yaml.v3"
+
+	"github.com/julianstephens/go-utils/jsonutil"
 )
 
 // LoadFromEnv loads configuration from environment variables into the provided struct.
@@ -17,16 +23,42 @@ import (
 // Optional tags: `default:"value"` for default values and `required:"true"` for required fields.
 //
 // Supported types: string, int (all variants), uint (all variants), float32, float64, bool,
+// time.Duration (as accepted by time.ParseDuration, e.g. "30s"), time.Time (RFC 3339),
 // slices of these types, and pointers to these types.
 func LoadFromEnv(cfg interface{}) error {
-	return loadFromEnv(cfg)
+	if err := loadFromEnv(cfg); err != nil {
+		return err
+	}
+	return finishLoad(cfg)
+}
+
+// LoadFromEnvWithPrefix is like LoadFromEnv but prepends prefix and an underscore
+// to every env tag, so with prefix "APP" a field tagged `env:"DATABASE_URL"` reads
+// APP_DATABASE_URL. This lets several services share one environment without
+// colliding. A trailing underscore in prefix is not doubled. The default and
+// required tags behave exactly as in LoadFromEnv.
+func LoadFromEnvWithPrefix(cfg interface{}, prefix string) error {
+	if err := loadFromEnvWithPrefix(cfg, prefix, loadEnv); err != nil {
+		return err
+	}
+	return finishLoad(cfg)
 }
 
 // LoadFromFile loads configuration from a YAML or JSON file into the provided struct.
-// The file format is determined by the file extension (.yaml, .yml, or .json).
-// The struct should use standard json/yaml tags for field mapping.
+// The file format is determined by the file extension (.yaml, .yml, .json, .jsonc, or .toml).
+// .jsonc files may contain comments and trailing commas.
+// The struct should use standard json/yaml/toml tags for field mapping; TOML keys
+// without a toml tag match field names case-insensitively.
+//
+// If a secret provider is configured with SetSecretProvider, string values
+// prefixed with "enc:" are decrypted after loading, and structs implementing
+// PostLoader then derive their computed fields. Finally, a cfg implementing
+// Validatable has its Validate method called. This applies to all loaders.
 func LoadFromFile(cfg interface{}, filepath string) error {
-	return loadFromFile(cfg, filepath)
+	if err := loadFromFile(cfg, filepath); err != nil {
+		return err
+	}
+	return finishLoad(cfg)
 }
 
 // LoadFromFileWithEnv loads configuration from a file and then overrides with environment variables.
@@ -43,7 +75,60 @@ func LoadFromFileWithEnv(cfg interface{}, filepath string) error {
 		return fmt.Errorf("failed to override with environment variables: %w", err)
 	}
 
-	return nil
+	return finishLoad(cfg)
+}
+
+// LoadFromFiles loads each file in order into cfg, merging later files over earlier
+// ones. Merging is field by field: a nested struct such as a server block keeps the
+// fields an overlay does not mention, while scalars and slices the overlay sets
+// replace earlier values. Files may mix formats. After merging, fields still at
+// their zero value take their `default` tag, and a `required:"true"` field that
+// is still zero is an error; environment variables are not read. Every file must
+// exist; use LoadFromFilesOptional to skip missing overlays, or LoadLayered to
+// also apply environment variable overrides.
+func LoadFromFiles(cfg interface{}, paths ...string) error {
+	if err := loadFromFiles(cfg, paths, false); err != nil {
+		return err
+	}
+	if err := applyFileDefaults(cfg); err != nil {
+		return err
+	}
+	return finishLoad(cfg)
+}
+
+// LoadFromFilesOptional is like LoadFromFiles but skips files after the first that
+// do not exist, so an environment-specific overlay like config.prod.yaml can be
+// absent. The first file is still required.
+func LoadFromFilesOptional(cfg interface{}, paths ...string) error {
+	if err := loadFromFiles(cfg, paths, true); err != nil {
+		return err
+	}
+	if err := applyFileDefaults(cfg); err != nil {
+		return err
+	}
+	return finishLoad(cfg)
+}
+
+// LoadLayered loads each file in order into cfg and then overrides with environment
+// variables. Later files override values set by earlier ones, so a base file such as
+// config.yaml can be combined with an environment-specific overlay like config.prod.yaml.
+// The first file is required; overlay files that do not exist are skipped.
+//
+// An environment variable only overrides a field when it is set. The default and
+// required tags apply afterwards, to fields that neither a file nor the environment
+// set, so a value from a file is never replaced by its default.
+func LoadLayered(cfg interface{}, files ...string) error {
+	if len(files) > 0 {
+		if err := loadFromFiles(cfg, files, true); err != nil {
+			return fmt.Errorf("failed to load from file: %w", err)
+		}
+	}
+
+	if err := loadFromEnvWithPrefix(cfg, "", loadEnvOverlay); err != nil {
+		return fmt.Errorf("failed to override with environment variables: %w", err)
+	}
+
+	return finishLoad(cfg)
 }
 
 // MustLoadFromEnv is like LoadFromEnv but panics on error.
@@ -70,18 +155,64 @@ func MustLoadFromFileWithEnv(cfg interface{}, filepath string) {
 	}
 }
 
+// MustLoadLayered is like LoadLayered but panics on error.
+// Useful for application initialization where configuration errors should be fatal.
+func MustLoadLayered(cfg interface{}, files ...string) {
+	if err := LoadLayered(cfg, files...); err != nil {
+		panic(fmt.Sprintf("failed to load layered config %v: %v", files, err))
+	}
+}
+
 // loadFromEnv is the internal implementation for loading from environment variables
 func loadFromEnv(cfg interface{}) error {
+	return loadFromEnvWithPrefix(cfg, "", loadEnv)
+}
+
+// loadMode selects how processStruct sources field values.
+type loadMode int
+
+const (
+	// loadEnv sets every env-tagged field from its variable, falling back to the
+	// default tag when the variable is unset.
+	loadEnv loadMode = iota
+	// loadEnvOverlay sets env-tagged fields whose variable is set and leaves the
+	// rest alone; only fields that are still zero take their default or fail if
+	// required.
+	loadEnvOverlay
+	// loadFileDefaults reads no environment variables: fields need no env tag,
+	// and only zero-valued fields take their default or fail if required.
+	loadFileDefaults
+)
+
+// loadFromEnvWithPrefix loads from environment variables named prefix + "_" + tag
+func loadFromEnvWithPrefix(cfg interface{}, prefix string, mode loadMode) error {
 	v := reflect.ValueOf(cfg)
 	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
 		return fmt.Errorf("config must be a pointer to a struct")
 	}
 
-	return processStruct(v.Elem())
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+	return processStruct(v.Elem(), prefix, false, mode)
 }
 
-// processStruct recursively processes struct fields for environment variable loading
-func processStruct(v reflect.Value) error {
+// applyFileDefaults applies default and required tags to cfg after it has been
+// loaded from files, without reading the environment.
+func applyFileDefaults(cfg interface{}) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config must be a pointer to a struct")
+	}
+	return processStruct(v.Elem(), "", false, loadFileDefaults)
+}
+
+// processStruct recursively processes struct fields for environment variable loading.
+// prefix is prepended to each env tag: it is the caller's prefix, if any, and for
+// elements of a struct slice also the element path (e.g., "UPSTREAMS_0_"). default
+// and required tags are ignored for slice elements. mode selects where values come
+// from and when defaults apply.
+func processStruct(v reflect.Value, prefix string, element bool, mode loadMode) error {
 	t := v.Type()
 
 	for i := 0; i < v.NumField(); i++ {
@@ -93,27 +224,49 @@ func processStruct(v reflect.Value) error {
 			continue
 		}
 
-		// Handle nested structs
-		if field.Kind() == reflect.Struct {
-			if err := processStruct(field); err != nil {
+		// Handle nested structs; time.Time is parsed as a value instead
+		if field.Kind() == reflect.Struct && field.Type() != timeType {
+			if err := processStruct(field, prefix, element, mode); err != nil {
 				return err
 			}
 			continue
 		}
 
-		envTag := fieldType.Tag.Get("env")
-		if envTag == "" {
-			continue
+		defaultVal := ""
+		required := false
+		if !element {
+			defaultVal = fieldType.Tag.Get("default")
+			required = fieldType.Tag.Get("required") == "true"
 		}
 
-		defaultVal := fieldType.Tag.Get("default")
-		required := fieldType.Tag.Get("required") == "true"
+		var envTag, envVal, source string
+		if mode != loadFileDefaults {
+			envTag = fieldType.Tag.Get("env")
+			if envTag == "" {
+				continue
+			}
+			envTag = prefix + envTag
+			source = fmt.Sprintf(" (env: %s)", envTag)
+
+			// Handle slices of structs, addressed by index (e.g., UPSTREAMS_0_HOST)
+			if isStructSlice(field.Type()) {
+				if err := processStructSlice(field, envTag, fieldType.Name); err != nil {
+					return err
+				}
+				continue
+			}
 
-		envVal := os.Getenv(envTag)
+			envVal = os.Getenv(envTag)
+			if envVal == "" && mode == loadEnvOverlay && !field.IsZero() {
+				continue
+			}
+		} else if !field.IsZero() {
+			continue
+		}
 
 		// Handle required fields
 		if required && envVal == "" {
-			return fmt.Errorf("required field '%s' (env: %s) is missing or empty", fieldType.Name, envTag)
+			return fmt.Errorf("required field '%s'%s is missing or empty", fieldType.Name, source)
 		}
 
 		// Use default value if env var is not set
@@ -128,7 +281,7 @@ func processStruct(v reflect.Value) error {
 
 		// Set the field value
 		if err := setFieldValue(field, envVal, fieldType.Name); err != nil {
-			return fmt.Errorf("failed to set field '%s' (env: %s): %w", fieldType.Name, envTag, err)
+			return fmt.Errorf("failed to set field '%s'%s: %w", fieldType.Name, source, err)
 		}
 	}
 
@@ -152,13 +305,142 @@ func loadFromFile(cfg interface{}, filePath string) error {
 		if err := json.Unmarshal(data, cfg); err != nil {
 			return fmt.Errorf("failed to parse JSON config file '%s': %w", filePath, err)
 		}
+	case ".jsonc":
+		if err := jsonutil.UnmarshalJSONC(data, cfg); err != nil {
+			return fmt.Errorf("failed to parse JSONC config file '%s': %w", filePath, err)
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, cfg); err != nil {
+			return fmt.Errorf("failed to parse TOML config file '%s': %w", filePath, err)
+		}
 	default:
-		return fmt.Errorf("unsupported config file format '%s', supported formats: .yaml, .yml, .json", ext)
+		return fmt.Errorf(
+			"unsupported config file format '%s', supported formats: .yaml, .yml, .json, .jsonc, .toml",
+			ext,
+		)
 	}
 
 	return nil
 }
 
+// loadFromFiles decodes each file in order into cfg. Decoding into the same struct
+// leaves fields absent from a later file untouched, which merges nested structs
+// field by field. When skipMissing is set, files after the first that do not exist
+// are skipped.
+func loadFromFiles(cfg interface{}, paths []string, skipMissing bool) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("no config files given")
+	}
+	for i, path := range paths {
+		if skipMissing && i > 0 {
+			if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+		}
+		if err := loadFromFile(cfg, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isStructSlice reports whether t is a slice of structs or of pointers to structs
+func isStructSlice(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+	elem := t.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return elem.Kind() == reflect.Struct && elem != timeType
+}
+
+// MaxStructSliceIndex is the highest struct slice element index that an
+// environment variable may address (e.g., UPSTREAMS_1023_HOST). It bounds the
+// memory a single variable can make the loader allocate.
+const MaxStructSliceIndex = 1023
+
+// processStructSlice applies environment overrides to the elements of a struct
+// slice. Element i reads its fields from variables named "<name>_<i>_<TAG>".
+// The slice is grown when variables address indexes past its end; every new
+// index must be addressed so no element is silently left zero.
+func processStructSlice(field reflect.Value, name, fieldName string) error {
+	prefix := name + "_"
+
+	// Collect the indexes addressed by environment variables
+	maxIndex := -1
+	addressed := make(map[int]bool)
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		rest, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+		indexStr, _, ok := strings.Cut(rest, "_")
+		if !ok || !isSliceIndex(indexStr) {
+			continue
+		}
+		index, err := strconv.Atoi(indexStr)
+		if err != nil || index < 0 || index > MaxStructSliceIndex {
+			return fmt.Errorf("failed to set field '%s' (env: %s): index %s is out of range 0-%d",
+				fieldName, key, indexStr, MaxStructSliceIndex)
+		}
+		addressed[index] = true
+		if index > maxIndex {
+			maxIndex = index
+		}
+	}
+
+	for i := field.Len(); i <= maxIndex; i++ {
+		if !addressed[i] {
+			return fmt.Errorf("failed to set field '%s' (env: %s%d_...): index %d is missing; "+
+				"new elements must be added without gaps after the existing %d",
+				fieldName, prefix, maxIndex, i, field.Len())
+		}
+	}
+
+	if maxIndex >= field.Len() {
+		grown := reflect.MakeSlice(field.Type(), maxIndex+1, maxIndex+1)
+		reflect.Copy(grown, field)
+		field.Set(grown)
+	}
+
+	for i := 0; i < field.Len(); i++ {
+		elem := field.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				elem.Set(reflect.New(elem.Type().Elem()))
+			}
+			elem = elem.Elem()
+		}
+		if err := processStruct(elem, fmt.Sprintf("%s%d_", prefix, i), true, loadEnv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// isSliceIndex reports whether s looks like an element index: digits,
+// optionally preceded by a minus sign so negative indexes can be rejected.
+func isSliceIndex(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // setFieldValue sets a struct field value from a string representation
 func setFieldValue(field reflect.Value, value string, fieldName string) error {
 	// Handle pointers
@@ -170,6 +452,25 @@ func setFieldValue(field reflect.Value, value string, fieldName string) error {
 		field = field.Elem()
 	}
 
+	// Check types with dedicated parsers before their underlying kinds
+	switch field.Type() {
+	case durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration value '%s': %w", value, err)
+		}
+		field.SetInt(int64(d))
+		return nil
+
+	case timeType:
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("invalid time value '%s': %w", value, err)
+		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
 	switch field.Kind() {
 	case reflect.String:
 		field.SetString(value)
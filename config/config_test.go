@@ -1,9 +1,11 @@
 package config_test
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/julianstephens/go-utils/config"
 	tst "github.com/julianstephens/go-utils/tests"
@@ -38,17 +40,17 @@ type ComplexConfig struct {
 
 type FileConfig struct {
 	Server struct {
-		Host string `yaml:"host" json:"host"`
-		Port int    `yaml:"port" json:"port"`
-	} `yaml:"server"   json:"server"`
+		Host string `yaml:"host" json:"host" toml:"host"`
+		Port int    `yaml:"port" json:"port" toml:"port"`
+	} `yaml:"server"   json:"server"   toml:"server"`
 	Database struct {
-		URL      string `yaml:"url" json:"url"`
-		MaxConns int    `yaml:"max_conns" json:"max_conns"`
-	} `yaml:"database" json:"database"`
+		URL      string `yaml:"url" json:"url" toml:"url"`
+		MaxConns int    `yaml:"max_conns" json:"max_conns" toml:"max_conns"`
+	} `yaml:"database" json:"database" toml:"database"`
 	Features struct {
-		EnableMetrics bool `yaml:"enable_metrics" json:"enable_metrics"`
-		EnableTracing bool `yaml:"enable_tracing" json:"enable_tracing"`
-	} `yaml:"features" json:"features"`
+		EnableMetrics bool `yaml:"enable_metrics" json:"enable_metrics" toml:"enable_metrics"`
+		EnableTracing bool `yaml:"enable_tracing" json:"enable_tracing" toml:"enable_tracing"`
+	} `yaml:"features" json:"features" toml:"features"`
 }
 
 func TestLoadFromEnv(t *testing.T) {
@@ -113,6 +115,56 @@ func TestLoadFromEnv(t *testing.T) {
 	})
 }
 
+func TestLoadFromEnvWithPrefix(t *testing.T) {
+	t.Run("prefixed variables", func(t *testing.T) {
+		t.Setenv("APP_DATABASE_URL", "postgres://app/db")
+		t.Setenv("APP_PORT", "9000")
+		t.Setenv("DATABASE_URL", "postgres://other/db")
+		t.Setenv("HOST", "unprefixed-host")
+
+		var cfg BasicConfig
+		err := config.LoadFromEnvWithPrefix(&cfg, "APP")
+		tst.AssertNoError(t, err)
+		tst.AssertTrue(t, cfg.Database == "postgres://app/db", "Database should come from APP_DATABASE_URL")
+		tst.AssertTrue(t, cfg.Port == 9000, "Port should come from APP_PORT")
+		tst.AssertTrue(t, cfg.Host == "localhost", "unprefixed HOST should be ignored in favor of the default")
+	})
+
+	t.Run("trailing underscore", func(t *testing.T) {
+		t.Setenv("APP_DATABASE_URL", "postgres://app/db")
+
+		var cfg BasicConfig
+		err := config.LoadFromEnvWithPrefix(&cfg, "APP_")
+		tst.AssertNoError(t, err)
+		tst.AssertTrue(t, cfg.Database == "postgres://app/db", "Database should come from APP_DATABASE_URL")
+	})
+
+	t.Run("required field missing", func(t *testing.T) {
+		t.Setenv("DATABASE_URL", "postgres://other/db")
+
+		var cfg BasicConfig
+		err := config.LoadFromEnvWithPrefix(&cfg, "APP")
+		tst.AssertNotNil(t, err, "expected error for missing required field")
+		tst.AssertTrue(
+			t,
+			err.Error() == "required field 'Database' (env: APP_DATABASE_URL) is missing or empty",
+			"error message should name the prefixed variable",
+		)
+	})
+
+	t.Run("struct slice elements", func(t *testing.T) {
+		t.Setenv("APP_UPSTREAMS_0_HOST", "10.0.0.9")
+		t.Setenv("APP_UPSTREAMS_0_PORT", "8443")
+
+		var cfg UpstreamConfig
+		err := config.LoadFromEnvWithPrefix(&cfg, "APP")
+		tst.AssertNoError(t, err)
+		tst.AssertTrue(t, len(cfg.Upstreams) == 1, "should load one upstream")
+		tst.AssertTrue(t, cfg.Upstreams[0].Host == "10.0.0.9", "Upstreams[0].Host should match")
+		tst.AssertTrue(t, cfg.Upstreams[0].Port == 8443, "Upstreams[0].Port should match")
+	})
+}
+
 func TestComplexTypes(t *testing.T) {
 	// Clean environment
 	cleanEnv := func() {
@@ -181,6 +233,67 @@ func TestComplexTypes(t *testing.T) {
 	})
 }
 
+type TimeConfig struct {
+	Timeout   time.Duration   `env:"TC_TIMEOUT"   default:"30s"`
+	Interval  *time.Duration  `env:"TC_INTERVAL"`
+	Backoffs  []time.Duration `env:"TC_BACKOFFS"  default:"100ms,1s,1m"`
+	StartsAt  time.Time       `env:"TC_STARTS_AT" default:"2024-01-02T15:04:05Z"`
+	EndsAt    *time.Time      `env:"TC_ENDS_AT"`
+	Blackouts []time.Time     `env:"TC_BLACKOUTS"`
+	Nested    struct {
+		TTL time.Duration `env:"TC_TTL"`
+	}
+}
+
+func TestTimeTypes(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		var cfg TimeConfig
+		err := config.LoadFromEnv(&cfg)
+		tst.AssertNoError(t, err)
+		tst.AssertTrue(t, cfg.Timeout == 30*time.Second, "Timeout should default to 30s")
+		tst.AssertNil(t, cfg.Interval, "Interval should be nil")
+		tst.AssertDeepEqual(t, cfg.Backoffs, []time.Duration{100 * time.Millisecond, time.Second, time.Minute})
+		tst.AssertTrue(t, cfg.StartsAt.Equal(time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)), "StartsAt should match default")
+		tst.AssertNil(t, cfg.EndsAt, "EndsAt should be nil")
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		t.Setenv("TC_TIMEOUT", "1m30s")
+		t.Setenv("TC_INTERVAL", "250ms")
+		t.Setenv("TC_ENDS_AT", "2024-06-30T23:59:59+02:00")
+		t.Setenv("TC_BLACKOUTS", "2024-12-24T00:00:00Z, 2024-12-31T00:00:00Z")
+		t.Setenv("TC_TTL", "2h")
+
+		var cfg TimeConfig
+		err := config.LoadFromEnv(&cfg)
+		tst.AssertNoError(t, err)
+		tst.AssertTrue(t, cfg.Timeout == 90*time.Second, "Timeout should be overridden")
+		tst.AssertNotNil(t, cfg.Interval, "Interval should not be nil")
+		tst.AssertTrue(t, *cfg.Interval == 250*time.Millisecond, "Interval should match")
+		tst.AssertNotNil(t, cfg.EndsAt, "EndsAt should not be nil")
+		tst.AssertTrue(t, cfg.EndsAt.Equal(time.Date(2024, 6, 30, 21, 59, 59, 0, time.UTC)), "EndsAt should match")
+		tst.AssertTrue(t, len(cfg.Blackouts) == 2, "Blackouts should have two entries")
+		tst.AssertTrue(t, cfg.Blackouts[1].Equal(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)), "Blackouts[1] should match")
+		tst.AssertTrue(t, cfg.Nested.TTL == 2*time.Hour, "Nested.TTL should match")
+	})
+
+	t.Run("invalid duration", func(t *testing.T) {
+		t.Setenv("TC_TIMEOUT", "30")
+
+		var cfg TimeConfig
+		err := config.LoadFromEnv(&cfg)
+		tst.AssertErrorContains(t, err, "failed to set field 'Timeout' (env: TC_TIMEOUT): invalid duration value '30'")
+	})
+
+	t.Run("invalid time", func(t *testing.T) {
+		t.Setenv("TC_ENDS_AT", "2024-06-30")
+
+		var cfg TimeConfig
+		err := config.LoadFromEnv(&cfg)
+		tst.AssertErrorContains(t, err, "failed to set field 'EndsAt' (env: TC_ENDS_AT): invalid time value '2024-06-30'")
+	})
+}
+
 func TestLoadFromFile(t *testing.T) {
 	// Create temporary directory for test files
 	tempDir := t.TempDir()
@@ -244,6 +357,95 @@ features:
 		tst.AssertTrue(t, cfg.Features.EnableTracing, "EnableTracing should be true")
 	})
 
+	t.Run("load from JSONC file", func(t *testing.T) {
+		jsoncContent := `{
+  // server settings
+  "server": {
+    "host": "0.0.0.0",
+    "port": 8080, /* default port */
+  },
+  "database": {
+    "url": "postgres://localhost/jsonc_test",
+    "max_conns": 25,
+  },
+}`
+		jsoncFile := filepath.Join(tempDir, "config.jsonc")
+		if err := os.WriteFile(jsoncFile, []byte(jsoncContent), 0644); err != nil {
+			t.Fatalf("Failed to create JSONC test file: %v", err)
+		}
+
+		var cfg FileConfig
+		err := config.LoadFromFile(&cfg, jsoncFile)
+		tst.AssertNoError(t, err)
+		tst.AssertTrue(t, cfg.Server.Port == 8080, "Server.Port should match")
+		tst.AssertTrue(t, cfg.Database.URL == "postgres://localhost/jsonc_test", "Database.URL should match")
+	})
+
+	t.Run("TOML file", func(t *testing.T) {
+		tomlContent := `
+# server settings
+[server]
+host = "10.0.0.1"
+port = 7000
+
+[database]
+url = "postgres://localhost/toml_test"
+max_conns = 30
+
+[features]
+enable_metrics = true
+enable_tracing = true
+`
+		tomlFile := filepath.Join(tempDir, "config.toml")
+		if err := os.WriteFile(tomlFile, []byte(tomlContent), 0644); err != nil {
+			t.Fatalf("Failed to create TOML test file: %v", err)
+		}
+
+		var cfg FileConfig
+		err := config.LoadFromFile(&cfg, tomlFile)
+		tst.AssertNoError(t, err)
+		tst.AssertTrue(t, cfg.Server.Host == "10.0.0.1", "Server.Host should match")
+		tst.AssertTrue(t, cfg.Server.Port == 7000, "Server.Port should match")
+		tst.AssertTrue(t, cfg.Database.URL == "postgres://localhost/toml_test", "Database.URL should match")
+		tst.AssertTrue(t, cfg.Database.MaxConns == 30, "Database.MaxConns should match")
+		tst.AssertTrue(t, cfg.Features.EnableMetrics, "EnableMetrics should be true")
+		tst.AssertTrue(t, cfg.Features.EnableTracing, "EnableTracing should be true")
+	})
+
+	t.Run("TOML file without tags", func(t *testing.T) {
+		type untagged struct {
+			Name  string
+			Limit int
+			Inner struct {
+				Enabled bool
+			}
+		}
+		tomlFile := filepath.Join(tempDir, "untagged.toml")
+		content := "name = \"svc\"\nlimit = 5\n\n[inner]\nenabled = true\n"
+		if err := os.WriteFile(tomlFile, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create TOML test file: %v", err)
+		}
+
+		var cfg untagged
+		err := config.LoadFromFile(&cfg, tomlFile)
+		tst.AssertNoError(t, err)
+		tst.AssertTrue(t, cfg.Name == "svc", "Name should match")
+		tst.AssertTrue(t, cfg.Limit == 5, "Limit should match")
+		tst.AssertTrue(t, cfg.Inner.Enabled, "Inner.Enabled should be true")
+	})
+
+	t.Run("invalid TOML file", func(t *testing.T) {
+		tomlFile := filepath.Join(tempDir, "invalid.toml")
+		if err := os.WriteFile(tomlFile, []byte("[server\nport = 1"), 0644); err != nil {
+			t.Fatalf("Failed to create TOML test file: %v", err)
+		}
+
+		var cfg FileConfig
+		err := config.LoadFromFile(&cfg, tomlFile)
+		tst.AssertNotNil(t, err, "expected error for invalid TOML")
+		tst.AssertErrorContains(t, err, "failed to parse TOML config file")
+	})
+
 	t.Run("unsupported file format", func(t *testing.T) {
 		unsupportedFile := filepath.Join(tempDir, "config.xml")
 		if err := os.WriteFile(unsupportedFile, []byte("<config></config>"), 0644); err != nil {
@@ -255,7 +457,7 @@ features:
 		tst.AssertNotNil(t, err, "expected error for unsupported file format")
 		tst.AssertTrue(
 			t,
-			err.Error() == "unsupported config file format '.xml', supported formats: .yaml, .yml, .json",
+			err.Error() == "unsupported config file format '.xml', supported formats: .yaml, .yml, .json, .jsonc, .toml",
 			"error message should match",
 		)
 	})
@@ -321,6 +523,302 @@ features:
 	})
 }
 
+type UpstreamConfig struct {
+	Upstreams []struct {
+		Host   string `yaml:"host"   json:"host"   env:"HOST"`
+		Port   int    `yaml:"port"   json:"port"   env:"PORT"`
+		Weight int    `yaml:"weight" json:"weight" env:"WEIGHT"`
+	} `yaml:"upstreams" json:"upstreams" env:"UPSTREAMS"`
+}
+
+func TestLoadStructSlices(t *testing.T) {
+	tempDir := t.TempDir()
+
+	yamlContent := `
+upstreams:
+  - host: "10.0.0.1"
+    port: 8080
+    weight: 1
+  - host: "10.0.0.2"
+    port: 8081
+    weight: 2
+`
+	yamlFile := filepath.Join(tempDir, "upstreams.yaml")
+	if err := os.WriteFile(yamlFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to create YAML test file: %v", err)
+	}
+
+	t.Run("load from file", func(t *testing.T) {
+		var cfg UpstreamConfig
+		err := config.LoadFromFile(&cfg, yamlFile)
+		tst.AssertNoError(t, err)
+		tst.AssertTrue(t, len(cfg.Upstreams) == 2, "should load two upstreams")
+		tst.AssertTrue(t, cfg.Upstreams[1].Host == "10.0.0.2", "Upstreams[1].Host should match file")
+		tst.AssertTrue(t, cfg.Upstreams[1].Weight == 2, "Upstreams[1].Weight should match file")
+	})
+
+	t.Run("override indexed element via env", func(t *testing.T) {
+		t.Setenv("UPSTREAMS_1_HOST", "env-host")
+		t.Setenv("UPSTREAMS_1_PORT", "9090")
+
+		var cfg UpstreamConfig
+		err := config.LoadFromFileWithEnv(&cfg, yamlFile)
+		tst.AssertNoError(t, err)
+		tst.AssertTrue(t, cfg.Upstreams[1].Host == "env-host", "Upstreams[1].Host should be overridden by env")
+		tst.AssertTrue(t, cfg.Upstreams[1].Port == 9090, "Upstreams[1].Port should be overridden by env")
+		tst.AssertTrue(t, cfg.Upstreams[1].Weight == 2, "Upstreams[1].Weight should keep the file value")
+		tst.AssertTrue(t, cfg.Upstreams[0].Host == "10.0.0.1", "Upstreams[0] should be unchanged")
+	})
+
+	t.Run("env index past end grows slice", func(t *testing.T) {
+		t.Setenv("UPSTREAMS_2_HOST", "10.0.0.3")
+
+		var cfg UpstreamConfig
+		err := config.LoadFromFileWithEnv(&cfg, yamlFile)
+		tst.AssertNoError(t, err)
+		tst.AssertTrue(t, len(cfg.Upstreams) == 3, "slice should grow to three elements")
+		tst.AssertTrue(t, cfg.Upstreams[2].Host == "10.0.0.3", "Upstreams[2].Host should come from env")
+	})
+
+	t.Run("invalid element value", func(t *testing.T) {
+		t.Setenv("UPSTREAMS_0_PORT", "not-a-number")
+
+		var cfg UpstreamConfig
+		err := config.LoadFromFileWithEnv(&cfg, yamlFile)
+		tst.AssertErrorContains(t, err, "UPSTREAMS_0_PORT")
+	})
+
+	t.Run("index above maximum", func(t *testing.T) {
+		t.Setenv("UPSTREAMS_2000000000_HOST", "x")
+
+		var cfg UpstreamConfig
+		err := config.LoadFromFileWithEnv(&cfg, yamlFile)
+		tst.AssertErrorContains(t, err, "failed to set field 'Upstreams' (env: UPSTREAMS_2000000000_HOST)")
+		tst.AssertErrorContains(t, err, "out of range")
+	})
+
+	t.Run("negative index", func(t *testing.T) {
+		t.Setenv("UPSTREAMS_-1_HOST", "x")
+
+		var cfg UpstreamConfig
+		err := config.LoadFromFileWithEnv(&cfg, yamlFile)
+		tst.AssertErrorContains(t, err, "failed to set field 'Upstreams' (env: UPSTREAMS_-1_HOST)")
+	})
+
+	t.Run("sparse index", func(t *testing.T) {
+		t.Setenv("UPSTREAMS_4_HOST", "10.0.0.5")
+
+		var cfg UpstreamConfig
+		err := config.LoadFromFileWithEnv(&cfg, yamlFile)
+		tst.AssertErrorContains(t, err, "failed to set field 'Upstreams'")
+		tst.AssertErrorContains(t, err, "index 2 is missing")
+	})
+
+	t.Run("index at maximum", func(t *testing.T) {
+		var cfg UpstreamConfig
+		for i := 0; i <= config.MaxStructSliceIndex; i++ {
+			t.Setenv(fmt.Sprintf("UPSTREAMS_%d_PORT", i), "80")
+		}
+		err := config.LoadFromEnv(&cfg)
+		tst.AssertNoError(t, err)
+		tst.AssertEqual(t, len(cfg.Upstreams), config.MaxStructSliceIndex+1)
+	})
+}
+
+func TestLoadLayered(t *testing.T) {
+	tempDir := t.TempDir()
+
+	base := filepath.Join(tempDir, "config.yaml")
+	baseContent := `
+server:
+  host: "base-host"
+  port: 3000
+database:
+  url: "postgres://base-db/app"
+`
+	if err := os.WriteFile(base, []byte(baseContent), 0644); err != nil {
+		t.Fatalf("Failed to create base config: %v", err)
+	}
+
+	overlay := filepath.Join(tempDir, "config.prod.yaml")
+	overlayContent := `
+server:
+  host: "prod-host"
+`
+	if err := os.WriteFile(overlay, []byte(overlayContent), 0644); err != nil {
+		t.Fatalf("Failed to create overlay config: %v", err)
+	}
+
+	type LayeredConfig struct {
+		Server struct {
+			Host string `yaml:"host" env:"LAYERED_SERVER_HOST"`
+			Port int    `yaml:"port" env:"LAYERED_SERVER_PORT"`
+		} `yaml:"server"`
+		Database struct {
+			URL string `yaml:"url" env:"LAYERED_DATABASE_URL"`
+		} `yaml:"database"`
+	}
+
+	t.Run("overlay overrides base", func(t *testing.T) {
+		var cfg LayeredConfig
+		err := config.LoadLayered(&cfg, base, overlay)
+		tst.AssertNoError(t, err)
+		tst.AssertTrue(t, cfg.Server.Host == "prod-host", "Server.Host should come from the overlay")
+		tst.AssertTrue(t, cfg.Server.Port == 3000, "Server.Port should keep the base value")
+		tst.AssertTrue(t, cfg.Database.URL == "postgres://base-db/app", "Database.URL should keep the base value")
+	})
+
+	t.Run("env overrides overlay", func(t *testing.T) {
+		t.Setenv("LAYERED_SERVER_HOST", "env-host")
+
+		var cfg LayeredConfig
+		err := config.LoadLayered(&cfg, base, overlay)
+		tst.AssertNoError(t, err)
+		tst.AssertTrue(t, cfg.Server.Host == "env-host", "Server.Host should be overridden by env")
+		tst.AssertTrue(t, cfg.Server.Port == 3000, "Server.Port should keep the base value")
+	})
+
+	t.Run("file values beat default and required tags", func(t *testing.T) {
+		type TaggedConfig struct {
+			Server struct {
+				Host string `yaml:"host" env:"LAYERED_SERVER_HOST" default:"0.0.0.0"`
+				Port int    `yaml:"port" env:"LAYERED_SERVER_PORT" default:"8080"`
+			} `yaml:"server"`
+			Database struct {
+				URL string `yaml:"url" env:"LAYERED_DATABASE_URL" required:"true"`
+			} `yaml:"database"`
+			Workers int `env:"LAYERED_WORKERS" default:"4"`
+		}
+
+		var cfg TaggedConfig
+		err := config.LoadLayered(&cfg, base, overlay)
+		tst.AssertNoError(t, err)
+		tst.AssertEqual(t, cfg.Server.Host, "prod-host")
+		tst.AssertEqual(t, cfg.Server.Port, 3000)
+		tst.AssertEqual(t, cfg.Database.URL, "postgres://base-db/app")
+		tst.AssertEqual(t, cfg.Workers, 4)
+
+		// A required field set nowhere is still an error
+		var missing TaggedConfig
+		err = config.LoadLayered(&missing, overlay)
+		tst.AssertErrorContains(t, err, "required field 'URL' (env: LAYERED_DATABASE_URL) is missing or empty")
+	})
+
+	t.Run("missing overlay is skipped", func(t *testing.T) {
+		var cfg LayeredConfig
+		err := config.LoadLayered(&cfg, base, filepath.Join(tempDir, "config.staging.yaml"))
+		tst.AssertNoError(t, err)
+		tst.AssertTrue(t, cfg.Server.Host == "base-host", "Server.Host should keep the base value")
+	})
+
+	t.Run("missing base is an error", func(t *testing.T) {
+		var cfg LayeredConfig
+		err := config.LoadLayered(&cfg, filepath.Join(tempDir, "missing.yaml"), overlay)
+		tst.AssertErrorContains(t, err, "missing.yaml")
+	})
+}
+
+func TestLoadFromFiles(t *testing.T) {
+	tempDir := t.TempDir()
+
+	base := filepath.Join(tempDir, "config.yaml")
+	baseContent := `
+server:
+  host: "base-host"
+  port: 3000
+database:
+  url: "postgres://base-db/app"
+  max_conns: 10
+features:
+  enable_metrics: true
+`
+	if err := os.WriteFile(base, []byte(baseContent), 0644); err != nil {
+		t.Fatalf("Failed to create base config: %v", err)
+	}
+
+	overlay := filepath.Join(tempDir, "config.prod.yaml")
+	overlayContent := `
+server:
+  port: 443
+database:
+  max_conns: 50
+`
+	if err := os.WriteFile(overlay, []byte(overlayContent), 0644); err != nil {
+		t.Fatalf("Failed to create overlay config: %v", err)
+	}
+
+	local := filepath.Join(tempDir, "config.local.json")
+	if err := os.WriteFile(local, []byte(`{"server": {"host": "localhost"}}`), 0644); err != nil {
+		t.Fatalf("Failed to create local config: %v", err)
+	}
+
+	t.Run("nested structs merge field by field", func(t *testing.T) {
+		var cfg FileConfig
+		err := config.LoadFromFiles(&cfg, base, overlay, local)
+		tst.AssertNoError(t, err)
+		tst.AssertTrue(t, cfg.Server.Host == "localhost", "Server.Host should come from the last file")
+		tst.AssertTrue(t, cfg.Server.Port == 443, "Server.Port should come from the overlay")
+		tst.AssertTrue(t, cfg.Database.URL == "postgres://base-db/app", "Database.URL should keep the base value")
+		tst.AssertTrue(t, cfg.Database.MaxConns == 50, "Database.MaxConns should come from the overlay")
+		tst.AssertTrue(t, cfg.Features.EnableMetrics, "Features.EnableMetrics should keep the base value")
+	})
+
+	t.Run("missing overlay is an error", func(t *testing.T) {
+		var cfg FileConfig
+		err := config.LoadFromFiles(&cfg, base, filepath.Join(tempDir, "config.staging.yaml"))
+		tst.AssertErrorContains(t, err, "config.staging.yaml")
+	})
+
+	t.Run("optional skips missing overlay", func(t *testing.T) {
+		var cfg FileConfig
+		err := config.LoadFromFilesOptional(&cfg, base, filepath.Join(tempDir, "config.staging.yaml"), overlay)
+		tst.AssertNoError(t, err)
+		tst.AssertTrue(t, cfg.Server.Host == "base-host", "Server.Host should keep the base value")
+		tst.AssertTrue(t, cfg.Server.Port == 443, "Server.Port should come from the overlay")
+	})
+
+	t.Run("optional still requires the base", func(t *testing.T) {
+		var cfg FileConfig
+		err := config.LoadFromFilesOptional(&cfg, filepath.Join(tempDir, "missing.yaml"), overlay)
+		tst.AssertErrorContains(t, err, "missing.yaml")
+	})
+
+	t.Run("no files is an error", func(t *testing.T) {
+		var cfg FileConfig
+		tst.AssertNotNil(t, config.LoadFromFiles(&cfg), "expected error when no files are given")
+	})
+
+	type taggedConfig struct {
+		Server struct {
+			Host    string        `yaml:"host"    default:"0.0.0.0"`
+			Port    int           `yaml:"port"    default:"8080"`
+			Timeout time.Duration `yaml:"timeout" default:"30s"`
+		} `yaml:"server"`
+		Database struct {
+			URL string `yaml:"url" env:"TAGGED_DATABASE_URL" required:"true"`
+		} `yaml:"database"`
+	}
+
+	t.Run("defaults fill fields no file sets", func(t *testing.T) {
+		t.Setenv("TAGGED_DATABASE_URL", "postgres://env-db/app")
+		var cfg taggedConfig
+		err := config.LoadFromFilesOptional(&cfg, base, filepath.Join(tempDir, "config.staging.yaml"), overlay)
+		tst.AssertNoError(t, err)
+		tst.AssertEqual(t, cfg.Server.Host, "base-host")
+		tst.AssertEqual(t, cfg.Server.Port, 443)
+		tst.AssertEqual(t, cfg.Server.Timeout, 30*time.Second)
+		tst.AssertEqual(t, cfg.Database.URL, "postgres://base-db/app")
+	})
+
+	t.Run("required field missing from every file", func(t *testing.T) {
+		// The environment is not consulted when loading from files
+		t.Setenv("TAGGED_DATABASE_URL", "postgres://env-db/app")
+		var cfg taggedConfig
+		err := config.LoadFromFiles(&cfg, overlay)
+		tst.AssertErrorContains(t, err, "required field 'URL' is missing or empty")
+	})
+}
+
 func TestMustFunctions(t *testing.T) {
 	t.Run("MustLoadFromEnv panics on error", func(t *testing.T) {
 		defer func() {
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RedactedValue replaces the value of fields tagged `secret:"true"` or
+// `redact:"true"` in Dump output.
+const RedactedValue = "***"
+
+// Dump renders cfg as key=value lines, one per leaf field, for logging the
+// effective configuration at startup. Keys are dotted field paths such as
+// Server.Port, with [i] for slice elements and [key] for map entries, in field
+// declaration order. Strings are quoted so empty values and embedded newlines
+// are visible.
+//
+// Fields tagged `secret:"true"` are rendered as RedactedValue, whatever their
+// type; tagging a nested struct redacts all of it. The `redact:"true"` tag used
+// by jsonutil.MarshalRedacted is honored too, so one tag covers both outputs.
+// Values decrypted from "enc:" strings are plaintext after loading, so tag
+// those fields too.
+//
+// cfg must be a struct or a pointer to one. Dump can be called after any of
+// the Load functions.
+func Dump(cfg interface{}) (string, error) {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", fmt.Errorf("config must be a struct or a pointer to a struct")
+	}
+
+	var b strings.Builder
+	dumpValue(&b, v, "")
+	return b.String(), nil
+}
+
+// dumpValue writes the lines for v, named path, to b.
+func dumpValue(b *strings.Builder, v reflect.Value, path string) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			writeDumpLine(b, path, "<nil>")
+			return
+		}
+		dumpValue(b, v.Elem(), path)
+
+	case reflect.Struct:
+		if v.Type() == timeType {
+			writeDumpLine(b, path, formatDumpValue(v))
+			return
+		}
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := joinFieldPath(path, field.Name)
+			if isSecretField(field) {
+				writeDumpLine(b, fieldPath, RedactedValue)
+				continue
+			}
+			dumpValue(b, v.Field(i), fieldPath)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if !hasNestedFields(v.Type().Elem()) {
+			writeDumpLine(b, path, formatDumpValue(v))
+			return
+		}
+		if v.Len() == 0 {
+			writeDumpLine(b, path, "[]")
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			dumpValue(b, v.Index(i), fmt.Sprintf("%s[%d]", path, i))
+		}
+
+	case reflect.Map:
+		if v.Len() == 0 {
+			writeDumpLine(b, path, "{}")
+			return
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			dumpValue(b, v.MapIndex(key), fmt.Sprintf("%s[%v]", path, key.Interface()))
+		}
+
+	default:
+		writeDumpLine(b, path, formatDumpValue(v))
+	}
+}
+
+// isSecretField reports whether field is tagged `secret:"true"` or `redact:"true"`.
+func isSecretField(field reflect.StructField) bool {
+	return field.Tag.Get("secret") == "true" || field.Tag.Get("redact") == "true"
+}
+
+// hasNestedFields reports whether values of t are dumped field by field rather
+// than on a single line.
+func hasNestedFields(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return t != timeType
+	case reflect.Map, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// formatDumpValue formats a leaf value, quoting strings.
+func formatDumpValue(v reflect.Value) string {
+	if !v.CanInterface() {
+		return "<unexported>"
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return "[]"
+		}
+		if v.Type().Elem().Kind() == reflect.String {
+			return fmt.Sprintf("%q", v.Interface())
+		}
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// writeDumpLine writes a single key=value line.
+func writeDumpLine(b *strings.Builder, key, value string) {
+	b.WriteString(key)
+	b.WriteByte('=')
+	b.WriteString(value)
+	b.WriteByte('\n')
+}
@@ -0,0 +1,106 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/go-utils/config"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+type dumpServerConfig struct {
+	Host    string        `yaml:"host"`
+	Port    int           `yaml:"port"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type dumpConfig struct {
+	Server      dumpServerConfig `yaml:"server"`
+	DatabaseURL string           `yaml:"database_url" secret:"true"`
+	Credentials struct {
+		User     string `yaml:"user"`
+		Password string `yaml:"password"`
+	} `yaml:"credentials" secret:"true"`
+	Tags      []string `yaml:"tags"`
+	Replicas  []*dumpServerConfig
+	Labels    map[string]string `yaml:"labels"`
+	MaxConns  *int              `yaml:"max_conns"`
+	Debug     bool              `yaml:"debug"`
+	Notes     string            `yaml:"notes"`
+	unexposed string
+}
+
+func TestDump(t *testing.T) {
+	content := `server:
+  host: "0.0.0.0"
+  port: 8080
+  timeout: 30s
+database_url: "postgres://admin:hunter2@db/app"
+credentials:
+  user: admin
+  password: hunter2
+tags: [api, "blue green"]
+labels:
+  zone: us-east-1
+  tier: web
+debug: true
+notes: "line one\nline two"
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	tst.RequireNoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	var cfg dumpConfig
+	tst.RequireNoError(t, config.LoadFromFile(&cfg, path))
+	cfg.Replicas = []*dumpServerConfig{{Host: "replica-1", Port: 5433}}
+	cfg.unexposed = "hidden"
+
+	out, err := config.Dump(&cfg)
+	tst.RequireNoError(t, err)
+
+	want := `Server.Host="0.0.0.0"
+Server.Port=8080
+Server.Timeout=30s
+DatabaseURL=***
+Credentials=***
+Tags=["api" "blue green"]
+Replicas[0].Host="replica-1"
+Replicas[0].Port=5433
+Replicas[0].Timeout=0s
+Labels[tier]="web"
+Labels[zone]="us-east-1"
+MaxConns=<nil>
+Debug=true
+Notes="line one\nline two"
+`
+	tst.AssertEqual(t, out, want)
+	tst.AssertFalse(t, strings.Contains(out, "hunter2"), "secrets should be redacted")
+
+	// A struct value works as well as a pointer
+	byValue, err := config.Dump(cfg)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, byValue, out)
+}
+
+func TestDump_RedactTag(t *testing.T) {
+	// Fields tagged for jsonutil.MarshalRedacted are redacted as well
+	cfg := struct {
+		User   string
+		APIKey string `json:"api_key" redact:"true"`
+	}{User: "admin", APIKey: "sk-live-123"}
+
+	out, err := config.Dump(cfg)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, out, "User=\"admin\"\nAPIKey=***\n")
+}
+
+func TestDump_Errors(t *testing.T) {
+	_, err := config.Dump("not a struct")
+	tst.AssertErrorContains(t, err, "config must be a struct or a pointer to a struct")
+
+	var nilCfg *dumpConfig
+	_, err = config.Dump(nilCfg)
+	tst.AssertNotNil(t, err, "expected error for nil pointer")
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// PostLoader is implemented by config structs that derive some fields from
+// others, such as a DSN assembled from host, port, and database name. The
+// loaders call PostLoad after all values have been loaded, env overrides
+// applied, and secrets decrypted.
+//
+// PostLoad is also called on nested structs (including pointers to structs and
+// elements of struct slices) that implement PostLoader. Nested structs run
+// first, so a parent can rely on values its children derived.
+type PostLoader interface {
+	PostLoad() error
+}
+
+// finishLoad runs the steps shared by every loader once values are in place.
+func finishLoad(cfg interface{}) error {
+	if err := decryptConfigSecrets(cfg); err != nil {
+		return err
+	}
+	if err := runPostLoad(reflect.ValueOf(cfg), ""); err != nil {
+		return err
+	}
+	return runValidate(cfg)
+}
+
+// runPostLoad calls PostLoad on v and on every nested value implementing
+// PostLoader, innermost first. path names the field for error messages.
+func runPostLoad(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return runPostLoad(v.Elem(), path)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if err := runPostLoad(v.Field(i), joinFieldPath(path, t.Field(i).Name)); err != nil {
+				return err
+			}
+		}
+		// Use the address so hooks with pointer receivers are found
+		if v.CanAddr() {
+			return callPostLoad(v.Addr(), path)
+		}
+		return callPostLoad(v, path)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := runPostLoad(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// callPostLoad calls PostLoad if v implements PostLoader.
+func callPostLoad(v reflect.Value, path string) error {
+	if !v.CanInterface() {
+		return nil
+	}
+	loader, ok := v.Interface().(PostLoader)
+	if !ok {
+		return nil
+	}
+	if err := loader.PostLoad(); err != nil {
+		if path == "" {
+			return fmt.Errorf("post-load hook failed: %w", err)
+		}
+		return fmt.Errorf("post-load hook for %s failed: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package config_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/go-utils/config"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+type postLoadDatabase struct {
+	Host string `yaml:"host" env:"PL_DB_HOST"`
+	Port int    `yaml:"port" env:"PL_DB_PORT"`
+	Name string `yaml:"name" env:"PL_DB_NAME"`
+	DSN  string `yaml:"-"`
+}
+
+func (d *postLoadDatabase) PostLoad() error {
+	if d.Host == "" {
+		return errors.New("host is required")
+	}
+	d.DSN = fmt.Sprintf("postgres://%s:%d/%s", d.Host, d.Port, d.Name)
+	return nil
+}
+
+type postLoadConfig struct {
+	Database postLoadDatabase    `yaml:"database"`
+	Replicas []*postLoadDatabase `yaml:"replicas"`
+	Summary  string              `yaml:"-"`
+}
+
+// PostLoad runs after the nested databases have built their DSNs
+func (c *postLoadConfig) PostLoad() error {
+	c.Summary = fmt.Sprintf("%s (+%d replicas)", c.Database.DSN, len(c.Replicas))
+	return nil
+}
+
+func TestPostLoad_FromEnv(t *testing.T) {
+	t.Setenv("PL_DB_HOST", "db.internal")
+	t.Setenv("PL_DB_PORT", "6543")
+	t.Setenv("PL_DB_NAME", "app")
+
+	var cfg postLoadConfig
+	tst.RequireNoError(t, config.LoadFromEnv(&cfg))
+	tst.AssertEqual(t, cfg.Database.DSN, "postgres://db.internal:6543/app")
+	tst.AssertEqual(t, cfg.Summary, "postgres://db.internal:6543/app (+0 replicas)")
+}
+
+func TestPostLoad_FromFileWithEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `database:
+  host: primary
+  port: 5432
+  name: orders
+replicas:
+  - host: replica-1
+    port: 5433
+    name: orders
+`
+	tst.RequireNoError(t, os.WriteFile(path, []byte(content), 0o600))
+	t.Setenv("PL_DB_NAME", "orders_prod")
+
+	var cfg postLoadConfig
+	tst.RequireNoError(t, config.LoadFromFileWithEnv(&cfg, path))
+	tst.AssertEqual(t, cfg.Database.DSN, "postgres://primary:5432/orders_prod")
+	tst.AssertEqual(t, cfg.Replicas[0].DSN, "postgres://replica-1:5433/orders")
+	tst.AssertEqual(t, cfg.Summary, "postgres://primary:5432/orders_prod (+1 replicas)")
+}
+
+func TestPostLoad_Error(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	tst.RequireNoError(t, os.WriteFile(path, []byte("database:\n  host: primary\nreplicas:\n  - port: 5433\n"), 0o600))
+
+	var cfg postLoadConfig
+	err := config.LoadFromFile(&cfg, path)
+	tst.AssertErrorContains(t, err, "post-load hook for Replicas[0] failed: host is required")
+}
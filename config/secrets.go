@@ -0,0 +1,205 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/julianstephens/go-utils/security"
+)
+
+// EncryptedPrefix marks a string value as encrypted. The rest of the value is
+// the base64-encoded output of security.Encrypt.
+const EncryptedPrefix = "enc:"
+
+// DefaultSecretKeyName is the secret name used to look up the decryption key
+// when SetSecretProvider is called with an empty key name.
+const DefaultSecretKeyName = "CONFIG_KEY"
+
+var (
+	secretMu       sync.RWMutex
+	secretProvider security.SecretProvider
+	secretKeyName  string
+)
+
+// SetSecretProvider configures decryption of encrypted config values. When a
+// provider is set, LoadFromEnv, LoadFromFile, LoadFromFileWithEnv, and
+// LoadLayered decrypt every string value that starts with EncryptedPrefix
+// after loading. The key is read from the provider under keyName (or
+// DefaultSecretKeyName if empty) and must be a base64-encoded AES-128, AES-192,
+// or AES-256 key. Passing a nil provider disables decryption.
+func SetSecretProvider(provider security.SecretProvider, keyName string) {
+	if keyName == "" {
+		keyName = DefaultSecretKeyName
+	}
+
+	secretMu.Lock()
+	defer secretMu.Unlock()
+	secretProvider = provider
+	secretKeyName = keyName
+}
+
+// EncryptValue encrypts plaintext with key and returns it in the
+// "enc:BASE64CIPHERTEXT" form understood by the loaders, ready to be committed
+// to a config file.
+func EncryptValue(key []byte, plaintext string) (string, error) {
+	ciphertext, err := security.Encrypt(key, []byte(plaintext))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt config value: %w", err)
+	}
+	return EncryptedPrefix + security.EncodeBase64(ciphertext), nil
+}
+
+// DecryptSecrets decrypts every string value in cfg that starts with
+// EncryptedPrefix, using the key stored in provider under keyName. Struct
+// fields, pointers, slices, and map values are searched recursively. The key
+// is only looked up if at least one encrypted value is present. An empty
+// keyName uses DefaultSecretKeyName.
+func DecryptSecrets(cfg interface{}, provider security.SecretProvider, keyName string) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config must be a pointer to a struct")
+	}
+
+	if keyName == "" {
+		keyName = DefaultSecretKeyName
+	}
+	d := &secretDecrypter{provider: provider, keyName: keyName}
+	return d.decrypt(v.Elem(), "")
+}
+
+// decryptConfigSecrets decrypts cfg using the provider configured with
+// SetSecretProvider, if any.
+func decryptConfigSecrets(cfg interface{}) error {
+	secretMu.RLock()
+	provider, keyName := secretProvider, secretKeyName
+	secretMu.RUnlock()
+
+	if provider == nil {
+		return nil
+	}
+	if err := DecryptSecrets(cfg, provider, keyName); err != nil {
+		return fmt.Errorf("failed to decrypt config values: %w", err)
+	}
+	return nil
+}
+
+// secretDecrypter walks a config value and decrypts encrypted strings,
+// fetching the key from the provider on first use.
+type secretDecrypter struct {
+	provider security.SecretProvider
+	keyName  string
+	key      []byte
+}
+
+// decrypt decrypts encrypted strings in v. path names v in error messages.
+func (d *secretDecrypter) decrypt(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.String:
+		if !strings.HasPrefix(v.String(), EncryptedPrefix) || !v.CanSet() {
+			return nil
+		}
+		plaintext, err := d.decryptValue(v.String())
+		if err != nil {
+			return fmt.Errorf("field '%s': %w", path, err)
+		}
+		v.SetString(plaintext)
+
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Interface {
+			// Values held in an interface are not addressable; decrypt a copy
+			// and store it back.
+			elem := reflect.New(v.Elem().Type()).Elem()
+			elem.Set(v.Elem())
+			if err := d.decrypt(elem, path); err != nil {
+				return err
+			}
+			if v.CanSet() {
+				v.Set(elem)
+			}
+			return nil
+		}
+		return d.decrypt(v.Elem(), path)
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if err := d.decrypt(v.Field(i), joinFieldPath(path, t.Field(i).Name)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := d.decrypt(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			// Map values are not addressable; decrypt a copy and store it back.
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			if err := d.decrypt(elem, fmt.Sprintf("%s[%v]", path, iter.Key())); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+
+	return nil
+}
+
+// decryptValue decrypts a single "enc:"-prefixed value.
+func (d *secretDecrypter) decryptValue(value string) (string, error) {
+	if d.key == nil {
+		key, err := d.loadKey()
+		if err != nil {
+			return "", err
+		}
+		d.key = key
+	}
+
+	ciphertext, err := security.DecodeBase64(strings.TrimPrefix(value, EncryptedPrefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	plaintext, err := security.Decrypt(d.key, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt value: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+// loadKey fetches and decodes the decryption key from the provider.
+func (d *secretDecrypter) loadKey() ([]byte, error) {
+	if d.provider == nil {
+		return nil, fmt.Errorf("encrypted value found but no secret provider is configured")
+	}
+	secret, err := d.provider.GetSecret(d.keyName)
+	if err != nil {
+		return nil, fmt.Errorf("decryption key '%s' is not available: %w", d.keyName, err)
+	}
+	key, err := security.DecodeBase64(strings.TrimSpace(string(secret)))
+	if err != nil {
+		return nil, fmt.Errorf("decryption key '%s' is not valid base64: %w", d.keyName, err)
+	}
+	return key, nil
+}
+
+// joinFieldPath appends name to a dotted field path.
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
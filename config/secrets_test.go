@@ -0,0 +1,98 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/go-utils/config"
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+type SecretConfig struct {
+	Database struct {
+		Host     string `yaml:"host"`
+		Password string `yaml:"password"`
+	} `yaml:"database"`
+	APIKeys map[string]string `yaml:"api_keys"`
+	Tokens  []string          `yaml:"tokens"`
+}
+
+func TestLoadFromFile_DecryptsSecrets(t *testing.T) {
+	key, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+	t.Setenv("TEST_CONFIG_KEY", security.EncodeBase64(key))
+
+	password, err := config.EncryptValue(key, "s3cret")
+	tst.RequireNoError(t, err)
+	apiKey, err := config.EncryptValue(key, "sk-live-123")
+	tst.RequireNoError(t, err)
+	token, err := config.EncryptValue(key, "tok-2")
+	tst.RequireNoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "database:\n" +
+		"  host: db.internal\n" +
+		"  password: " + password + "\n" +
+		"api_keys:\n" +
+		"  billing: " + apiKey + "\n" +
+		"tokens:\n" +
+		"  - tok-1\n" +
+		"  - " + token + "\n"
+	tst.RequireNoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	config.SetSecretProvider(security.NewEnvSecretProvider("TEST_"), "")
+	t.Cleanup(func() { config.SetSecretProvider(nil, "") })
+
+	var cfg SecretConfig
+	tst.RequireNoError(t, config.LoadFromFile(&cfg, path))
+
+	tst.AssertEqual(t, cfg.Database.Host, "db.internal")
+	tst.AssertEqual(t, cfg.Database.Password, "s3cret")
+	tst.AssertEqual(t, cfg.APIKeys["billing"], "sk-live-123")
+	tst.AssertDeepEqual(t, cfg.Tokens, []string{"tok-1", "tok-2"})
+}
+
+func TestDecryptSecrets_MissingKey(t *testing.T) {
+	key, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+	password, err := config.EncryptValue(key, "s3cret")
+	tst.RequireNoError(t, err)
+
+	var cfg SecretConfig
+	cfg.Database.Password = password
+
+	err = config.DecryptSecrets(&cfg, security.NewEnvSecretProvider("MISSING_"), "CONFIG_KEY")
+	tst.AssertErrorContains(t, err, "decryption key 'CONFIG_KEY' is not available")
+	tst.AssertTrue(t, errors.Is(err, security.ErrSecretNotFound), "error should wrap ErrSecretNotFound")
+	tst.AssertEqual(t, cfg.Database.Password, password)
+}
+
+func TestDecryptSecrets_WrongKey(t *testing.T) {
+	key, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+	otherKey, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+	t.Setenv("TEST_CONFIG_KEY", security.EncodeBase64(otherKey))
+
+	password, err := config.EncryptValue(key, "s3cret")
+	tst.RequireNoError(t, err)
+
+	var cfg SecretConfig
+	cfg.Database.Password = password
+
+	err = config.DecryptSecrets(&cfg, security.NewEnvSecretProvider("TEST_"), "")
+	tst.AssertErrorContains(t, err, "Database.Password")
+}
+
+func TestDecryptSecrets_NoEncryptedValues(t *testing.T) {
+	var cfg SecretConfig
+	cfg.Database.Password = "plain"
+
+	// The key is only fetched when an encrypted value is present.
+	err := config.DecryptSecrets(&cfg, security.NewEnvSecretProvider("MISSING_"), "")
+	tst.AssertNoError(t, err)
+	tst.AssertEqual(t, cfg.Database.Password, "plain")
+}
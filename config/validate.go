@@ -0,0 +1,23 @@
+package config
+
+import "fmt"
+
+// Validatable is implemented by config structs with invariants that span
+// several fields, such as "if TLS is enabled, CertPath must be set". The
+// loaders call Validate on the target once loading is otherwise complete,
+// after PostLoad hooks have run, so derived fields can be checked too.
+type Validatable interface {
+	Validate() error
+}
+
+// runValidate calls Validate if cfg implements Validatable.
+func runValidate(cfg interface{}) error {
+	v, ok := cfg.(Validatable)
+	if !ok {
+		return nil
+	}
+	if err := v.Validate(); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+	return nil
+}
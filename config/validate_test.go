@@ -0,0 +1,67 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/go-utils/config"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+var errMissingCert = errors.New("cert_path is required when TLS is enabled")
+
+type validatedConfig struct {
+	TLSEnabled bool   `yaml:"tls_enabled" env:"VC_TLS_ENABLED"`
+	CertPath   string `yaml:"cert_path"   env:"VC_CERT_PATH"`
+	Addr       string `yaml:"-"`
+}
+
+func (c *validatedConfig) PostLoad() error {
+	if c.TLSEnabled {
+		c.Addr = ":443"
+	}
+	return nil
+}
+
+func (c *validatedConfig) Validate() error {
+	if c.TLSEnabled && c.CertPath == "" {
+		return errMissingCert
+	}
+	if c.TLSEnabled && c.Addr != ":443" {
+		return errors.New("validate ran before PostLoad")
+	}
+	return nil
+}
+
+func TestValidate_FromEnv(t *testing.T) {
+	t.Setenv("VC_TLS_ENABLED", "true")
+
+	var cfg validatedConfig
+	err := config.LoadFromEnv(&cfg)
+	tst.AssertErrorIs(t, err, errMissingCert)
+	tst.AssertErrorContains(t, err, "config validation failed")
+
+	t.Setenv("VC_CERT_PATH", "/etc/tls/cert.pem")
+	cfg = validatedConfig{}
+	tst.AssertNoError(t, config.LoadFromEnv(&cfg))
+	tst.AssertEqual(t, cfg.Addr, ":443")
+}
+
+func TestValidate_FromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	tst.RequireNoError(t, os.WriteFile(path, []byte("tls_enabled: true\n"), 0644))
+
+	var cfg validatedConfig
+	tst.AssertErrorIs(t, config.LoadFromFile(&cfg, path), errMissingCert)
+
+	// An environment override can satisfy the invariant
+	t.Setenv("VC_CERT_PATH", "/etc/tls/cert.pem")
+	cfg = validatedConfig{}
+	tst.AssertNoError(t, config.LoadFromFileWithEnv(&cfg, path))
+
+	tst.RequireNoError(t, os.WriteFile(path, []byte("tls_enabled: false\n"), 0644))
+	cfg = validatedConfig{}
+	tst.AssertNoError(t, config.LoadFromFile(&cfg, path))
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDebounce coalesces the bursts of events editors produce for one save.
+const watchDebounce = 100 * time.Millisecond
+
+// watchPollInterval is how often the file is checked when fsnotify is unavailable.
+const watchPollInterval = time.Second
+
+// Watch reloads the file at path into cfg with LoadFromFileWithEnv whenever it
+// changes, for services that pick up configuration without a restart. cfg must
+// be a pointer to a config struct.
+//
+// Each reload decodes into a fresh value and copies it into cfg only if
+// loading, PostLoad hooks, and Validate all succeed, so a bad edit leaves the
+// previous values intact. onReload, if not nil, is called after every reload
+// attempt with nil or the error that rejected the file, and also receives
+// errors from the watcher itself. Calls to onReload are made from a single
+// goroutine, one at a time.
+//
+// cfg is written from the watcher goroutine, so code that reads it while Watch
+// is running must synchronize with those writes; use WatchWithLock to have
+// each copy made under a lock the readers also hold.
+//
+// Changes are detected with fsnotify, watching the file's directory so editors
+// that save by renaming a new file into place are handled. If fsnotify is not
+// supported, the file's size and modification time are polled every second.
+//
+// The returned stop function stops watching and waits for an in-progress reload
+// to finish. It is safe to call more than once.
+func Watch(cfg interface{}, path string, onReload func(error)) (stop func(), err error) {
+	return WatchWithLock(cfg, path, nil, onReload)
+}
+
+// WatchWithLock is like Watch but holds mu while a reloaded config is copied
+// into cfg, so readers that take mu (or its read lock, for a sync.RWMutex)
+// never see a partly updated config. A nil mu behaves like Watch.
+func WatchWithLock(cfg interface{}, path string, mu sync.Locker, onReload func(error)) (stop func(), err error) {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config must be a pointer to a struct")
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config file path '%s': %w", path, err)
+	}
+	if _, err := os.Stat(absPath); err != nil {
+		return nil, fmt.Errorf("failed to watch config file '%s': %w", path, err)
+	}
+
+	notify := func(err error) {
+		if onReload != nil {
+			onReload(err)
+		}
+	}
+	reload := func() {
+		fresh := reflect.New(v.Elem().Type())
+		if err := LoadFromFileWithEnv(fresh.Interface(), absPath); err != nil {
+			notify(err)
+			return
+		}
+		if mu != nil {
+			mu.Lock()
+		}
+		v.Elem().Set(fresh.Elem())
+		if mu != nil {
+			mu.Unlock()
+		}
+		notify(nil)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		if err := watcher.Add(filepath.Dir(absPath)); err != nil {
+			_ = watcher.Close()
+			return nil, fmt.Errorf("failed to watch config file '%s': %w", path, err)
+		}
+		go func() {
+			defer wg.Done()
+			watchEvents(watcher, absPath, done, reload, notify)
+		}()
+	} else {
+		watcher = nil
+		go func() {
+			defer wg.Done()
+			pollFile(absPath, done, reload)
+		}()
+	}
+
+	var once sync.Once
+	stop = func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+			if watcher != nil {
+				_ = watcher.Close()
+			}
+		})
+	}
+	return stop, nil
+}
+
+// watchEvents calls reload after writes to path settle, until done is closed.
+func watchEvents(w *fsnotify.Watcher, path string, done <-chan struct{}, reload func(), notify func(error)) {
+	timer := time.NewTimer(watchDebounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case event, ok := <-w.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != path || !event.Has(fsnotify.Write|fsnotify.Create) {
+				continue
+			}
+			timer.Reset(watchDebounce)
+		case err, ok := <-w.Errors:
+			if !ok {
+				return
+			}
+			notify(fmt.Errorf("config watcher error: %w", err))
+		case <-timer.C:
+			reload()
+		}
+	}
+}
+
+// pollFile calls reload when the size or modification time of path changes,
+// until done is closed. A missing file is ignored until it reappears.
+func pollFile(path string, done <-chan struct{}, reload func()) {
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	var lastSize int64
+	var lastMod time.Time
+	if info, err := os.Stat(path); err == nil {
+		lastSize, lastMod = info.Size(), info.ModTime()
+	}
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			if info.Size() == lastSize && info.ModTime().Equal(lastMod) {
+				continue
+			}
+			lastSize, lastMod = info.Size(), info.ModTime()
+			reload()
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/go-utils/config"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+type watchedConfig struct {
+	Port    int    `yaml:"port"`
+	Mode    string `yaml:"mode"`
+	Workers int    `yaml:"workers"`
+}
+
+func (c *watchedConfig) Validate() error {
+	if c.Workers < 1 {
+		return errors.New("workers must be at least 1")
+	}
+	return nil
+}
+
+// waitReload returns the error from the next reload, failing the test if no
+// reload happens in time.
+func waitReload(t *testing.T, reloads <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-reloads:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for config reload")
+		return nil
+	}
+}
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	tst.RequireNoError(t, os.WriteFile(path, []byte("port: 8080\nmode: blue\nworkers: 4\n"), 0644))
+
+	var cfg watchedConfig
+	tst.RequireNoError(t, config.LoadFromFileWithEnv(&cfg, path))
+
+	var mu sync.RWMutex
+	current := func() watchedConfig {
+		mu.RLock()
+		defer mu.RUnlock()
+		return cfg
+	}
+	reloads := make(chan error, 10)
+	stop, err := config.WatchWithLock(&cfg, path, &mu, func(err error) { reloads <- err })
+	tst.RequireNoError(t, err)
+	defer stop()
+
+	// A valid change is copied into cfg
+	tst.RequireNoError(t, os.WriteFile(path, []byte("port: 9090\nmode: green\nworkers: 8\n"), 0644))
+	tst.AssertNoError(t, waitReload(t, reloads))
+	tst.AssertDeepEqual(t, current(), watchedConfig{Port: 9090, Mode: "green", Workers: 8})
+
+	// A change that fails validation leaves cfg untouched
+	tst.RequireNoError(t, os.WriteFile(path, []byte("port: 7070\nmode: red\nworkers: 0\n"), 0644))
+	tst.AssertErrorContains(t, waitReload(t, reloads), "workers must be at least 1")
+	tst.AssertDeepEqual(t, current(), watchedConfig{Port: 9090, Mode: "green", Workers: 8})
+
+	// So does a file that fails to parse
+	tst.RequireNoError(t, os.WriteFile(path, []byte("port: [not a number\n"), 0644))
+	tst.AssertNotNil(t, waitReload(t, reloads), "expected parse error")
+	tst.AssertEqual(t, current().Port, 9090)
+
+	// Replacing the file by rename, as many editors do, is picked up
+	tmp := filepath.Join(filepath.Dir(path), "config.yaml.tmp")
+	tst.RequireNoError(t, os.WriteFile(tmp, []byte("port: 6060\nmode: blue\nworkers: 2\n"), 0644))
+	tst.RequireNoError(t, os.Rename(tmp, path))
+	tst.AssertNoError(t, waitReload(t, reloads))
+	tst.AssertEqual(t, current().Port, 6060)
+
+	// No reloads after stop
+	stop()
+	stop()
+	tst.RequireNoError(t, os.WriteFile(path, []byte("port: 5050\nmode: blue\nworkers: 2\n"), 0644))
+	select {
+	case err := <-reloads:
+		t.Fatalf("unexpected reload after stop: %v", err)
+	case <-time.After(300 * time.Millisecond):
+	}
+	tst.AssertEqual(t, cfg.Port, 6060)
+}
+
+func TestWatch_WithoutLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	tst.RequireNoError(t, os.WriteFile(path, []byte("port: 8080\nmode: blue\nworkers: 4\n"), 0644))
+
+	var cfg watchedConfig
+	reloads := make(chan error, 10)
+	stop, err := config.Watch(&cfg, path, func(err error) { reloads <- err })
+	tst.RequireNoError(t, err)
+	defer stop()
+
+	// cfg is written before onReload runs, so it is safe to read once the
+	// callback has been observed
+	tst.RequireNoError(t, os.WriteFile(path, []byte("port: 9090\nmode: green\nworkers: 8\n"), 0644))
+	tst.AssertNoError(t, waitReload(t, reloads))
+	tst.AssertEqual(t, cfg.Port, 9090)
+}
+
+func TestWatch_Errors(t *testing.T) {
+	onReload := func(error) {}
+	var cfg watchedConfig
+	_, err := config.Watch(cfg, "config.yaml", onReload)
+	tst.AssertErrorContains(t, err, "config must be a pointer to a struct")
+
+	_, err = config.Watch(&cfg, filepath.Join(t.TempDir(), "missing.yaml"), onReload)
+	tst.AssertErrorIs(t, err, os.ErrNotExist)
+}
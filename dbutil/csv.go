@@ -0,0 +1,96 @@
+package dbutil
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ExportCSV runs query and writes the result to w as CSV: a header row of
+// column names followed by one record per row. Rows are streamed as they are
+// read, so large result sets are not held in memory. NULL values are written
+// as empty fields, byte slices as text, and times in RFC 3339 format; fields
+// are quoted where needed by encoding/csv.
+func ExportCSV(ctx context.Context, db *sql.DB, w io.Writer, query string, args ...any) error {
+	if err := exportCSV(ctx, db, w, query, args); err != nil {
+		return fmt.Errorf("dbutil: export csv failed: %w", err)
+	}
+	return nil
+}
+
+// ExportCSVTx is like ExportCSV but uses a transaction.
+func ExportCSVTx(ctx context.Context, tx *sql.Tx, w io.Writer, query string, args ...any) error {
+	if err := exportCSV(ctx, tx, w, query, args); err != nil {
+		return fmt.Errorf("dbutil: export csv (tx) failed: %w", err)
+	}
+	return nil
+}
+
+func exportCSV(ctx context.Context, q queryer, w io.Writer, query string, args []any) error {
+	rows, err := hookedQuery(ctx, q, query, args)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+
+	values := make([]any, len(columns))
+	ptrs := make([]any, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	record := make([]string, len(columns))
+
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		for i, v := range values {
+			record[i] = csvField(v)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvField formats a scanned column value as a CSV field.
+func csvField(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(val)
+	case string:
+		return val
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		return fmt.Sprint(val)
+	}
+}
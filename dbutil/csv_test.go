@@ -0,0 +1,50 @@
+package dbutil_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/julianstephens/go-utils/dbutil"
+	"github.com/julianstephens/go-utils/dbutil/dbtest"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestExportCSV(t *testing.T) {
+	db := dbtest.NewDB(t, "CREATE TABLE contacts (id INTEGER PRIMARY KEY, name TEXT, note TEXT, score REAL)")
+	ctx := context.Background()
+
+	_, err := db.Exec(`INSERT INTO contacts (id, name, note, score) VALUES
+		(1, 'Smith, Jane', 'said "hi"', 9.5),
+		(2, 'Bob', NULL, NULL),
+		(3, 'Multi', 'line one
+line two', 7)`)
+	tst.RequireNoError(t, err)
+
+	var buf bytes.Buffer
+	err = dbutil.ExportCSV(ctx, db, &buf, "SELECT id, name, note, score FROM contacts ORDER BY id")
+	tst.RequireNoError(t, err)
+
+	want := "id,name,note,score\n" +
+		"1,\"Smith, Jane\",\"said \"\"hi\"\"\",9.5\n" +
+		"2,Bob,,\n" +
+		"3,Multi,\"line one\nline two\",7\n"
+	tst.AssertEqual(t, buf.String(), want)
+}
+
+func TestExportCSV_Empty(t *testing.T) {
+	db := dbtest.NewDB(t, "CREATE TABLE contacts (id INTEGER PRIMARY KEY, name TEXT)")
+
+	var buf bytes.Buffer
+	err := dbutil.ExportCSV(context.Background(), db, &buf, "SELECT id, name FROM contacts WHERE id > ?", 0)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, buf.String(), "id,name\n")
+}
+
+func TestExportCSV_QueryError(t *testing.T) {
+	db := dbtest.NewDB(t, "CREATE TABLE contacts (id INTEGER PRIMARY KEY)")
+
+	var buf bytes.Buffer
+	err := dbutil.ExportCSV(context.Background(), db, &buf, "SELECT missing FROM contacts")
+	tst.AssertErrorContains(t, err, "dbutil: export csv failed")
+}
@@ -0,0 +1,33 @@
+package dbtest
+
+import (
+	"database/sql"
+	"testing"
+)
+
+// DriverName is the database/sql driver used by NewDB.
+const DriverName = "sqlite"
+
+// NewDB opens an in-memory SQLite database, applies each schema statement in
+// order, and registers a cleanup that closes the database when the test ends.
+// Any failure stops the test.
+//
+// The pool is limited to one connection because every connection to an
+// in-memory SQLite database gets its own, empty database.
+func NewDB(t *testing.T, schema ...string) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open(DriverName, ":memory:")
+	if err != nil {
+		t.Fatalf("dbtest: failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	for _, stmt := range schema {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("dbtest: failed to apply schema statement %q: %v", stmt, err)
+		}
+	}
+	return db
+}
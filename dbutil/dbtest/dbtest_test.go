@@ -0,0 +1,44 @@
+package dbtest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/julianstephens/go-utils/dbutil"
+	"github.com/julianstephens/go-utils/dbutil/dbtest"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+type user struct {
+	ID    int64  `db:"id"`
+	Name  string `db:"name"`
+	Email string `db:"email"`
+}
+
+func TestNewDB_QuerySlice(t *testing.T) {
+	db := dbtest.NewDB(t,
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, email TEXT)",
+		"INSERT INTO users (id, name, email) VALUES (1, 'Alice', 'alice@example.com'), (2, 'Bob', 'bob@example.com')",
+	)
+
+	var users []user
+	err := dbutil.QuerySlice(context.Background(), db, &users, "SELECT id, name, email FROM users ORDER BY id")
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, users, []user{
+		{ID: 1, Name: "Alice", Email: "alice@example.com"},
+		{ID: 2, Name: "Bob", Email: "bob@example.com"},
+	})
+}
+
+func TestNewDB_Isolated(t *testing.T) {
+	first := dbtest.NewDB(t, "CREATE TABLE items (id INTEGER PRIMARY KEY)")
+	second := dbtest.NewDB(t)
+
+	exists, err := dbutil.Exists(context.Background(), first, "SELECT 1 FROM sqlite_master WHERE name = 'items'")
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, exists, "schema should be applied")
+
+	exists, err = dbutil.Exists(context.Background(), second, "SELECT 1 FROM sqlite_master WHERE name = 'items'")
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, exists, "each database should start empty")
+}
@@ -0,0 +1,21 @@
+// Package dbtest provides a disposable in-memory SQLite database for tests of
+// code built on database/sql, such as the dbutil helpers.
+//
+// NewDB opens a fresh database for each test, applies the given schema
+// statements, and closes it when the test finishes:
+//
+//	func TestListUsers(t *testing.T) {
+//	    db := dbtest.NewDB(t,
+//	        "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)",
+//	        "INSERT INTO users (id, name) VALUES (1, 'Alice')",
+//	    )
+//
+//	    var users []User
+//	    err := dbutil.QuerySlice(ctx, db, &users, "SELECT id, name FROM users")
+//	    // ...
+//	}
+//
+// The pure-Go modernc.org/sqlite driver is registered by default. Build with
+// the nosqlite tag to leave it out and register another driver under
+// DriverName instead.
+package dbtest
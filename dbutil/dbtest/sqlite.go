@@ -0,0 +1,8 @@
+//go:build !nosqlite
+
+package dbtest
+
+import (
+	// Register the pure-Go SQLite driver under DriverName
+	_ "modernc.org/sqlite"
+)
@@ -4,12 +4,24 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"reflect"
 	"strings"
 	"time"
 )
 
+// Backoff bounds used by WithTransactionRetry.
+const (
+	transactionRetryBaseDelay = 10 * time.Millisecond
+	transactionRetryMaxDelay  = time.Second
+)
+
+// ErrUnexpectedRowCount is returned by ExecExpect when a statement affects a
+// different number of rows than expected.
+var ErrUnexpectedRowCount = errors.New("dbutil: unexpected row count")
+
 // ConnectionOptions holds configuration options for database connections.
 type ConnectionOptions struct {
 	// MaxOpenConns sets the maximum number of open connections to the database.
@@ -30,14 +42,38 @@ type ConnectionOptions struct {
 
 // QueryOptions holds configuration options for query execution.
 type QueryOptions struct {
-	// Timeout sets the timeout for query execution.
+	// Timeout bounds each query run by the *WithOptions helpers; zero or
+	// less disables it. A deadline already on the caller's context still
+	// applies if it is sooner.
 	Timeout time.Duration
 	// MaxRows limits the number of rows returned (0 means no limit).
 	MaxRows int
 	// FieldMapper is a function to map struct field names to database column names.
 	FieldMapper func(string) string
+	// KeyCase normalizes the map keys returned by QueryMapsWithOptions.
+	KeyCase KeyCase
+	// FieldStruct is a struct value or pointer whose fields name the map keys
+	// under KeyCaseField.
+	FieldStruct any
 }
 
+// KeyCase selects how QueryMapsWithOptions normalizes column names into map keys.
+type KeyCase int
+
+const (
+	// KeyCaseAsIs keeps column names exactly as the driver reports them.
+	KeyCaseAsIs KeyCase = iota
+	// KeyCaseLower lowercases column names (e.g., "UserID" -> "userid").
+	KeyCaseLower
+	// KeyCaseSnake converts column names to snake_case (e.g., "UserID" -> "user_id").
+	KeyCaseSnake
+	// KeyCaseField applies FieldMapper in reverse: each column is keyed by the
+	// name of the FieldStruct field that maps to it, via its db tag or
+	// FieldMapper (DefaultFieldMapper if nil). Columns that match no field keep
+	// their name. FieldStruct is required.
+	KeyCaseField
+)
+
 // TransactionOptions holds configuration options for transactions.
 type TransactionOptions struct {
 	// Isolation sets the transaction isolation level.
@@ -150,18 +186,18 @@ func PingWithRetry(ctx context.Context, db *sql.DB, attempts int, delay time.Dur
 // QueryRow executes a query that is expected to return at most one row.
 // It returns a *sql.Row which can be scanned into destination variables.
 func QueryRow(ctx context.Context, db *sql.DB, query string, args ...any) *sql.Row {
-	return db.QueryRowContext(ctx, query, args...)
+	return hookedQueryRow(ctx, db, query, args)
 }
 
 // QueryRowTx is like QueryRow but uses a transaction.
 func QueryRowTx(ctx context.Context, tx *sql.Tx, query string, args ...any) *sql.Row {
-	return tx.QueryRowContext(ctx, query, args...)
+	return hookedQueryRow(ctx, tx, query, args)
 }
 
 // QueryRows executes a query and returns multiple rows.
 // It's the caller's responsibility to close the returned *sql.Rows.
 func QueryRows(ctx context.Context, db *sql.DB, query string, args ...any) (*sql.Rows, error) {
-	rows, err := db.QueryContext(ctx, query, args...)
+	rows, err := hookedQuery(ctx, db, query, args)
 	if err != nil {
 		return nil, fmt.Errorf("dbutil: query rows failed: %w", err)
 	}
@@ -170,7 +206,7 @@ func QueryRows(ctx context.Context, db *sql.DB, query string, args ...any) (*sql
 
 // QueryRowsTx is like QueryRows but uses a transaction.
 func QueryRowsTx(ctx context.Context, tx *sql.Tx, query string, args ...any) (*sql.Rows, error) {
-	rows, err := tx.QueryContext(ctx, query, args...)
+	rows, err := hookedQuery(ctx, tx, query, args)
 	if err != nil {
 		return nil, fmt.Errorf("dbutil: query rows (tx) failed: %w", err)
 	}
@@ -180,7 +216,7 @@ func QueryRowsTx(ctx context.Context, tx *sql.Tx, query string, args ...any) (*s
 // Exec executes a query without returning any rows.
 // It returns the number of rows affected and any error encountered.
 func Exec(ctx context.Context, db *sql.DB, query string, args ...any) (sql.Result, error) {
-	result, err := db.ExecContext(ctx, query, args...)
+	result, err := hookedExec(ctx, db, query, args)
 	if err != nil {
 		return nil, fmt.Errorf("dbutil: exec failed: %w", err)
 	}
@@ -189,13 +225,86 @@ func Exec(ctx context.Context, db *sql.DB, query string, args ...any) (sql.Resul
 
 // ExecTx is like Exec but uses a transaction.
 func ExecTx(ctx context.Context, tx *sql.Tx, query string, args ...any) (sql.Result, error) {
-	result, err := tx.ExecContext(ctx, query, args...)
+	result, err := hookedExec(ctx, tx, query, args)
 	if err != nil {
 		return nil, fmt.Errorf("dbutil: exec (tx) failed: %w", err)
 	}
 	return result, nil
 }
 
+// ExecWithOptions is like Exec but bounds the statement by opts.Timeout. A nil
+// opts uses DefaultQueryOptions.
+func ExecWithOptions(
+	ctx context.Context,
+	db *sql.DB,
+	query string,
+	opts *QueryOptions,
+	args ...any,
+) (sql.Result, error) {
+	ctx, cancel := withQueryTimeout(ctx, opts)
+	defer cancel()
+	return Exec(ctx, db, query, args...)
+}
+
+// ExecWithOptionsTx is like ExecWithOptions but uses a transaction.
+func ExecWithOptionsTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	query string,
+	opts *QueryOptions,
+	args ...any,
+) (sql.Result, error) {
+	ctx, cancel := withQueryTimeout(ctx, opts)
+	defer cancel()
+	return ExecTx(ctx, tx, query, args...)
+}
+
+// withQueryTimeout derives a context bounded by opts.Timeout, using
+// DefaultQueryOptions when opts is nil. The returned cancel must be called
+// once the query's results have been consumed.
+func withQueryTimeout(ctx context.Context, opts *QueryOptions) (context.Context, context.CancelFunc) {
+	if opts == nil {
+		opts = DefaultQueryOptions()
+	}
+	if opts.Timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, opts.Timeout)
+}
+
+// ExecExpect executes a statement and checks that it affected exactly expected
+// rows. If the count differs it returns an error wrapping ErrUnexpectedRowCount
+// that reports both counts. This catches UPDATE and DELETE statements that
+// silently match no rows.
+func ExecExpect(ctx context.Context, db *sql.DB, expected int64, query string, args ...any) error {
+	result, err := hookedExec(ctx, db, query, args)
+	if err != nil {
+		return fmt.Errorf("dbutil: exec failed: %w", err)
+	}
+	return checkRowsAffected(result, expected)
+}
+
+// ExecExpectTx is like ExecExpect but uses a transaction.
+func ExecExpectTx(ctx context.Context, tx *sql.Tx, expected int64, query string, args ...any) error {
+	result, err := hookedExec(ctx, tx, query, args)
+	if err != nil {
+		return fmt.Errorf("dbutil: exec (tx) failed: %w", err)
+	}
+	return checkRowsAffected(result, expected)
+}
+
+// checkRowsAffected compares the rows affected by result against expected.
+func checkRowsAffected(result sql.Result, expected int64) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("dbutil: rows affected failed: %w", err)
+	}
+	if affected != expected {
+		return fmt.Errorf("%w: expected %d, got %d", ErrUnexpectedRowCount, expected, affected)
+	}
+	return nil
+}
+
 // WithTransaction executes a function within a database transaction.
 // If the function returns an error, the transaction is rolled back.
 // Otherwise, the transaction is committed.
@@ -252,21 +361,86 @@ func WithTransactionOptions(ctx context.Context, db *sql.DB, opts *TransactionOp
 	return nil
 }
 
+// WithTransactionRetry executes fn within a transaction like WithTransaction,
+// re-running the entire transaction when it fails with a deadlock or
+// serialization failure (see IsSerializationError). Up to attempts runs are
+// made, with exponential backoff and jitter between them. Other errors are
+// returned immediately. fn may run more than once, so it must not have side
+// effects outside the transaction.
+func WithTransactionRetry(ctx context.Context, db *sql.DB, attempts int, fn func(*sql.Tx) error) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	delay := transactionRetryBaseDelay
+	var err error
+	for i := range attempts {
+		err = WithTransaction(ctx, db, fn)
+		if err == nil || !IsSerializationError(err) {
+			return err
+		}
+		if i == attempts-1 {
+			break
+		}
+
+		// Sleep between delay/2 and delay so competing transactions spread out
+		wait := delay/2 + rand.N(delay/2+1)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("dbutil: transaction retry cancelled: %w", ctx.Err())
+		case <-time.After(wait):
+		}
+		delay = min(delay*2, transactionRetryMaxDelay)
+	}
+	return fmt.Errorf("dbutil: transaction failed after %d attempts: %w", attempts, err)
+}
+
 // QueryRowScan executes a query that returns a single row and scans the result into dest.
 // dest should be a pointer to a struct with appropriate db tags.
 func QueryRowScan(ctx context.Context, db *sql.DB, dest any, query string, args ...any) error {
 	return queryRowScanImpl(ctx, func() *sql.Row {
-		return db.QueryRowContext(ctx, query, args...)
+		return hookedQueryRow(ctx, db, query, args)
 	}, dest)
 }
 
 // QueryRowScanTx is like QueryRowScan but uses a transaction.
 func QueryRowScanTx(ctx context.Context, tx *sql.Tx, dest any, query string, args ...any) error {
 	return queryRowScanImpl(ctx, func() *sql.Row {
-		return tx.QueryRowContext(ctx, query, args...)
+		return hookedQueryRow(ctx, tx, query, args)
 	}, dest)
 }
 
+// QueryRowScanWithOptions is like QueryRowScan but bounds the query by
+// opts.Timeout. The row is scanned before the timeout context is released,
+// which is why there is no timeout-aware variant of QueryRow. A nil opts uses
+// DefaultQueryOptions.
+func QueryRowScanWithOptions(
+	ctx context.Context,
+	db *sql.DB,
+	dest any,
+	query string,
+	opts *QueryOptions,
+	args ...any,
+) error {
+	ctx, cancel := withQueryTimeout(ctx, opts)
+	defer cancel()
+	return QueryRowScan(ctx, db, dest, query, args...)
+}
+
+// QueryRowScanWithOptionsTx is like QueryRowScanWithOptions but uses a transaction.
+func QueryRowScanWithOptionsTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	dest any,
+	query string,
+	opts *QueryOptions,
+	args ...any,
+) error {
+	ctx, cancel := withQueryTimeout(ctx, opts)
+	defer cancel()
+	return QueryRowScanTx(ctx, tx, dest, query, args...)
+}
+
 // queryRowScanImpl is the common implementation for QueryRowScan functions.
 func queryRowScanImpl(_ context.Context, queryFn func() *sql.Row, dest any) error {
 	destValue := reflect.ValueOf(dest)
@@ -292,7 +466,7 @@ func queryRowScanImpl(_ context.Context, queryFn func() *sql.Row, dest any) erro
 		if !fieldValue.CanAddr() {
 			return fmt.Errorf("dbutil: field %s cannot be addressed", field.Name)
 		}
-		scanDests[i] = fieldValue.Addr().Interface()
+		scanDests[i] = scanDest(fieldValue, field)
 	}
 
 	// Execute query and scan
@@ -309,6 +483,8 @@ type structField struct {
 	Name   string
 	Column string
 	Index  int
+	JSON   bool // db:"column,json": column holds JSON that is (un)marshaled into the field
+	Key    bool // db:"column,key": column orders rows for keyset pagination
 }
 
 // getStructFields extracts struct fields with db tags.
@@ -324,17 +500,21 @@ func getStructFields(t reflect.Type) ([]structField, error) {
 		}
 
 		// Get db tag or use field name
-		column := field.Tag.Get("db")
+		tag := field.Tag.Get("db")
+		if tag == "-" {
+			continue // Skip fields marked with db:"-"
+		}
+		column, opts, _ := strings.Cut(tag, ",")
 		if column == "" {
 			column = DefaultFieldMapper(field.Name)
-		} else if column == "-" {
-			continue // Skip fields marked with db:"-"
 		}
 
 		fields = append(fields, structField{
 			Name:   field.Name,
 			Column: column,
 			Index:  i,
+			JSON:   hasTagOption(opts, "json"),
+			Key:    hasTagOption(opts, "key"),
 		})
 	}
 
@@ -345,6 +525,26 @@ func getStructFields(t reflect.Type) ([]structField, error) {
 	return fields, nil
 }
 
+// hasTagOption reports whether the comma-separated tag options contain opt.
+func hasTagOption(opts, opt string) bool {
+	for opts != "" {
+		var name string
+		name, opts, _ = strings.Cut(opts, ",")
+		if name == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// scanDest returns the value to pass to Scan for an addressable struct field.
+func scanDest(fieldValue reflect.Value, field structField) any {
+	if field.JSON {
+		return &jsonColumn{dest: fieldValue.Addr().Interface()}
+	}
+	return fieldValue.Addr().Interface()
+}
+
 // IsNoRowsError checks if an error is a sql.ErrNoRows error.
 func IsNoRowsError(err error) bool {
 	return err == sql.ErrNoRows
@@ -382,10 +582,47 @@ func IsConnectionError(err error) bool {
 	return false
 }
 
-// IsContextError checks if an error is a context cancellation or timeout error.
+// IsSerializationError checks if an error is a deadlock or serialization
+// failure, which is expected under concurrency and resolved by re-running the
+// whole transaction. It recognises the SQLSTATE codes 40001 and 40P01 on
+// errors exposing a SQLState method (pgx, lib/pq) and the deadlock and lock
+// messages reported by PostgreSQL, MySQL, and SQLite.
+func IsSerializationError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		switch stateErr.SQLState() {
+		case "40001", "40P01":
+			return true
+		}
+	}
+
+	errStr := strings.ToLower(err.Error())
+	serializationErrors := []string{
+		"deadlock",
+		"could not serialize access",
+		"serialization failure",
+		"database is locked",
+		"database table is locked",
+	}
+
+	for _, serErr := range serializationErrors {
+		if strings.Contains(errStr, serErr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsContextError checks if an error is, or wraps, a context cancellation or
+// timeout error.
 func IsContextError(err error) bool {
 	if err == nil {
 		return false
 	}
-	return err == context.Canceled || err == context.DeadlineExceeded
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
 }
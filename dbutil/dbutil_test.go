@@ -5,9 +5,11 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/julianstephens/go-utils/dbutil"
+	"github.com/julianstephens/go-utils/dbutil/dbtest"
 	tst "github.com/julianstephens/go-utils/tests"
 )
 
@@ -73,6 +75,34 @@ func TestIsConnectionError(t *testing.T) {
 	}
 }
 
+// sqlStateError mimics driver errors that expose a SQLSTATE code.
+type sqlStateError struct{ code string }
+
+func (e sqlStateError) Error() string    { return "pq: error " + e.code }
+func (e sqlStateError) SQLState() string { return e.code }
+
+func TestIsSerializationError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{nil, false},
+		{sqlStateError{"40001"}, true},
+		{sqlStateError{"40P01"}, true},
+		{sqlStateError{"23505"}, false},
+		{fmt.Errorf("wrapped: %w", sqlStateError{"40001"}), true},
+		{errors.New("Error 1213: Deadlock found when trying to get lock"), true},
+		{errors.New("ERROR: could not serialize access due to concurrent update"), true},
+		{errors.New("database is locked (5) (SQLITE_BUSY)"), true},
+		{errors.New("some other error"), false},
+	}
+
+	for _, test := range tests {
+		result := dbutil.IsSerializationError(test.err)
+		tst.AssertTrue(t, result == test.expected, "IsSerializationError result should match expected")
+	}
+}
+
 func TestIsContextError(t *testing.T) {
 	tests := []struct {
 		err      error
@@ -82,6 +112,7 @@ func TestIsContextError(t *testing.T) {
 		{context.Canceled, true},
 		{context.DeadlineExceeded, true},
 		{errors.New("other error"), false},
+		{fmt.Errorf("dbutil: query failed: %w", context.DeadlineExceeded), true},
 	}
 
 	for _, test := range tests {
@@ -112,6 +143,87 @@ func TestDefaultOptions(t *testing.T) {
 	})
 }
 
+func TestExecExpect(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.NewDB(t,
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, email TEXT)",
+		"INSERT INTO users (id, name, email) VALUES (1, 'Alice', 'alice@example.com'), (2, 'Bob', 'bob@example.com')",
+	)
+
+	t.Run("expected count", func(t *testing.T) {
+		err := dbutil.ExecExpect(ctx, db, 1, "UPDATE users SET name = ? WHERE id = ?", "Alicia", 1)
+		tst.RequireNoError(t, err)
+	})
+
+	t.Run("no rows affected", func(t *testing.T) {
+		err := dbutil.ExecExpect(ctx, db, 1, "UPDATE users SET name = ? WHERE id = ?", "Nobody", 99)
+		tst.AssertErrorIs(t, err, dbutil.ErrUnexpectedRowCount)
+		tst.AssertErrorContains(t, err, "expected 1, got 0")
+	})
+
+	t.Run("too many rows affected", func(t *testing.T) {
+		err := dbutil.WithTransaction(ctx, db, func(tx *sql.Tx) error {
+			return dbutil.ExecExpectTx(ctx, tx, 1, "DELETE FROM users")
+		})
+		tst.AssertErrorIs(t, err, dbutil.ErrUnexpectedRowCount)
+
+		var count int
+		tst.RequireNoError(t, db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count))
+		tst.AssertEqual(t, count, 2)
+	})
+}
+
+func TestWithTransactionRetry(t *testing.T) {
+	ctx := context.Background()
+	db := dbtest.NewDB(t, "CREATE TABLE events (id INTEGER PRIMARY KEY, name TEXT)")
+
+	countEvents := func() int {
+		var count int
+		tst.RequireNoError(t, db.QueryRow("SELECT COUNT(*) FROM events").Scan(&count))
+		return count
+	}
+
+	t.Run("retries after deadlock", func(t *testing.T) {
+		calls := 0
+		err := dbutil.WithTransactionRetry(ctx, db, 3, func(tx *sql.Tx) error {
+			calls++
+			if _, err := tx.Exec("INSERT INTO events (name) VALUES (?)", "created"); err != nil {
+				return err
+			}
+			if calls == 1 {
+				return errors.New("deadlock detected")
+			}
+			return nil
+		})
+		tst.RequireNoError(t, err)
+		tst.AssertEqual(t, calls, 2)
+		// The first attempt was rolled back, so only one row is stored
+		tst.AssertEqual(t, countEvents(), 1)
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		calls := 0
+		err := dbutil.WithTransactionRetry(ctx, db, 3, func(tx *sql.Tx) error {
+			calls++
+			return errBoom
+		})
+		tst.AssertErrorIs(t, err, errBoom)
+		tst.AssertEqual(t, calls, 1)
+	})
+
+	t.Run("gives up after attempts", func(t *testing.T) {
+		calls := 0
+		err := dbutil.WithTransactionRetry(ctx, db, 3, func(tx *sql.Tx) error {
+			calls++
+			return sqlStateError{"40001"}
+		})
+		tst.AssertErrorContains(t, err, "after 3 attempts")
+		tst.AssertTrue(t, dbutil.IsSerializationError(err), "final error should wrap the serialization failure")
+		tst.AssertEqual(t, calls, 3)
+	})
+}
+
 // Benchmark tests
 func BenchmarkDefaultFieldMapper(b *testing.B) {
 	fieldName := "VeryLongFieldNameWithManyCamelCaseWords"
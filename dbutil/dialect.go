@@ -0,0 +1,58 @@
+package dbutil
+
+import (
+	"sync/atomic"
+)
+
+// Dialect identifies the SQL dialect used when the package generates SQL.
+type Dialect int
+
+const (
+	// DialectPostgres generates PostgreSQL syntax ($1 placeholders).
+	DialectPostgres Dialect = iota
+	// DialectMySQL generates MySQL syntax (? placeholders).
+	DialectMySQL
+	// DialectSQLite generates SQLite syntax (? placeholders).
+	DialectSQLite
+)
+
+// String returns the name of the dialect.
+func (d Dialect) String() string {
+	switch d {
+	case DialectPostgres:
+		return "postgres"
+	case DialectMySQL:
+		return "mysql"
+	case DialectSQLite:
+		return "sqlite"
+	default:
+		return "unknown"
+	}
+}
+
+// defaultDialect holds the package-wide dialect used by generated SQL.
+var defaultDialect atomic.Int32
+
+// SetDialect sets the package-wide dialect used by helpers that generate SQL,
+// such as Upsert. The default is DialectPostgres. The dialect chooses the SQL
+// syntax; placeholders in generated SQL follow the package-wide placeholder
+// style instead when one other than PlaceholderQuestion is set, so they always
+// match queries rebound by SetPlaceholderStyle.
+func SetDialect(d Dialect) {
+	defaultDialect.Store(int32(d))
+}
+
+// GetDialect returns the package-wide dialect used by helpers that generate SQL.
+func GetDialect() Dialect {
+	return Dialect(defaultDialect.Load())
+}
+
+// generatedPlaceholderStyle returns the placeholder style for SQL generated by
+// the package-level helpers: the package-wide style when rebinding is enabled,
+// and otherwise the native style of the package-wide dialect.
+func generatedPlaceholderStyle() PlaceholderStyle {
+	if style := GetPlaceholderStyle(); style != PlaceholderQuestion {
+		return style
+	}
+	return GetDialect().PlaceholderStyle()
+}
@@ -0,0 +1,85 @@
+package dbutil
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/go-utils/health"
+)
+
+const (
+	// DefaultHealthCheckQuery is the query used by HealthCheck when none is provided.
+	DefaultHealthCheckQuery = "SELECT 1"
+	// DefaultHealthCheckTimeout bounds how long a health check query may run.
+	DefaultHealthCheckTimeout = 5 * time.Second
+)
+
+// HealthCheck runs a lightweight query against the database and returns an error
+// if it fails. Unlike PingContext, which may succeed as soon as a connection is
+// established, this exercises the full query path and therefore catches
+// databases that accept connections but cannot serve queries.
+// If query is empty, DefaultHealthCheckQuery is used. The query is bounded by
+// DefaultHealthCheckTimeout in addition to any deadline already set on ctx.
+func HealthCheck(ctx context.Context, db *sql.DB, query string) error {
+	if db == nil {
+		return fmt.Errorf("dbutil: health check failed: db is nil")
+	}
+	if query == "" {
+		query = DefaultHealthCheckQuery
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultHealthCheckTimeout)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("dbutil: health check failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	// Drain the result so errors surfaced during iteration are reported
+	for rows.Next() {
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("dbutil: health check failed: %w", err)
+	}
+
+	return nil
+}
+
+// HealthChecker adapts HealthCheck to the health.Checker interface.
+type HealthChecker struct {
+	// CheckName is the name reported in health check results (default: "database").
+	CheckName string
+	// DB is the database to check.
+	DB *sql.DB
+	// Query is the query to run (default: DefaultHealthCheckQuery).
+	Query string
+}
+
+// NewHealthChecker creates a health.Checker that runs HealthCheck against db.
+func NewHealthChecker(db *sql.DB, query string) *HealthChecker {
+	return &HealthChecker{
+		CheckName: "database",
+		DB:        db,
+		Query:     query,
+	}
+}
+
+// Name returns the name of this checker.
+func (c *HealthChecker) Name() string {
+	if c.CheckName == "" {
+		return "database"
+	}
+	return c.CheckName
+}
+
+// Check runs the health check query and reports the result.
+func (c *HealthChecker) Check() health.Check {
+	if err := HealthCheck(context.Background(), c.DB, c.Query); err != nil {
+		return health.NewCheckWithError(c.Name(), health.StatusError, "Database query failed", err)
+	}
+	return health.NewCheck(c.Name(), health.StatusHealthy, "Database query succeeded")
+}
@@ -0,0 +1,50 @@
+package dbutil_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/julianstephens/go-utils/dbutil"
+	"github.com/julianstephens/go-utils/dbutil/dbtest"
+	"github.com/julianstephens/go-utils/health"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestHealthCheck(t *testing.T) {
+	db := dbtest.NewDB(t, "CREATE TABLE items (id INTEGER PRIMARY KEY)")
+	ctx := context.Background()
+
+	t.Run("default query", func(t *testing.T) {
+		tst.AssertNoError(t, dbutil.HealthCheck(ctx, db, ""))
+	})
+
+	t.Run("custom query", func(t *testing.T) {
+		tst.AssertNoError(t, dbutil.HealthCheck(ctx, db, "SELECT COUNT(*) FROM items"))
+	})
+
+	t.Run("failing query", func(t *testing.T) {
+		err := dbutil.HealthCheck(ctx, db, "SELECT * FROM missing_table")
+		tst.AssertErrorContains(t, err, "health check failed")
+	})
+
+	t.Run("nil db", func(t *testing.T) {
+		err := dbutil.HealthCheck(ctx, nil, "")
+		tst.AssertErrorContains(t, err, "db is nil")
+	})
+}
+
+func TestHealthChecker(t *testing.T) {
+	db := dbtest.NewDB(t)
+
+	healthy := dbutil.NewHealthChecker(db, "")
+	tst.AssertEqual(t, healthy.Name(), "database")
+	tst.AssertEqual(t, healthy.Check().Status, health.StatusHealthy)
+
+	failing := dbutil.NewHealthChecker(db, "SELECT * FROM missing_table")
+	check := failing.Check()
+	tst.AssertEqual(t, check.Status, health.StatusError)
+	tst.AssertNotNil(t, check.Error)
+
+	report := health.RunChecks(healthy, failing)
+	tst.AssertEqual(t, report.ExitCode, health.ExitError)
+}
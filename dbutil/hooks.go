@@ -0,0 +1,144 @@
+package dbutil
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"time"
+
+	"github.com/julianstephens/go-utils/logger"
+)
+
+// QueryEvent describes a query executed through the package helpers.
+type QueryEvent struct {
+	Query string
+	Args  []any
+	// Duration covers the driver call that sends the query, up to the point
+	// it returns. Time spent iterating and scanning result rows afterwards is
+	// not included.
+	Duration time.Duration
+	Err      error
+}
+
+// QueryHooks are invoked around every query executed through the package
+// helpers (Exec, QueryRow, QueryRows, QueryRowScan, QuerySlice, QueryMap,
+// Exists, Count, Upsert and their Tx variants). Install them with SetQueryHooks.
+type QueryHooks struct {
+	// BeforeQuery is called before the query is sent to the database.
+	BeforeQuery func(ctx context.Context, query string, args []any)
+	// AfterQuery is called once the query has returned.
+	AfterQuery func(ctx context.Context, event QueryEvent)
+
+	// SlowQueryThreshold enables slow query detection when greater than zero.
+	// Queries whose QueryEvent.Duration exceeds the threshold are reported to
+	// OnSlowQuery; a query that returns quickly but streams many rows is not.
+	SlowQueryThreshold time.Duration
+	// OnSlowQuery is called for slow queries. If nil, a warning including the
+	// query text and duration is written to Logger.
+	OnSlowQuery func(ctx context.Context, event QueryEvent)
+	// Logger receives slow query warnings when OnSlowQuery is nil.
+	// Defaults to logger.GetDefaultLogger().
+	Logger *logger.Logger
+
+	// Now returns the current time. Defaults to time.Now; override it to
+	// control timing in tests.
+	Now func() time.Time
+}
+
+// queryHooks holds the package-wide hooks installed with SetQueryHooks.
+var queryHooks atomic.Pointer[QueryHooks]
+
+// SetQueryHooks installs package-wide query hooks. Passing nil removes them.
+func SetQueryHooks(hooks *QueryHooks) {
+	queryHooks.Store(hooks)
+}
+
+// GetQueryHooks returns the installed query hooks, or nil if none are set.
+func GetQueryHooks() *QueryHooks {
+	return queryHooks.Load()
+}
+
+// queryer is implemented by both *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// hookedQuery rebinds query to the package placeholder style and runs
+// QueryContext wrapped with the installed hooks.
+func hookedQuery(ctx context.Context, q queryer, query string, args []any) (*sql.Rows, error) {
+	query = rebindDefault(query)
+	done := startQuery(ctx, query, args)
+	rows, err := q.QueryContext(ctx, query, args...)
+	done(err)
+	return rows, err
+}
+
+// hookedQueryRow is like hookedQuery but runs QueryRowContext.
+func hookedQueryRow(ctx context.Context, q queryer, query string, args []any) *sql.Row {
+	query = rebindDefault(query)
+	done := startQuery(ctx, query, args)
+	row := q.QueryRowContext(ctx, query, args...)
+	done(row.Err())
+	return row
+}
+
+// hookedExec is like hookedQuery but runs ExecContext.
+func hookedExec(ctx context.Context, q queryer, query string, args []any) (sql.Result, error) {
+	query = rebindDefault(query)
+	done := startQuery(ctx, query, args)
+	result, err := q.ExecContext(ctx, query, args...)
+	done(err)
+	return result, err
+}
+
+// startQuery calls BeforeQuery and returns a function that completes the
+// query event, calling AfterQuery and reporting slow queries.
+func startQuery(ctx context.Context, query string, args []any) func(err error) {
+	hooks := queryHooks.Load()
+	if hooks == nil {
+		return func(error) {}
+	}
+
+	now := hooks.Now
+	if now == nil {
+		now = time.Now
+	}
+	if hooks.BeforeQuery != nil {
+		hooks.BeforeQuery(ctx, query, args)
+	}
+	start := now()
+
+	return func(err error) {
+		event := QueryEvent{
+			Query:    query,
+			Args:     args,
+			Duration: now().Sub(start),
+			Err:      err,
+		}
+		if hooks.AfterQuery != nil {
+			hooks.AfterQuery(ctx, event)
+		}
+		if hooks.SlowQueryThreshold > 0 && event.Duration > hooks.SlowQueryThreshold {
+			hooks.reportSlowQuery(ctx, event)
+		}
+	}
+}
+
+// reportSlowQuery passes a slow query to OnSlowQuery or logs a warning.
+func (h *QueryHooks) reportSlowQuery(ctx context.Context, event QueryEvent) {
+	if h.OnSlowQuery != nil {
+		h.OnSlowQuery(ctx, event)
+		return
+	}
+	log := h.Logger
+	if log == nil {
+		log = logger.GetDefaultLogger()
+	}
+	log.WithContext(ctx).WithFields(map[string]any{
+		"query":     event.Query,
+		"duration":  event.Duration.String(),
+		"threshold": h.SlowQueryThreshold.String(),
+	}).Warn("dbutil: slow query")
+}
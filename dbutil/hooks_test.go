@@ -0,0 +1,94 @@
+package dbutil_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/julianstephens/go-utils/dbutil"
+	"github.com/julianstephens/go-utils/dbutil/dbtest"
+	"github.com/julianstephens/go-utils/logger"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+// stepClock returns a clock that advances by the current step on every call.
+func stepClock(step *time.Duration) func() time.Time {
+	now := time.Unix(0, 0)
+	return func() time.Time {
+		now = now.Add(*step)
+		return now
+	}
+}
+
+func TestQueryHooks_SlowQuery(t *testing.T) {
+	db := dbtest.NewDB(t, "CREATE TABLE items (id INTEGER PRIMARY KEY)")
+	ctx := context.Background()
+
+	step := 10 * time.Millisecond
+	var slow []dbutil.QueryEvent
+	var after int
+	dbutil.SetQueryHooks(&dbutil.QueryHooks{
+		AfterQuery:         func(context.Context, dbutil.QueryEvent) { after++ },
+		SlowQueryThreshold: 100 * time.Millisecond,
+		OnSlowQuery: func(_ context.Context, event dbutil.QueryEvent) {
+			slow = append(slow, event)
+		},
+		Now: stepClock(&step),
+	})
+	t.Cleanup(func() { dbutil.SetQueryHooks(nil) })
+
+	// Fast query: the clock advances 10ms between start and end
+	_, err := dbutil.Exec(ctx, db, "INSERT INTO items (id) VALUES (?)", 1)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, len(slow), 0)
+
+	// Slow query: the clock advances 250ms
+	step = 250 * time.Millisecond
+	_, err = dbutil.Count(ctx, db, "SELECT COUNT(*) FROM items")
+	tst.RequireNoError(t, err)
+
+	tst.AssertEqual(t, after, 2)
+	tst.AssertEqual(t, len(slow), 1)
+	tst.AssertEqual(t, slow[0].Query, "SELECT COUNT(*) FROM items")
+	tst.AssertEqual(t, slow[0].Duration, 250*time.Millisecond)
+}
+
+func TestQueryHooks_SlowQueryLogs(t *testing.T) {
+	db := dbtest.NewDB(t)
+
+	var buf bytes.Buffer
+	step := time.Second
+	dbutil.SetQueryHooks(&dbutil.QueryHooks{
+		SlowQueryThreshold: 500 * time.Millisecond,
+		Logger:             logger.NewWithOptions(&buf, logrus.WarnLevel, &logrus.JSONFormatter{}),
+		Now:                stepClock(&step),
+	})
+	t.Cleanup(func() { dbutil.SetQueryHooks(nil) })
+
+	_, err := dbutil.Exists(context.Background(), db, "SELECT 1")
+	tst.RequireNoError(t, err)
+
+	out := buf.String()
+	tst.AssertTrue(t, strings.Contains(out, "slow query"), "warning should be logged")
+	tst.AssertTrue(t, strings.Contains(out, `"query":"SELECT 1"`), "log should include the query")
+	tst.AssertTrue(t, strings.Contains(out, `"duration":"1s"`), "log should include the duration")
+}
+
+func TestQueryHooks_BeforeQuery(t *testing.T) {
+	db := dbtest.NewDB(t)
+
+	var seen []string
+	dbutil.SetQueryHooks(&dbutil.QueryHooks{
+		BeforeQuery: func(_ context.Context, query string, _ []any) { seen = append(seen, query) },
+	})
+	t.Cleanup(func() { dbutil.SetQueryHooks(nil) })
+
+	var n int
+	tst.RequireNoError(t, dbutil.QueryRow(context.Background(), db, "SELECT 42").Scan(&n))
+	tst.AssertDeepEqual(t, seen, []string{"SELECT 42"})
+	tst.AssertNotNil(t, dbutil.GetQueryHooks(), "hooks should be installed")
+}
@@ -0,0 +1,84 @@
+package dbutil
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ExpandIn expands slice arguments into a matching number of ? placeholders so
+// they can be used in IN clauses. Each ? in query is paired with the argument
+// at the same position; when that argument is a slice, the placeholder is
+// replaced with one ? per element and the elements are spliced into the
+// returned arguments in order. Other arguments, including []byte and
+// values implementing driver.Valuer, are passed through unchanged.
+//
+// The returned query still uses ? placeholders, so it can be passed to Rebind
+// or to the package helpers, which rebind automatically. ExpandIn returns an
+// error when the number of placeholders does not match the number of
+// arguments or when a slice argument is empty, since "IN ()" is not valid SQL.
+func ExpandIn(query string, args ...any) (string, []any, error) {
+	lengths := make([]int, len(args))
+	expand := false
+	for i, arg := range args {
+		lengths[i] = -1
+		v, ok := inSliceValue(arg)
+		if !ok {
+			continue
+		}
+		if v.Len() == 0 {
+			return "", nil, fmt.Errorf("dbutil: expand in: argument %d is an empty slice", i+1)
+		}
+		lengths[i] = v.Len()
+		expand = true
+	}
+
+	count := 0
+	expanded := replacePlaceholders(query, func(n int) string {
+		count = n
+		if n > len(args) || lengths[n-1] < 0 {
+			return "?"
+		}
+		return strings.Repeat("?, ", lengths[n-1]-1) + "?"
+	})
+	if count != len(args) {
+		return "", nil, fmt.Errorf(
+			"dbutil: expand in: query has %d placeholders but %d arguments were given",
+			count,
+			len(args),
+		)
+	}
+	if !expand {
+		return query, args, nil
+	}
+
+	flat := make([]any, 0, len(args))
+	for i, arg := range args {
+		if lengths[i] < 0 {
+			flat = append(flat, arg)
+			continue
+		}
+		v := reflect.ValueOf(arg)
+		for j := 0; j < v.Len(); j++ {
+			flat = append(flat, v.Index(j).Interface())
+		}
+	}
+	return expanded, flat, nil
+}
+
+// inSliceValue reports whether arg should be expanded by ExpandIn and returns
+// its reflected value.
+func inSliceValue(arg any) (reflect.Value, bool) {
+	if arg == nil {
+		return reflect.Value{}, false
+	}
+	if _, ok := arg.(driver.Valuer); ok {
+		return reflect.Value{}, false
+	}
+	v := reflect.ValueOf(arg)
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 {
+		return reflect.Value{}, false
+	}
+	return v, true
+}
@@ -0,0 +1,48 @@
+package dbutil_test
+
+import (
+	"testing"
+
+	"github.com/julianstephens/go-utils/dbutil"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestExpandIn(t *testing.T) {
+	query, args, err := dbutil.ExpandIn(
+		"SELECT * FROM users WHERE org = ? AND id IN (?) AND status = ?",
+		"acme", []int{1, 2, 3}, "active",
+	)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, query, "SELECT * FROM users WHERE org = ? AND id IN (?, ?, ?) AND status = ?")
+	tst.AssertDeepEqual(t, args, []any{"acme", 1, 2, 3, "active"})
+
+	rebound := dbutil.Rebind(query, dbutil.PlaceholderDollar)
+	tst.AssertEqual(t, rebound, "SELECT * FROM users WHERE org = $1 AND id IN ($2, $3, $4) AND status = $5")
+}
+
+func TestExpandIn_PassThrough(t *testing.T) {
+	blob := []byte("raw")
+	query, args, err := dbutil.ExpandIn("UPDATE files SET data = ? WHERE name = '?' AND id = ?", blob, 7)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, query, "UPDATE files SET data = ? WHERE name = '?' AND id = ?")
+	tst.AssertDeepEqual(t, args, []any{blob, 7})
+}
+
+func TestExpandIn_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		args  []any
+	}{
+		{"empty slice", "SELECT * FROM t WHERE id IN (?)", []any{[]int{}}},
+		{"too few args", "SELECT * FROM t WHERE a = ? AND b IN (?)", []any{1}},
+		{"too many args", "SELECT * FROM t WHERE b IN (?)", []any{[]string{"x"}, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := dbutil.ExpandIn(tt.query, tt.args...)
+			tst.AssertErrorContains(t, err, "dbutil: expand in")
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package dbutil
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// returningClause matches a RETURNING keyword in a statement. It is matched
+// against unquotedSQL so quoted text and comments are ignored.
+var returningClause = regexp.MustCompile(`(?i)\bRETURNING\b`)
+
+// InsertReturningID runs an INSERT and returns the generated primary key,
+// hiding the difference between drivers. If query already has a RETURNING
+// clause, its single column is scanned as the ID. Otherwise, for
+// DialectPostgres "RETURNING id" is appended, and for DialectMySQL and
+// DialectSQLite the statement is executed and sql.Result.LastInsertId is
+// used. The dialect is the package-wide one set by SetDialect.
+func InsertReturningID(ctx context.Context, db *sql.DB, query string, args ...any) (int64, error) {
+	id, err := insertReturningID(ctx, db, query, args)
+	if err != nil {
+		return 0, fmt.Errorf("dbutil: insert returning id failed: %w", err)
+	}
+	return id, nil
+}
+
+// InsertReturningIDTx is like InsertReturningID but uses a transaction.
+func InsertReturningIDTx(ctx context.Context, tx *sql.Tx, query string, args ...any) (int64, error) {
+	id, err := insertReturningID(ctx, tx, query, args)
+	if err != nil {
+		return 0, fmt.Errorf("dbutil: insert returning id (tx) failed: %w", err)
+	}
+	return id, nil
+}
+
+func insertReturningID(ctx context.Context, q queryer, query string, args []any) (int64, error) {
+	if !returningClause.MatchString(unquotedSQL(query)) {
+		if GetDialect() != DialectPostgres {
+			result, err := hookedExec(ctx, q, query, args)
+			if err != nil {
+				return 0, err
+			}
+			return result.LastInsertId()
+		}
+		query = strings.TrimRight(strings.TrimSpace(query), ";") + " RETURNING id"
+	}
+
+	var id int64
+	if err := hookedQueryRow(ctx, q, query, args).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
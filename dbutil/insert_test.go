@@ -0,0 +1,68 @@
+package dbutil_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/julianstephens/go-utils/dbutil"
+	"github.com/julianstephens/go-utils/dbutil/dbtest"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestInsertReturningID_LastInsertID(t *testing.T) {
+	db := dbtest.NewDB(t, "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, email TEXT)")
+	ctx := context.Background()
+
+	dbutil.SetDialect(dbutil.DialectSQLite)
+	t.Cleanup(func() { dbutil.SetDialect(dbutil.DialectPostgres) })
+
+	id, err := dbutil.InsertReturningID(ctx, db, "INSERT INTO users (name, email) VALUES (?, ?)", "Alice", "alice@example.com")
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, id, int64(1))
+
+	err = dbutil.WithTransaction(ctx, db, func(tx *sql.Tx) error {
+		id, err = dbutil.InsertReturningIDTx(ctx, tx, "INSERT INTO users (name, email) VALUES (?, ?)", "Bob", "bob@example.com")
+		return err
+	})
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, id, int64(2))
+
+	// RETURNING inside a string literal is not a RETURNING clause
+	id, err = dbutil.InsertReturningID(ctx, db, "INSERT INTO users (name, email) VALUES ('returning', ?)", "r@example.com")
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, id, int64(3))
+
+	var name string
+	tst.RequireNoError(t, dbutil.QueryRow(ctx, db, "SELECT name FROM users WHERE id = ?", 2).Scan(&name))
+	tst.AssertEqual(t, name, "Bob")
+}
+
+func TestInsertReturningID_Returning(t *testing.T) {
+	db := dbtest.NewDB(t, "CREATE TABLE users (user_id INTEGER PRIMARY KEY, name TEXT, email TEXT)")
+	ctx := context.Background()
+
+	dbutil.SetDialect(dbutil.DialectSQLite)
+	t.Cleanup(func() { dbutil.SetDialect(dbutil.DialectPostgres) })
+
+	// An explicit RETURNING clause is used as-is, whatever the dialect
+	id, err := dbutil.InsertReturningID(ctx, db,
+		"INSERT INTO users (user_id, name, email) VALUES (?, ?, ?) returning user_id", 42, "Alice", "alice@example.com")
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, id, int64(42))
+}
+
+func TestInsertReturningID_PostgresAppendsReturning(t *testing.T) {
+	db := dbtest.NewDB(t, "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, email TEXT)",
+		"INSERT INTO users (id, name, email) VALUES (9, 'Existing', 'existing@example.com')")
+	ctx := context.Background()
+
+	// SQLite understands RETURNING, so it stands in for PostgreSQL here
+	tst.AssertEqual(t, dbutil.GetDialect(), dbutil.DialectPostgres)
+	id, err := dbutil.InsertReturningID(ctx, db, "INSERT INTO users (name, email) VALUES ('Bob', 'bob@example.com');")
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, id, int64(10))
+
+	_, err = dbutil.InsertReturningID(ctx, db, "INSERT INTO missing (name) VALUES ('x')")
+	tst.AssertErrorContains(t, err, "dbutil: insert returning id failed")
+}
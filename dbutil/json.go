@@ -0,0 +1,35 @@
+package dbutil
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// jsonColumn is a sql.Scanner that unmarshals a JSON/JSONB column into dest.
+// It is used for struct fields tagged db:"column,json". NULL columns leave
+// dest untouched so the field keeps its zero value.
+type jsonColumn struct {
+	dest any
+}
+
+// Scan implements sql.Scanner.
+func (j *jsonColumn) Scan(src any) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into JSON field", src)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, j.dest); err != nil {
+		return fmt.Errorf("failed to unmarshal JSON column: %w", err)
+	}
+	return nil
+}
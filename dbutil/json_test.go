@@ -0,0 +1,81 @@
+package dbutil_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/julianstephens/go-utils/dbutil"
+	"github.com/julianstephens/go-utils/dbutil/dbtest"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+type Metadata struct {
+	Tags  []string `json:"tags"`
+	Owner struct {
+		Name string `json:"name"`
+	} `json:"owner"`
+}
+
+type Document struct {
+	ID       int      `db:"id"`
+	Metadata Metadata `db:"metadata,json"`
+}
+
+const documentsSchema = "CREATE TABLE documents (id INTEGER PRIMARY KEY, metadata TEXT)"
+
+func TestQueryRowScan_JSONColumn(t *testing.T) {
+	db := dbtest.NewDB(t, documentsSchema)
+	ctx := context.Background()
+
+	_, err := db.Exec(`INSERT INTO documents (id, metadata) VALUES (1, '{"tags":["a","b"],"owner":{"name":"alice"}}')`)
+	tst.RequireNoError(t, err)
+
+	var doc Document
+	err = dbutil.QueryRowScan(ctx, db, &doc, "SELECT id, metadata FROM documents WHERE id = ?", 1)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, doc.Metadata.Tags, []string{"a", "b"})
+	tst.AssertEqual(t, doc.Metadata.Owner.Name, "alice")
+}
+
+func TestQuerySlice_JSONColumnNull(t *testing.T) {
+	db := dbtest.NewDB(t, documentsSchema)
+	ctx := context.Background()
+
+	_, err := db.Exec(`INSERT INTO documents (id, metadata) VALUES (1, NULL), (2, '{"tags":["x"]}')`)
+	tst.RequireNoError(t, err)
+
+	var docs []Document
+	err = dbutil.QuerySlice(ctx, db, &docs, "SELECT id, metadata FROM documents ORDER BY id")
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, len(docs), 2)
+	tst.AssertDeepEqual(t, docs[0].Metadata, Metadata{})
+	tst.AssertDeepEqual(t, docs[1].Metadata.Tags, []string{"x"})
+}
+
+func TestQueryRowScan_InvalidJSON(t *testing.T) {
+	db := dbtest.NewDB(t, documentsSchema)
+
+	_, err := db.Exec(`INSERT INTO documents (id, metadata) VALUES (1, 'not json')`)
+	tst.RequireNoError(t, err)
+
+	var doc Document
+	err = dbutil.QueryRowScan(context.Background(), db, &doc, "SELECT id, metadata FROM documents WHERE id = 1")
+	tst.AssertErrorContains(t, err, "failed to unmarshal JSON column")
+}
+
+func TestUpsert_JSONColumn(t *testing.T) {
+	db := dbtest.NewDB(t, documentsSchema)
+	ctx := context.Background()
+
+	dbutil.SetDialect(dbutil.DialectSQLite)
+	t.Cleanup(func() { dbutil.SetDialect(dbutil.DialectPostgres) })
+
+	doc := Document{ID: 1, Metadata: Metadata{Tags: []string{"new"}}}
+	_, err := dbutil.Upsert(ctx, db, "documents", doc, []string{"id"}, []string{"metadata"})
+	tst.RequireNoError(t, err)
+
+	var got Document
+	err = dbutil.QueryRowScan(ctx, db, &got, "SELECT id, metadata FROM documents WHERE id = 1")
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, got.Metadata.Tags, []string{"new"})
+}
@@ -0,0 +1,113 @@
+package dbutil
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// KeysetPage fetches up to limit rows of T ordered by a key column, starting
+// after cursor. Unlike OFFSET pagination, each page is an index range scan, so
+// performance does not degrade for pages deep into large tables.
+//
+// The key column is the field of T tagged with the "key" option (e.g.
+// `db:"id,key"`), or the field mapped to column "id" if none is tagged.
+// KeysetPage wraps baseQuery as a derived table, running
+// "SELECT * FROM (baseQuery) AS page WHERE key > ? ORDER BY key LIMIT n", so
+// any predicates in baseQuery (including OR conditions and subqueries) are
+// kept intact. baseQuery must select the key column and should not have its
+// own ORDER BY or LIMIT. args bind baseQuery's placeholders. A nil cursor
+// fetches the first page.
+//
+// The cursor placeholder follows baseQuery: ? if baseQuery uses ? placeholders
+// or takes no args (rebound by SetPlaceholderStyle like any other query), and
+// otherwise a placeholder in the style SetDialect describes for generated SQL,
+// numbered after args (e.g., $2 for "... WHERE archived = $1").
+//
+// nextCursor is the key of the last row returned, to be passed back as cursor
+// for the following page. It is nil once fewer than limit rows are returned.
+func KeysetPage[T any](
+	ctx context.Context,
+	db *sql.DB,
+	baseQuery string,
+	cursor any,
+	limit int,
+	args ...any,
+) (page []T, nextCursor any, err error) {
+	if limit <= 0 {
+		return nil, nil, fmt.Errorf("dbutil: keyset page limit must be positive, got %d", limit)
+	}
+
+	elemType := reflect.TypeOf((*T)(nil)).Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("dbutil: keyset page type must be a struct or pointer to struct")
+	}
+	key, err := keyField(structType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	base := strings.TrimRight(strings.TrimSpace(baseQuery), ";")
+	query := "SELECT * FROM (" + base + ") AS page"
+	queryArgs := append([]any{}, args...)
+	if cursor != nil {
+		query += " WHERE page." + key.Column + " > " + cursorPlaceholder(base, len(args))
+		queryArgs = append(queryArgs, cursor)
+	}
+	query += " ORDER BY page." + key.Column + " LIMIT " + strconv.Itoa(limit)
+
+	if err := QuerySlice(ctx, db, &page, query, queryArgs...); err != nil {
+		return nil, nil, err
+	}
+
+	if len(page) < limit {
+		return page, nil, nil
+	}
+	last := reflect.ValueOf(page[len(page)-1])
+	if last.Kind() == reflect.Pointer {
+		last = last.Elem()
+	}
+	return page, last.Field(key.Index).Interface(), nil
+}
+
+// cursorPlaceholder returns the bind parameter for the cursor argument that
+// follows nargs arguments of baseQuery, matching baseQuery's placeholders.
+func cursorPlaceholder(baseQuery string, nargs int) string {
+	questions := 0
+	replacePlaceholders(baseQuery, func(int) string {
+		questions++
+		return "?"
+	})
+	if nargs == 0 || questions > 0 {
+		return "?"
+	}
+
+	return generatedPlaceholderStyle().placeholder(nargs + 1)
+}
+
+// keyField returns the field of t used as the keyset pagination key.
+func keyField(t reflect.Type) (structField, error) {
+	fields, err := getStructFields(t)
+	if err != nil {
+		return structField{}, fmt.Errorf("dbutil: failed to analyze struct: %w", err)
+	}
+
+	for _, f := range fields {
+		if f.Key {
+			return f, nil
+		}
+	}
+	for _, f := range fields {
+		if f.Column == "id" {
+			return f, nil
+		}
+	}
+	return structField{}, fmt.Errorf("dbutil: %s has no key field; tag one with db:\"column,key\"", t)
+}
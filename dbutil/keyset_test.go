@@ -0,0 +1,94 @@
+package dbutil_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/julianstephens/go-utils/dbutil"
+	"github.com/julianstephens/go-utils/dbutil/dbtest"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+type keysetItem struct {
+	Seq  int64  `db:"seq,key"`
+	Name string `db:"name"`
+}
+
+func TestKeysetPage(t *testing.T) {
+	db := dbtest.NewDB(t,
+		"CREATE TABLE items (seq INTEGER PRIMARY KEY, name TEXT, archived INTEGER DEFAULT 0)",
+		"INSERT INTO items (seq, name) VALUES (1, 'a'), (2, 'b'), (3, 'c'), (4, 'd'), (5, 'e')",
+	)
+	ctx := context.Background()
+	const base = "SELECT seq, name FROM items"
+
+	first, cursor, err := dbutil.KeysetPage[keysetItem](ctx, db, base, nil, 2)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, first, []keysetItem{{1, "a"}, {2, "b"}})
+	tst.AssertDeepEqual(t, cursor, any(int64(2)))
+
+	second, cursor, err := dbutil.KeysetPage[keysetItem](ctx, db, base, cursor, 2)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, second, []keysetItem{{3, "c"}, {4, "d"}})
+
+	last, cursor, err := dbutil.KeysetPage[keysetItem](ctx, db, base, cursor, 2)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, last, []keysetItem{{5, "e"}})
+	tst.AssertNil(t, cursor)
+}
+
+func TestKeysetPage_ExistingWhere(t *testing.T) {
+	db := dbtest.NewDB(t,
+		"CREATE TABLE items (seq INTEGER PRIMARY KEY, name TEXT, archived INTEGER DEFAULT 0)",
+		"INSERT INTO items (seq, name, archived) VALUES (1, 'a', 0), (2, 'b', 1), (3, 'c', 0), (4, 'd', 0)",
+	)
+	ctx := context.Background()
+	const base = "SELECT seq, name FROM items WHERE archived = ?"
+
+	page, cursor, err := dbutil.KeysetPage[*keysetItem](ctx, db, base, int64(1), 2, 0)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, page, []*keysetItem{{3, "c"}, {4, "d"}})
+	tst.AssertDeepEqual(t, cursor, any(int64(4)))
+
+	_, _, err = dbutil.KeysetPage[keysetItem](ctx, db, base, nil, 0)
+	tst.AssertErrorContains(t, err, "limit must be positive")
+}
+
+func TestKeysetPage_DollarPlaceholders(t *testing.T) {
+	db := dbtest.NewDB(t,
+		"CREATE TABLE items (seq INTEGER PRIMARY KEY, name TEXT, archived INTEGER DEFAULT 0)",
+		"INSERT INTO items (seq, name, archived) VALUES (1, 'a', 0), (2, 'b', 1), (3, 'c', 0), (4, 'd', 0)",
+	)
+	ctx := context.Background()
+	const base = "SELECT seq, name FROM items WHERE archived = $1"
+
+	// The cursor is bound as $2 rather than ?, matching the base query
+	page, cursor, err := dbutil.KeysetPage[keysetItem](ctx, db, base, int64(1), 2, 0)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, page, []keysetItem{{3, "c"}, {4, "d"}})
+	tst.AssertDeepEqual(t, cursor, any(int64(4)))
+}
+
+func TestKeysetPage_OrPredicate(t *testing.T) {
+	db := dbtest.NewDB(t,
+		"CREATE TABLE items (seq INTEGER PRIMARY KEY, name TEXT, archived INTEGER DEFAULT 0)",
+		"INSERT INTO items (seq, name, archived) VALUES (1, 'a', 0), (2, 'b', 1), (3, 'c', 2), (4, 'd', 0), (5, 'e', 1)",
+	)
+	ctx := context.Background()
+	// Without the cursor condition applying to both branches, the first branch
+	// would match seq 1 and 4 on every page
+	const base = "SELECT seq, name FROM items WHERE archived = ? OR archived = ?"
+
+	var all []keysetItem
+	var cursor any
+	for pages := 0; pages < 10; pages++ {
+		page, next, err := dbutil.KeysetPage[keysetItem](ctx, db, base, cursor, 1, 0, 1)
+		tst.RequireNoError(t, err)
+		all = append(all, page...)
+		if next == nil {
+			break
+		}
+		cursor = next
+	}
+	tst.AssertDeepEqual(t, all, []keysetItem{{1, "a"}, {2, "b"}, {4, "d"}, {5, "e"}})
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"strings"
+	"unicode"
 )
 
 // QuerySlice executes a query and scans all rows into a slice of structs.
@@ -19,6 +21,7 @@ func QuerySliceTx(ctx context.Context, tx *sql.Tx, dest any, query string, args
 }
 
 // QuerySliceWithOptions executes a query and scans all rows into a slice of structs with options.
+// The query, including scanning, is bounded by opts.Timeout.
 func QuerySliceWithOptions(
 	ctx context.Context,
 	db *sql.DB,
@@ -27,8 +30,10 @@ func QuerySliceWithOptions(
 	opts *QueryOptions,
 	args ...any,
 ) error {
+	ctx, cancel := withQueryTimeout(ctx, opts)
+	defer cancel()
 	return querySliceImpl(ctx, func() (*sql.Rows, error) {
-		return db.QueryContext(ctx, query, args...)
+		return hookedQuery(ctx, db, query, args)
 	}, dest, opts)
 }
 
@@ -41,8 +46,10 @@ func QuerySliceWithOptionsTx(
 	opts *QueryOptions,
 	args ...any,
 ) error {
+	ctx, cancel := withQueryTimeout(ctx, opts)
+	defer cancel()
 	return querySliceImpl(ctx, func() (*sql.Rows, error) {
-		return tx.QueryContext(ctx, query, args...)
+		return hookedQuery(ctx, tx, query, args)
 	}, dest, opts)
 }
 
@@ -121,7 +128,7 @@ func querySliceImpl(_ context.Context, queryFn func() (*sql.Rows, error), dest a
 			if !fieldValue.CanAddr() {
 				return fmt.Errorf("dbutil: field %s cannot be addressed", field.Name)
 			}
-			scanDests[i] = fieldValue.Addr().Interface()
+			scanDests[i] = scanDest(fieldValue, field)
 		}
 
 		// Scan row into struct
@@ -151,31 +158,65 @@ func querySliceImpl(_ context.Context, queryFn func() (*sql.Rows, error), dest a
 // QueryMap executes a query and returns the first row as a map[string]interface{}.
 func QueryMap(ctx context.Context, db *sql.DB, query string, args ...any) (map[string]any, error) {
 	return queryMapImpl(ctx, func() (*sql.Rows, error) {
-		return db.QueryContext(ctx, query, args...)
+		return hookedQuery(ctx, db, query, args)
 	})
 }
 
 // QueryMapTx is like QueryMap but uses a transaction.
 func QueryMapTx(ctx context.Context, tx *sql.Tx, query string, args ...any) (map[string]any, error) {
 	return queryMapImpl(ctx, func() (*sql.Rows, error) {
-		return tx.QueryContext(ctx, query, args...)
+		return hookedQuery(ctx, tx, query, args)
 	})
 }
 
 // QueryMaps executes a query and returns all rows as []map[string]interface{}.
 func QueryMaps(ctx context.Context, db *sql.DB, query string, args ...any) ([]map[string]any, error) {
 	return queryMapsImpl(ctx, func() (*sql.Rows, error) {
-		return db.QueryContext(ctx, query, args...)
+		return hookedQuery(ctx, db, query, args)
 	})
 }
 
 // QueryMapsTx is like QueryMaps but uses a transaction.
 func QueryMapsTx(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]map[string]any, error) {
 	return queryMapsImpl(ctx, func() (*sql.Rows, error) {
-		return tx.QueryContext(ctx, query, args...)
+		return hookedQuery(ctx, tx, query, args)
 	})
 }
 
+// QueryMapsWithOptions is like QueryMaps but normalizes map keys according to
+// opts.KeyCase, so generic consumers get predictable keys regardless of how the
+// driver reports column names. It stops after opts.MaxRows rows and is bounded
+// by opts.Timeout. It returns an error if two columns normalize to the same
+// key.
+func QueryMapsWithOptions(
+	ctx context.Context,
+	db *sql.DB,
+	query string,
+	opts *QueryOptions,
+	args ...any,
+) ([]map[string]any, error) {
+	ctx, cancel := withQueryTimeout(ctx, opts)
+	defer cancel()
+	return queryMapsWithOptionsImpl(ctx, func() (*sql.Rows, error) {
+		return hookedQuery(ctx, db, query, args)
+	}, opts)
+}
+
+// QueryMapsWithOptionsTx is like QueryMapsWithOptions but uses a transaction.
+func QueryMapsWithOptionsTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	query string,
+	opts *QueryOptions,
+	args ...any,
+) ([]map[string]any, error) {
+	ctx, cancel := withQueryTimeout(ctx, opts)
+	defer cancel()
+	return queryMapsWithOptionsImpl(ctx, func() (*sql.Rows, error) {
+		return hookedQuery(ctx, tx, query, args)
+	}, opts)
+}
+
 // queryMapImpl is the common implementation for QueryMap functions.
 func queryMapImpl(_ context.Context, queryFn func() (*sql.Rows, error)) (map[string]any, error) {
 	rows, err := queryFn()
@@ -218,6 +259,147 @@ func queryMapsImpl(_ context.Context, queryFn func() (*sql.Rows, error)) ([]map[
 	return result, nil
 }
 
+// queryMapsWithOptionsImpl is the common implementation for QueryMapsWithOptions functions.
+func queryMapsWithOptionsImpl(
+	_ context.Context,
+	queryFn func() (*sql.Rows, error),
+	opts *QueryOptions,
+) ([]map[string]any, error) {
+	if opts == nil {
+		opts = DefaultQueryOptions()
+	}
+
+	var fieldNames map[string]string
+	if opts.KeyCase == KeyCaseField {
+		var err error
+		if fieldNames, err = reverseFieldMap(opts); err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := queryFn()
+	if err != nil {
+		return nil, fmt.Errorf("dbutil: query maps failed: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []map[string]any
+	for rows.Next() {
+		if opts.MaxRows > 0 && len(result) >= opts.MaxRows {
+			break
+		}
+
+		rowMap, err := scanRowToMap(rows)
+		if err != nil {
+			return nil, err
+		}
+		if opts.KeyCase != KeyCaseAsIs {
+			if rowMap, err = normalizeMapKeys(rowMap, opts.KeyCase, fieldNames); err != nil {
+				return nil, err
+			}
+		}
+		result = append(result, rowMap)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("dbutil: query maps iteration failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// reverseFieldMap maps each column of opts.FieldStruct to its field name,
+// using the db tag or opts.FieldMapper as the struct scanners do.
+func reverseFieldMap(opts *QueryOptions) (map[string]string, error) {
+	t := reflect.TypeOf(opts.FieldStruct)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf(
+			"dbutil: KeyCaseField requires QueryOptions.FieldStruct to be a struct, got %T",
+			opts.FieldStruct,
+		)
+	}
+
+	mapper := opts.FieldMapper
+	if mapper == nil {
+		mapper = DefaultFieldMapper
+	}
+
+	names := make(map[string]string)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+		column, _, _ := strings.Cut(tag, ",")
+		if column == "" {
+			column = mapper(field.Name)
+		}
+		if _, exists := names[column]; !exists {
+			names[column] = field.Name
+		}
+	}
+	return names, nil
+}
+
+// normalizeMapKeys returns a copy of row with its keys converted to keyCase.
+// fieldNames maps columns to keys for KeyCaseField.
+func normalizeMapKeys(row map[string]any, keyCase KeyCase, fieldNames map[string]string) (map[string]any, error) {
+	result := make(map[string]any, len(row))
+	for column, value := range row {
+		key := normalizeKey(column, keyCase)
+		if keyCase == KeyCaseField {
+			if name, ok := fieldNames[column]; ok {
+				key = name
+			}
+		}
+		if _, exists := result[key]; exists {
+			return nil, fmt.Errorf("dbutil: columns normalize to duplicate map key %q", key)
+		}
+		result[key] = value
+	}
+	return result, nil
+}
+
+// normalizeKey converts a column name to keyCase.
+func normalizeKey(column string, keyCase KeyCase) string {
+	switch keyCase {
+	case KeyCaseLower:
+		return strings.ToLower(column)
+	case KeyCaseSnake:
+		return toSnakeCase(column)
+	default:
+		return column
+	}
+}
+
+// toSnakeCase converts a name to snake_case, keeping acronyms together
+// (e.g., "UserID" -> "user_id", "HTTPStatus" -> "http_status").
+func toSnakeCase(name string) string {
+	var result strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if r == ' ' || r == '-' {
+			r = '_'
+		}
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				result.WriteByte('_')
+			}
+		}
+		result.WriteRune(unicode.ToLower(r))
+	}
+	return result.String()
+}
+
 // scanRowToMap scans a single row into a map[string]interface{}.
 func scanRowToMap(rows *sql.Rows) (map[string]any, error) {
 	columns, err := rows.Columns()
@@ -273,14 +455,14 @@ func scanRowToMap(rows *sql.Rows) (map[string]any, error) {
 // Exists checks if a query returns any rows.
 func Exists(ctx context.Context, db *sql.DB, query string, args ...any) (bool, error) {
 	return existsImpl(ctx, func() (*sql.Rows, error) {
-		return db.QueryContext(ctx, query, args...)
+		return hookedQuery(ctx, db, query, args)
 	})
 }
 
 // ExistsTx is like Exists but uses a transaction.
 func ExistsTx(ctx context.Context, tx *sql.Tx, query string, args ...any) (bool, error) {
 	return existsImpl(ctx, func() (*sql.Rows, error) {
-		return tx.QueryContext(ctx, query, args...)
+		return hookedQuery(ctx, tx, query, args)
 	})
 }
 
@@ -303,14 +485,14 @@ func existsImpl(_ context.Context, queryFn func() (*sql.Rows, error)) (bool, err
 // Count executes a COUNT query and returns the result.
 func Count(ctx context.Context, db *sql.DB, query string, args ...any) (int64, error) {
 	return countImpl(ctx, func() *sql.Row {
-		return db.QueryRowContext(ctx, query, args...)
+		return hookedQueryRow(ctx, db, query, args)
 	})
 }
 
 // CountTx is like Count but uses a transaction.
 func CountTx(ctx context.Context, tx *sql.Tx, query string, args ...any) (int64, error) {
 	return countImpl(ctx, func() *sql.Row {
-		return tx.QueryRowContext(ctx, query, args...)
+		return hookedQueryRow(ctx, tx, query, args)
 	})
 }
 
@@ -3,9 +3,12 @@ package dbutil_test
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"testing"
 
 	"github.com/julianstephens/go-utils/dbutil"
+	"github.com/julianstephens/go-utils/dbutil/dbtest"
+	tst "github.com/julianstephens/go-utils/tests"
 )
 
 // Test query slice parameter validation
@@ -95,3 +98,48 @@ func TestCount(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryMapsWithOptionsKeyCase(t *testing.T) {
+	db := dbtest.NewDB(t,
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)",
+		"INSERT INTO users (id, name) VALUES (1, 'Alice'), (2, 'Bob')",
+	)
+	ctx := context.Background()
+	query := `SELECT id AS "UserID", name AS "DisplayName" FROM users ORDER BY id`
+
+	rows, err := dbutil.QueryMapsWithOptions(ctx, db, query, &dbutil.QueryOptions{KeyCase: dbutil.KeyCaseLower})
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, len(rows), 2)
+	tst.AssertDeepEqual(t, rows[0], map[string]any{"userid": int64(1), "displayname": "Alice"})
+
+	rows, err = dbutil.QueryMapsWithOptions(ctx, db, query, &dbutil.QueryOptions{KeyCase: dbutil.KeyCaseSnake, MaxRows: 1})
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, rows, []map[string]any{{"user_id": int64(1), "display_name": "Alice"}})
+
+	type user struct {
+		UserID      int64 `db:"user_id"`
+		DisplayName string
+		Secret      string `db:"-"`
+	}
+	rows, err = dbutil.QueryMapsWithOptions(ctx, db,
+		"SELECT id AS user_id, name AS display_name, name AS secret FROM users WHERE id = ?",
+		&dbutil.QueryOptions{KeyCase: dbutil.KeyCaseField, FieldStruct: user{}}, 2)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, rows, []map[string]any{{"UserID": int64(2), "DisplayName": "Bob", "secret": "Bob"}})
+
+	rows, err = dbutil.QueryMapsWithOptions(ctx, db, `SELECT name AS "DISPLAYNAME" FROM users WHERE id = ?`,
+		&dbutil.QueryOptions{KeyCase: dbutil.KeyCaseField, FieldMapper: strings.ToUpper, FieldStruct: &user{}}, 1)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, rows, []map[string]any{{"DisplayName": "Alice"}})
+
+	_, err = dbutil.QueryMapsWithOptions(ctx, db, query, &dbutil.QueryOptions{KeyCase: dbutil.KeyCaseField})
+	tst.AssertErrorContains(t, err, "FieldStruct")
+
+	rows, err = dbutil.QueryMapsWithOptions(ctx, db, query, nil)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, rows[1], map[string]any{"UserID": int64(2), "DisplayName": "Bob"})
+
+	_, err = dbutil.QueryMapsWithOptions(ctx, db, `SELECT id AS "ID", name AS "id" FROM users`,
+		&dbutil.QueryOptions{KeyCase: dbutil.KeyCaseLower})
+	tst.AssertErrorContains(t, err, "duplicate map key")
+}
@@ -0,0 +1,169 @@
+package dbutil
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// PlaceholderStyle identifies the bind parameter syntax of a database driver.
+type PlaceholderStyle int
+
+const (
+	// PlaceholderQuestion uses ? placeholders (MySQL, SQLite). Rebind leaves
+	// queries unchanged for this style.
+	PlaceholderQuestion PlaceholderStyle = iota
+	// PlaceholderDollar uses $1, $2, ... placeholders (PostgreSQL).
+	PlaceholderDollar
+	// PlaceholderColon uses :1, :2, ... placeholders (Oracle).
+	PlaceholderColon
+	// PlaceholderAt uses @p1, @p2, ... placeholders (SQL Server).
+	PlaceholderAt
+)
+
+// String returns the name of the placeholder style.
+func (s PlaceholderStyle) String() string {
+	switch s {
+	case PlaceholderQuestion:
+		return "question"
+	case PlaceholderDollar:
+		return "dollar"
+	case PlaceholderColon:
+		return "colon"
+	case PlaceholderAt:
+		return "at"
+	default:
+		return "unknown"
+	}
+}
+
+// PlaceholderStyle returns the placeholder style used by the dialect.
+func (d Dialect) PlaceholderStyle() PlaceholderStyle {
+	if d == DialectPostgres {
+		return PlaceholderDollar
+	}
+	return PlaceholderQuestion
+}
+
+// defaultPlaceholderStyle holds the package-wide style used to rebind queries.
+var defaultPlaceholderStyle atomic.Int32
+
+// SetPlaceholderStyle sets the package-wide placeholder style. When it is not
+// PlaceholderQuestion, queries run through the package helpers (Exec, QueryRow,
+// QueryRows, QuerySlice, ...) are rebound from ? placeholders to this style
+// before execution, so the same query text works across drivers. The style
+// also replaces the dialect's placeholders in SQL generated by Upsert,
+// UpdateChanged, and KeysetPage. The default is PlaceholderQuestion, which
+// disables rebinding.
+func SetPlaceholderStyle(style PlaceholderStyle) {
+	defaultPlaceholderStyle.Store(int32(style))
+}
+
+// GetPlaceholderStyle returns the package-wide placeholder style.
+func GetPlaceholderStyle() PlaceholderStyle {
+	return PlaceholderStyle(defaultPlaceholderStyle.Load())
+}
+
+// Rebind converts ? placeholders in query to the given style, numbering them
+// from 1 in order of appearance. Question marks inside quoted strings or
+// identifiers ('...', "...", `...`) and comments (-- and /* */) are left
+// untouched. Rebind does not recognise operators that use ?, such as
+// PostgreSQL's jsonb ? operator.
+func Rebind(query string, style PlaceholderStyle) string {
+	if style == PlaceholderQuestion || !strings.Contains(query, "?") {
+		return query
+	}
+	return replacePlaceholders(query, style.placeholder)
+}
+
+// replacePlaceholders replaces each ? placeholder in query with repl(n), where
+// n numbers the placeholders from 1 in order of appearance. Question marks
+// inside quoted strings or identifiers and comments are copied unchanged.
+func replacePlaceholders(query string, repl func(n int) string) string {
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n := 0
+
+	scanSQL(query, func(c byte) {
+		if c == '?' {
+			n++
+			b.WriteString(repl(n))
+		} else {
+			b.WriteByte(c)
+		}
+	}, func(section string) {
+		b.WriteString(section)
+	})
+	return b.String()
+}
+
+// unquotedSQL returns query with each quoted string or identifier and each
+// comment replaced by a single space, so keywords can be matched in the SQL
+// text alone.
+func unquotedSQL(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+	scanSQL(query, func(c byte) {
+		b.WriteByte(c)
+	}, func(string) {
+		b.WriteByte(' ')
+	})
+	return b.String()
+}
+
+// scanSQL walks query, calling code for each byte of SQL text and skip for
+// each quoted string or identifier ('...', "...", `...`) and comment (-- and
+// /* */). An unterminated section extends to the end of query.
+func scanSQL(query string, code func(c byte), skip func(section string)) {
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			// Doubled quotes are handled as two adjacent sections.
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				skip(query[i:])
+				return
+			}
+			skip(query[i : i+end+2])
+			i += end + 1
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				skip(query[i:])
+				return
+			}
+			skip(query[i : i+end+1])
+			i += end
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				skip(query[i:])
+				return
+			}
+			skip(query[i : i+end+4])
+			i += end + 3
+		default:
+			code(c)
+		}
+	}
+}
+
+// placeholder returns the bind parameter for the n-th (1-based) argument.
+func (s PlaceholderStyle) placeholder(n int) string {
+	switch s {
+	case PlaceholderDollar:
+		return "$" + strconv.Itoa(n)
+	case PlaceholderColon:
+		return ":" + strconv.Itoa(n)
+	case PlaceholderAt:
+		return "@p" + strconv.Itoa(n)
+	default:
+		return "?"
+	}
+}
+
+// rebindDefault rebinds query using the package-wide placeholder style.
+func rebindDefault(query string) string {
+	return Rebind(query, GetPlaceholderStyle())
+}
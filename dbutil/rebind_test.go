@@ -0,0 +1,105 @@
+package dbutil_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/julianstephens/go-utils/dbutil"
+	"github.com/julianstephens/go-utils/dbutil/dbtest"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestRebind(t *testing.T) {
+	query := "SELECT * FROM users WHERE id = ? AND status IN (?, ?)"
+
+	tests := []struct {
+		name  string
+		style dbutil.PlaceholderStyle
+		want  string
+	}{
+		{"dollar", dbutil.PlaceholderDollar, "SELECT * FROM users WHERE id = $1 AND status IN ($2, $3)"},
+		{"colon", dbutil.PlaceholderColon, "SELECT * FROM users WHERE id = :1 AND status IN (:2, :3)"},
+		{"at", dbutil.PlaceholderAt, "SELECT * FROM users WHERE id = @p1 AND status IN (@p2, @p3)"},
+		{"question", dbutil.PlaceholderQuestion, query},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tst.AssertEqual(t, dbutil.Rebind(query, tt.style), tt.want)
+		})
+	}
+}
+
+func TestRebind_SkipsLiteralsAndComments(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "string literal",
+			query: "SELECT 'why?' AS q, name FROM t WHERE id = ?",
+			want:  "SELECT 'why?' AS q, name FROM t WHERE id = $1",
+		},
+		{
+			name:  "escaped quote in literal",
+			query: "UPDATE t SET note = 'it''s ok?' WHERE a = ? AND b = ?",
+			want:  "UPDATE t SET note = 'it''s ok?' WHERE a = $1 AND b = $2",
+		},
+		{
+			name:  "quoted identifier",
+			query: `SELECT "col?" FROM t WHERE x = ?`,
+			want:  `SELECT "col?" FROM t WHERE x = $1`,
+		},
+		{
+			name:  "comments",
+			query: "SELECT a -- why?\nFROM t /* really? */ WHERE a = ?",
+			want:  "SELECT a -- why?\nFROM t /* really? */ WHERE a = $1",
+		},
+		{
+			name:  "unterminated literal",
+			query: "SELECT ? WHERE a = 'oops?",
+			want:  "SELECT $1 WHERE a = 'oops?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tst.AssertEqual(t, dbutil.Rebind(tt.query, dbutil.PlaceholderDollar), tt.want)
+		})
+	}
+}
+
+func TestSetPlaceholderStyle(t *testing.T) {
+	db := dbtest.NewDB(t)
+
+	var seen string
+	dbutil.SetQueryHooks(&dbutil.QueryHooks{
+		BeforeQuery: func(_ context.Context, query string, _ []any) { seen = query },
+	})
+	dbutil.SetPlaceholderStyle(dbutil.PlaceholderColon)
+	t.Cleanup(func() {
+		dbutil.SetQueryHooks(nil)
+		dbutil.SetPlaceholderStyle(dbutil.PlaceholderQuestion)
+	})
+
+	tst.AssertEqual(t, dbutil.GetPlaceholderStyle(), dbutil.PlaceholderColon)
+
+	// The SQLite driver treats :N as named parameters, so only the rebound
+	// query text is checked here.
+	_, _ = dbutil.Exec(context.Background(), db, "SELECT ? + ?", 2, 3)
+	tst.AssertEqual(t, seen, "SELECT :1 + :2")
+
+	// Generated SQL uses the same style, whatever the dialect's placeholders
+	type item struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	_, _ = dbutil.Upsert(context.Background(), db, "items", item{ID: 1, Name: "a"}, []string{"id"}, []string{"name"})
+	tst.AssertEqual(t, seen,
+		"INSERT INTO items (id, name) VALUES (:1, :2) ON CONFLICT (id) DO UPDATE SET name = excluded.name")
+
+	tst.AssertEqual(t, dbutil.DialectPostgres.PlaceholderStyle(), dbutil.PlaceholderDollar)
+	tst.AssertEqual(t, dbutil.DialectMySQL.PlaceholderStyle(), dbutil.PlaceholderQuestion)
+	tst.AssertEqual(t, dbutil.PlaceholderAt.String(), "at")
+}
@@ -0,0 +1,115 @@
+package dbutil
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/julianstephens/go-utils/health"
+)
+
+// DefaultPoolUtilizationThreshold is the InUse/MaxOpen ratio above which
+// PoolChecker reports a warning.
+const DefaultPoolUtilizationThreshold = 0.8
+
+// PoolStatsSnapshot is a point-in-time view of a connection pool's state.
+type PoolStatsSnapshot struct {
+	MaxOpen           int           // Maximum open connections (0 means unlimited)
+	Open              int           // Established connections, in use and idle
+	InUse             int           // Connections currently in use
+	Idle              int           // Idle connections
+	WaitCount         int64         // Total number of connections waited for
+	WaitDuration      time.Duration // Total time blocked waiting for a connection
+	MaxIdleClosed     int64         // Connections closed due to SetMaxIdleConns
+	MaxIdleTimeClosed int64         // Connections closed due to SetConnMaxIdleTime
+	MaxLifetimeClosed int64         // Connections closed due to SetConnMaxLifetime
+	// Utilization is InUse/MaxOpen in the range [0, 1]. It is always 0 for
+	// pools without a MaxOpen limit, since saturation cannot occur.
+	Utilization float64
+}
+
+// PoolStats returns a snapshot of db's connection pool statistics.
+func PoolStats(db *sql.DB) PoolStatsSnapshot {
+	return NewPoolStatsSnapshot(db.Stats())
+}
+
+// NewPoolStatsSnapshot converts raw sql.DBStats into a PoolStatsSnapshot and
+// computes the pool's utilization.
+func NewPoolStatsSnapshot(stats sql.DBStats) PoolStatsSnapshot {
+	snapshot := PoolStatsSnapshot{
+		MaxOpen:           stats.MaxOpenConnections,
+		Open:              stats.OpenConnections,
+		InUse:             stats.InUse,
+		Idle:              stats.Idle,
+		WaitCount:         stats.WaitCount,
+		WaitDuration:      stats.WaitDuration,
+		MaxIdleClosed:     stats.MaxIdleClosed,
+		MaxIdleTimeClosed: stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed: stats.MaxLifetimeClosed,
+	}
+	if stats.MaxOpenConnections > 0 {
+		snapshot.Utilization = float64(stats.InUse) / float64(stats.MaxOpenConnections)
+	}
+	return snapshot
+}
+
+// HealthStatus reports StatusWarning when Utilization exceeds threshold and
+// StatusHealthy otherwise.
+func (s PoolStatsSnapshot) HealthStatus(threshold float64) health.Status {
+	if s.Utilization > threshold {
+		return health.StatusWarning
+	}
+	return health.StatusHealthy
+}
+
+// PoolChecker adapts PoolStats to the health.Checker interface, warning when
+// the pool is close to saturation.
+type PoolChecker struct {
+	// CheckName is the name reported in health check results (default: "database_pool").
+	CheckName string
+	// DB is the database whose pool is inspected.
+	DB *sql.DB
+	// Threshold is the utilization above which a warning is reported
+	// (default: DefaultPoolUtilizationThreshold).
+	Threshold float64
+}
+
+// NewPoolChecker creates a health.Checker that warns when db's pool utilization
+// exceeds threshold. A threshold of 0 uses DefaultPoolUtilizationThreshold.
+func NewPoolChecker(db *sql.DB, threshold float64) *PoolChecker {
+	return &PoolChecker{
+		CheckName: "database_pool",
+		DB:        db,
+		Threshold: threshold,
+	}
+}
+
+// Name returns the name of this checker.
+func (c *PoolChecker) Name() string {
+	if c.CheckName == "" {
+		return "database_pool"
+	}
+	return c.CheckName
+}
+
+// Check inspects the pool statistics and reports the result.
+func (c *PoolChecker) Check() health.Check {
+	if c.DB == nil {
+		return health.NewCheckWithError(c.Name(), health.StatusError, "Database pool unavailable",
+			fmt.Errorf("dbutil: pool check failed: db is nil"))
+	}
+
+	threshold := c.Threshold
+	if threshold <= 0 {
+		threshold = DefaultPoolUtilizationThreshold
+	}
+
+	stats := PoolStats(c.DB)
+	status := stats.HealthStatus(threshold)
+	message := fmt.Sprintf("%d/%d connections in use (%.0f%% utilization)",
+		stats.InUse, stats.MaxOpen, stats.Utilization*100)
+	if stats.MaxOpen == 0 {
+		message = fmt.Sprintf("%d connections in use (no limit)", stats.InUse)
+	}
+	return health.NewCheck(c.Name(), status, message)
+}
@@ -0,0 +1,59 @@
+package dbutil_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/julianstephens/go-utils/dbutil"
+	"github.com/julianstephens/go-utils/dbutil/dbtest"
+	"github.com/julianstephens/go-utils/health"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestNewPoolStatsSnapshot(t *testing.T) {
+	tests := []struct {
+		name  string
+		stats sql.DBStats
+		want  float64
+	}{
+		{"half used", sql.DBStats{MaxOpenConnections: 10, InUse: 5}, 0.5},
+		{"saturated", sql.DBStats{MaxOpenConnections: 4, InUse: 4}, 1},
+		{"idle", sql.DBStats{MaxOpenConnections: 4, Idle: 2}, 0},
+		{"unlimited", sql.DBStats{InUse: 50}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot := dbutil.NewPoolStatsSnapshot(tt.stats)
+			tst.AssertEqual(t, snapshot.Utilization, tt.want)
+			tst.AssertEqual(t, snapshot.InUse, tt.stats.InUse)
+			tst.AssertEqual(t, snapshot.MaxOpen, tt.stats.MaxOpenConnections)
+		})
+	}
+}
+
+func TestPoolStatsSnapshot_HealthStatus(t *testing.T) {
+	snapshot := dbutil.NewPoolStatsSnapshot(sql.DBStats{MaxOpenConnections: 10, InUse: 9})
+	tst.AssertEqual(t, snapshot.HealthStatus(0.8), health.StatusWarning)
+	tst.AssertEqual(t, snapshot.HealthStatus(0.9), health.StatusHealthy)
+}
+
+func TestPoolChecker(t *testing.T) {
+	db := dbtest.NewDB(t)
+
+	checker := dbutil.NewPoolChecker(db, 0.5)
+	tst.AssertEqual(t, checker.Name(), "database_pool")
+	tst.AssertEqual(t, checker.Check().Status, health.StatusHealthy)
+
+	// Hold the only connection so the pool is fully utilized
+	conn, err := db.Conn(context.Background())
+	tst.RequireNoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	tst.AssertEqual(t, dbutil.PoolStats(db).Utilization, 1.0)
+	tst.AssertEqual(t, checker.Check().Status, health.StatusWarning)
+
+	nilChecker := dbutil.NewPoolChecker(nil, 0)
+	tst.AssertEqual(t, nilChecker.Check().Status, health.StatusError)
+}
@@ -0,0 +1,83 @@
+package dbutil_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/go-utils/dbutil"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+// slowDriver is a database/sql driver whose queries and statements block
+// until their context is done, simulating a query that never finishes.
+type slowDriver struct{}
+
+type slowConn struct{}
+
+func (slowDriver) Open(string) (driver.Conn, error) { return slowConn{}, nil }
+
+func (slowConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (slowConn) Close() error                        { return nil }
+func (slowConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (slowConn) QueryContext(ctx context.Context, _ string, _ []driver.NamedValue) (driver.Rows, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (slowConn) ExecContext(ctx context.Context, _ string, _ []driver.NamedValue) (driver.Result, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func init() {
+	sql.Register("dbutil-slow", slowDriver{})
+}
+
+func TestQueryOptionsTimeout(t *testing.T) {
+	db, err := sql.Open("dbutil-slow", "")
+	tst.RequireNoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	opts := &dbutil.QueryOptions{Timeout: 20 * time.Millisecond}
+
+	checks := map[string]func() error{
+		"QuerySliceWithOptions": func() error {
+			var users []User
+			return dbutil.QuerySliceWithOptions(ctx, db, &users, "SELECT id, name, email FROM users", opts)
+		},
+		"QueryRowScanWithOptions": func() error {
+			var user User
+			return dbutil.QueryRowScanWithOptions(ctx, db, &user, "SELECT id, name, email FROM users", opts)
+		},
+		"QueryMapsWithOptions": func() error {
+			_, err := dbutil.QueryMapsWithOptions(ctx, db, "SELECT * FROM users", opts)
+			return err
+		},
+		"ExecWithOptions": func() error {
+			_, err := dbutil.ExecWithOptions(ctx, db, "DELETE FROM users", opts)
+			return err
+		},
+	}
+	for name, check := range checks {
+		t.Run(name, func(t *testing.T) {
+			start := time.Now()
+			err := check()
+			tst.AssertTrue(t, dbutil.IsContextError(err), "expected a context error, got "+errString(err))
+			tst.AssertErrorIs(t, err, context.DeadlineExceeded)
+			tst.AssertTrue(t, time.Since(start) < 5*time.Second, "query should stop at the timeout")
+		})
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return "nil"
+	}
+	return err.Error()
+}
@@ -0,0 +1,104 @@
+package dbutil
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// UpdateChanged updates the row in table matching whereCol = whereVal, setting
+// only the columns whose values differ between oldRow and newRow. Both must be
+// structs (or pointers to structs) of the same type; columns come from their db
+// tags. If nothing changed, no statement is executed and 0 is returned.
+// Otherwise it returns the number of rows affected as reported by the driver.
+func UpdateChanged(
+	ctx context.Context,
+	db *sql.DB,
+	table string,
+	oldRow, newRow any,
+	whereCol string,
+	whereVal any,
+) (int64, error) {
+	query, args, err := buildUpdateChanged(generatedPlaceholderStyle(), table, oldRow, newRow, whereCol, whereVal)
+	if err != nil {
+		return 0, err
+	}
+	if query == "" {
+		return 0, nil
+	}
+
+	result, err := hookedExec(ctx, db, query, args)
+	if err != nil {
+		return 0, fmt.Errorf("dbutil: update failed: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("dbutil: update rows affected failed: %w", err)
+	}
+	return affected, nil
+}
+
+// BuildUpdateChanged generates the UPDATE statement and arguments used by
+// UpdateChanged for the given dialect without executing it. It returns an
+// empty query and nil arguments if oldRow and newRow are equal.
+func BuildUpdateChanged(
+	dialect Dialect,
+	table string,
+	oldRow, newRow any,
+	whereCol string,
+	whereVal any,
+) (string, []any, error) {
+	return buildUpdateChanged(dialect.PlaceholderStyle(), table, oldRow, newRow, whereCol, whereVal)
+}
+
+// buildUpdateChanged is like BuildUpdateChanged but writes placeholders in
+// style.
+func buildUpdateChanged(
+	style PlaceholderStyle,
+	table string,
+	oldRow, newRow any,
+	whereCol string,
+	whereVal any,
+) (string, []any, error) {
+	if table == "" {
+		return "", nil, fmt.Errorf("dbutil: update table name is empty")
+	}
+	if whereCol == "" {
+		return "", nil, fmt.Errorf("dbutil: update where column is empty")
+	}
+
+	columns, oldArgs, err := structColumnValues(oldRow)
+	if err != nil {
+		return "", nil, err
+	}
+	_, newArgs, err := structColumnValues(newRow)
+	if err != nil {
+		return "", nil, err
+	}
+	if reflect.Indirect(reflect.ValueOf(oldRow)).Type() != reflect.Indirect(reflect.ValueOf(newRow)).Type() {
+		return "", nil, fmt.Errorf("dbutil: update rows must have the same type, got %T and %T", oldRow, newRow)
+	}
+
+	var (
+		sets []string
+		args []any
+	)
+	for i, col := range columns {
+		if reflect.DeepEqual(oldArgs[i], newArgs[i]) {
+			continue
+		}
+		args = append(args, newArgs[i])
+		sets = append(sets, fmt.Sprintf("%s = %s", col, style.placeholder(len(args))))
+	}
+	if len(sets) == 0 {
+		return "", nil, nil
+	}
+
+	args = append(args, whereVal)
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s = %s",
+		table, strings.Join(sets, ", "), whereCol, style.placeholder(len(args)))
+	return query, args, nil
+}
@@ -0,0 +1,77 @@
+package dbutil_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/julianstephens/go-utils/dbutil"
+	"github.com/julianstephens/go-utils/dbutil/dbtest"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestBuildUpdateChanged(t *testing.T) {
+	old := User{ID: 1, Name: "Alice", Email: "alice@example.com"}
+
+	updated := old
+	updated.Email = "alice@example.org"
+	query, args, err := dbutil.BuildUpdateChanged(dbutil.DialectPostgres, "users", old, &updated, "id", old.ID)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, query, "UPDATE users SET email = $1 WHERE id = $2")
+	tst.AssertDeepEqual(t, args, []any{"alice@example.org", int64(1)})
+
+	updated.Name = "Alice Smith"
+	query, args, err = dbutil.BuildUpdateChanged(dbutil.DialectMySQL, "users", &old, updated, "id", old.ID)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, query, "UPDATE users SET name = ?, email = ? WHERE id = ?")
+	tst.AssertDeepEqual(t, args, []any{"Alice Smith", "alice@example.org", int64(1)})
+
+	query, args, err = dbutil.BuildUpdateChanged(dbutil.DialectPostgres, "users", old, old, "id", old.ID)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, query, "")
+	tst.AssertNil(t, args)
+}
+
+func TestBuildUpdateChangedErrors(t *testing.T) {
+	user := User{ID: 1}
+
+	_, _, err := dbutil.BuildUpdateChanged(dbutil.DialectPostgres, "", user, user, "id", 1)
+	tst.AssertErrorContains(t, err, "table name is empty")
+
+	_, _, err = dbutil.BuildUpdateChanged(dbutil.DialectPostgres, "users", user, user, "", 1)
+	tst.AssertErrorContains(t, err, "where column is empty")
+
+	_, _, err = dbutil.BuildUpdateChanged(dbutil.DialectPostgres, "users", user, UserWithDefaults{ID: 1}, "id", 1)
+	tst.AssertErrorContains(t, err, "same type")
+
+	_, _, err = dbutil.BuildUpdateChanged(dbutil.DialectPostgres, "users", user, nil, "id", 1)
+	tst.AssertErrorContains(t, err, "row must be a struct")
+}
+
+func TestUpdateChangedSQLite(t *testing.T) {
+	db := dbtest.NewDB(t,
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, email TEXT)",
+		"INSERT INTO users (id, name, email) VALUES (1, 'Alice', 'alice@example.com')",
+	)
+	ctx := context.Background()
+
+	dbutil.SetDialect(dbutil.DialectSQLite)
+	t.Cleanup(func() { dbutil.SetDialect(dbutil.DialectPostgres) })
+
+	old := User{ID: 1, Name: "Alice", Email: "alice@example.com"}
+	updated := old
+	updated.Name = "Alice Smith"
+
+	affected, err := dbutil.UpdateChanged(ctx, db, "users", old, updated, "id", old.ID)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, affected, int64(1))
+
+	var got User
+	err = dbutil.QueryRowScan(ctx, db, &got, "SELECT id, name, email FROM users WHERE id = ?", 1)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, got, updated)
+
+	// Identical rows are a no-op, even for a row that does not exist
+	affected, err = dbutil.UpdateChanged(ctx, db, "missing_table", updated, updated, "id", 1)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, affected, int64(0))
+}
@@ -0,0 +1,160 @@
+package dbutil
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Upsert inserts row into table, updating updateCols when a row with the same
+// conflictCols already exists. The SQL is generated for the package dialect
+// (see SetDialect) from the struct's db tags. If updateCols is empty,
+// conflicting rows are left unchanged. It returns the number of rows affected
+// as reported by the driver.
+func Upsert(
+	ctx context.Context,
+	db *sql.DB,
+	table string,
+	row any,
+	conflictCols []string,
+	updateCols []string,
+) (int64, error) {
+	query, args, err := buildUpsert(GetDialect(), generatedPlaceholderStyle(), table, row, conflictCols, updateCols)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := hookedExec(ctx, db, query, args)
+	if err != nil {
+		return 0, fmt.Errorf("dbutil: upsert failed: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("dbutil: upsert rows affected failed: %w", err)
+	}
+	return affected, nil
+}
+
+// BuildUpsert generates an upsert statement and its arguments for the given
+// dialect without executing it. PostgreSQL and SQLite use
+// INSERT ... ON CONFLICT (...) DO UPDATE; MySQL uses
+// INSERT ... ON DUPLICATE KEY UPDATE.
+func BuildUpsert(
+	dialect Dialect,
+	table string,
+	row any,
+	conflictCols []string,
+	updateCols []string,
+) (string, []any, error) {
+	return buildUpsert(dialect, dialect.PlaceholderStyle(), table, row, conflictCols, updateCols)
+}
+
+// buildUpsert is like BuildUpsert but writes placeholders in style.
+func buildUpsert(
+	dialect Dialect,
+	style PlaceholderStyle,
+	table string,
+	row any,
+	conflictCols []string,
+	updateCols []string,
+) (string, []any, error) {
+	if table == "" {
+		return "", nil, fmt.Errorf("dbutil: upsert table name is empty")
+	}
+	if len(conflictCols) == 0 && dialect != DialectMySQL {
+		return "", nil, fmt.Errorf("dbutil: upsert requires at least one conflict column")
+	}
+
+	columns, args, err := structColumnValues(row)
+	if err != nil {
+		return "", nil, err
+	}
+
+	known := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		known[col] = true
+	}
+	for _, col := range append(append([]string{}, conflictCols...), updateCols...) {
+		if !known[col] {
+			return "", nil, fmt.Errorf("dbutil: upsert column %q not found in row", col)
+		}
+	}
+
+	placeholders := make([]string, len(columns))
+	for i := range columns {
+		placeholders[i] = style.placeholder(i + 1)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "INSERT INTO %s (%s) VALUES (%s)",
+		table, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+
+	switch dialect {
+	case DialectPostgres, DialectSQLite:
+		fmt.Fprintf(&b, " ON CONFLICT (%s)", strings.Join(conflictCols, ", "))
+		if len(updateCols) == 0 {
+			b.WriteString(" DO NOTHING")
+			break
+		}
+		sets := make([]string, len(updateCols))
+		for i, col := range updateCols {
+			sets[i] = fmt.Sprintf("%s = excluded.%s", col, col)
+		}
+		fmt.Fprintf(&b, " DO UPDATE SET %s", strings.Join(sets, ", "))
+	case DialectMySQL:
+		b.WriteString(" ON DUPLICATE KEY UPDATE ")
+		if len(updateCols) == 0 {
+			// Assigning a column to itself is the MySQL idiom for "do nothing"
+			fmt.Fprintf(&b, "%s = %s", columns[0], columns[0])
+			break
+		}
+		sets := make([]string, len(updateCols))
+		for i, col := range updateCols {
+			sets[i] = fmt.Sprintf("%s = VALUES(%s)", col, col)
+		}
+		b.WriteString(strings.Join(sets, ", "))
+	default:
+		return "", nil, fmt.Errorf("dbutil: unsupported dialect: %s", dialect)
+	}
+
+	return b.String(), args, nil
+}
+
+// structColumnValues returns the column names and field values of a struct
+// (or pointer to struct) using its db tags.
+func structColumnValues(row any) ([]string, []any, error) {
+	value := reflect.ValueOf(row)
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil, nil, fmt.Errorf("dbutil: row must be a non-nil struct or pointer to struct")
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("dbutil: row must be a struct or pointer to struct")
+	}
+
+	fields, err := getStructFields(value.Type())
+	if err != nil {
+		return nil, nil, fmt.Errorf("dbutil: failed to analyze struct: %w", err)
+	}
+
+	columns := make([]string, len(fields))
+	args := make([]any, len(fields))
+	for i, field := range fields {
+		columns[i] = field.Column
+		args[i] = value.Field(field.Index).Interface()
+		if field.JSON {
+			data, err := json.Marshal(args[i])
+			if err != nil {
+				return nil, nil, fmt.Errorf("dbutil: failed to marshal JSON column %s: %w", field.Column, err)
+			}
+			args[i] = string(data)
+		}
+	}
+	return columns, args, nil
+}
@@ -0,0 +1,110 @@
+package dbutil_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/julianstephens/go-utils/dbutil"
+	"github.com/julianstephens/go-utils/dbutil/dbtest"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestBuildUpsert(t *testing.T) {
+	user := User{ID: 1, Name: "Alice", Email: "alice@example.com"}
+
+	tests := []struct {
+		name    string
+		dialect dbutil.Dialect
+		update  []string
+		want    string
+	}{
+		{
+			name:    "postgres",
+			dialect: dbutil.DialectPostgres,
+			update:  []string{"name", "email"},
+			want: "INSERT INTO users (id, name, email) VALUES ($1, $2, $3) " +
+				"ON CONFLICT (id) DO UPDATE SET name = excluded.name, email = excluded.email",
+		},
+		{
+			name:    "sqlite",
+			dialect: dbutil.DialectSQLite,
+			update:  []string{"name"},
+			want:    "INSERT INTO users (id, name, email) VALUES (?, ?, ?) ON CONFLICT (id) DO UPDATE SET name = excluded.name",
+		},
+		{
+			name:    "mysql",
+			dialect: dbutil.DialectMySQL,
+			update:  []string{"name", "email"},
+			want: "INSERT INTO users (id, name, email) VALUES (?, ?, ?) " +
+				"ON DUPLICATE KEY UPDATE name = VALUES(name), email = VALUES(email)",
+		},
+		{
+			name:    "postgres do nothing",
+			dialect: dbutil.DialectPostgres,
+			want:    "INSERT INTO users (id, name, email) VALUES ($1, $2, $3) ON CONFLICT (id) DO NOTHING",
+		},
+		{
+			name:    "mysql do nothing",
+			dialect: dbutil.DialectMySQL,
+			want:    "INSERT INTO users (id, name, email) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE id = id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := dbutil.BuildUpsert(tt.dialect, "users", &user, []string{"id"}, tt.update)
+			tst.RequireNoError(t, err)
+			tst.AssertEqual(t, query, tt.want)
+			tst.AssertDeepEqual(t, args, []any{int64(1), "Alice", "alice@example.com"})
+		})
+	}
+}
+
+func TestBuildUpsertErrors(t *testing.T) {
+	user := User{ID: 1}
+
+	_, _, err := dbutil.BuildUpsert(dbutil.DialectPostgres, "", user, []string{"id"}, nil)
+	tst.AssertErrorContains(t, err, "table name is empty")
+
+	_, _, err = dbutil.BuildUpsert(dbutil.DialectPostgres, "users", user, nil, nil)
+	tst.AssertErrorContains(t, err, "at least one conflict column")
+
+	_, _, err = dbutil.BuildUpsert(dbutil.DialectPostgres, "users", user, []string{"id"}, []string{"missing"})
+	tst.AssertErrorContains(t, err, `column "missing" not found`)
+
+	_, _, err = dbutil.BuildUpsert(dbutil.DialectPostgres, "users", "not a struct", []string{"id"}, nil)
+	tst.AssertErrorContains(t, err, "row must be a struct")
+}
+
+func TestUpsertSQLite(t *testing.T) {
+	db := dbtest.NewDB(t, "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, email TEXT)")
+	ctx := context.Background()
+
+	dbutil.SetDialect(dbutil.DialectSQLite)
+	t.Cleanup(func() { dbutil.SetDialect(dbutil.DialectPostgres) })
+
+	affected, err := dbutil.Upsert(ctx, db, "users", User{ID: 1, Name: "Alice", Email: "alice@example.com"},
+		[]string{"id"}, []string{"name", "email"})
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, affected, int64(1))
+
+	_, err = dbutil.Upsert(ctx, db, "users", User{ID: 1, Name: "Alice Smith", Email: "alice@example.org"},
+		[]string{"id"}, []string{"name"})
+	tst.RequireNoError(t, err)
+
+	var got User
+	err = dbutil.QueryRowScan(ctx, db, &got, "SELECT id, name, email FROM users WHERE id = ?", 1)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, got.Name, "Alice Smith")
+	tst.AssertEqual(t, got.Email, "alice@example.com") // not in updateCols
+
+	count, err := dbutil.Count(ctx, db, "SELECT COUNT(*) FROM users")
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, count, int64(1))
+}
+
+func TestDialect(t *testing.T) {
+	tst.AssertEqual(t, dbutil.GetDialect(), dbutil.DialectPostgres)
+	tst.AssertEqual(t, dbutil.DialectMySQL.String(), "mysql")
+	tst.AssertEqual(t, dbutil.Dialect(99).String(), "unknown")
+}
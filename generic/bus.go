@@ -0,0 +1,184 @@
+package generic
+
+import "sync"
+
+// BusPolicy controls what Publish does when a subscriber's buffer is full.
+type BusPolicy int
+
+const (
+	// BusDrop drops the event for subscribers whose buffer is full.
+	// Publish never blocks under this policy.
+	BusDrop BusPolicy = iota
+	// BusBlock waits until every subscriber has room for the event.
+	// Subscribers must keep draining their channel until unsubscribed;
+	// unsubscribing releases a Publish blocked on that subscriber.
+	BusBlock
+)
+
+// DefaultBusBuffer is the per-subscriber channel buffer used by NewBus.
+const DefaultBusBuffer = 16
+
+// BusConfig configures a Bus.
+type BusConfig struct {
+	// Buffer is the channel buffer size of each subscriber
+	Buffer int
+	// Policy decides how slow subscribers are handled
+	Policy BusPolicy
+}
+
+// DefaultBusConfig returns a BusConfig with DefaultBusBuffer and the BusDrop policy.
+func DefaultBusConfig() BusConfig {
+	return BusConfig{
+		Buffer: DefaultBusBuffer,
+		Policy: BusDrop,
+	}
+}
+
+// Bus is a typed in-process publish/subscribe event bus. Every event
+// passed to Publish is fanned out to all current subscribers.
+type Bus[T any] struct {
+	mu     sync.RWMutex
+	config BusConfig
+	subs   map[*subscriber[T]]struct{}
+	closed bool
+}
+
+type subscriber[T any] struct {
+	ch   chan T
+	done chan struct{}
+	// mu is held for reading by senders and for writing while closing ch,
+	// so a channel is never closed during a send.
+	mu     sync.RWMutex
+	closed bool
+	once   sync.Once
+}
+
+// send delivers event to the subscriber, waiting for buffer space if block
+// is set. A blocked send is abandoned once the subscriber is closed.
+func (s *subscriber[T]) send(event T, block bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return
+	}
+	if block {
+		select {
+		case s.ch <- event:
+		case <-s.done:
+		}
+		return
+	}
+	select {
+	case s.ch <- event:
+	default:
+	}
+}
+
+// close wakes any blocked senders, waits for them to return, and closes ch.
+// It is safe to call more than once.
+func (s *subscriber[T]) close() {
+	s.once.Do(func() {
+		close(s.done)
+		s.mu.Lock()
+		s.closed = true
+		close(s.ch)
+		s.mu.Unlock()
+	})
+}
+
+// NewBus creates a Bus using DefaultBusConfig.
+func NewBus[T any]() *Bus[T] {
+	return NewBusWithConfig[T](DefaultBusConfig())
+}
+
+// NewBusWithConfig creates a Bus with the given configuration.
+// A negative buffer size is treated as zero (unbuffered).
+func NewBusWithConfig[T any](config BusConfig) *Bus[T] {
+	if config.Buffer < 0 {
+		config.Buffer = 0
+	}
+	return &Bus[T]{
+		config: config,
+		subs:   make(map[*subscriber[T]]struct{}),
+	}
+}
+
+// Subscribe registers a new subscriber and returns its receive channel together
+// with an unsubscribe function. Calling unsubscribe removes the subscriber and
+// closes the channel; it is safe to call more than once. Subscribing to a closed
+// bus returns an already closed channel.
+func (b *Bus[T]) Subscribe() (<-chan T, func()) {
+	sub := &subscriber[T]{ch: make(chan T, b.config.Buffer), done: make(chan struct{})}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		close(sub.ch)
+		return sub.ch, func() {}
+	}
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		_, ok := b.subs[sub]
+		delete(b.subs, sub)
+		b.mu.Unlock()
+		if ok {
+			sub.close()
+		}
+	}
+	return sub.ch, unsubscribe
+}
+
+// Publish delivers event to all current subscribers. Under BusDrop, subscribers
+// whose buffer is full miss the event; under BusBlock, Publish waits for them,
+// giving up on any subscriber that unsubscribes or is closed meanwhile.
+// Publishing to a closed bus is a no-op.
+func (b *Bus[T]) Publish(event T) {
+	// Send outside the bus lock so a blocked send cannot stall Subscribe,
+	// unsubscribe, Len, or Close.
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return
+	}
+	subs := make([]*subscriber[T], 0, len(b.subs))
+	for sub := range b.subs {
+		subs = append(subs, sub)
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range subs {
+		sub.send(event, b.config.Policy == BusBlock)
+	}
+}
+
+// Len returns the number of active subscribers.
+func (b *Bus[T]) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs)
+}
+
+// Close unsubscribes all subscribers, closing their channels. Subsequent
+// Publish calls are ignored.
+func (b *Bus[T]) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	subs := make([]*subscriber[T], 0, len(b.subs))
+	for sub := range b.subs {
+		subs = append(subs, sub)
+		delete(b.subs, sub)
+	}
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.close()
+	}
+}
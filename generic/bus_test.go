@@ -0,0 +1,177 @@
+package generic_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/go-utils/generic"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func receive[T any](t *testing.T, ch <-chan T) (T, bool) {
+	t.Helper()
+	select {
+	case v, ok := <-ch:
+		return v, ok
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return generic.Zero[T](), false
+	}
+}
+
+func TestBus_FanOut(t *testing.T) {
+	bus := generic.NewBus[string]()
+
+	ch1, unsub1 := bus.Subscribe()
+	defer unsub1()
+	ch2, unsub2 := bus.Subscribe()
+	defer unsub2()
+
+	bus.Publish("hello")
+
+	v1, ok := receive(t, ch1)
+	tst.AssertTrue(t, ok, "first subscriber channel should be open")
+	tst.AssertEqual(t, v1, "hello")
+
+	v2, ok := receive(t, ch2)
+	tst.AssertTrue(t, ok, "second subscriber channel should be open")
+	tst.AssertEqual(t, v2, "hello")
+}
+
+func TestBus_Unsubscribe(t *testing.T) {
+	bus := generic.NewBus[int]()
+
+	ch1, unsub1 := bus.Subscribe()
+	ch2, unsub2 := bus.Subscribe()
+	defer unsub2()
+
+	unsub1()
+	unsub1() // safe to call twice
+	tst.AssertEqual(t, bus.Len(), 1)
+
+	bus.Publish(42)
+
+	_, ok := receive(t, ch1)
+	tst.AssertFalse(t, ok, "unsubscribed channel should be closed")
+
+	v, ok := receive(t, ch2)
+	tst.AssertTrue(t, ok, "remaining subscriber should receive")
+	tst.AssertEqual(t, v, 42)
+}
+
+func TestBus_DropPolicy(t *testing.T) {
+	bus := generic.NewBusWithConfig[int](generic.BusConfig{Buffer: 1, Policy: generic.BusDrop})
+	ch, unsub := bus.Subscribe()
+	defer unsub()
+
+	// Second publish must not block on the full buffer
+	bus.Publish(1)
+	bus.Publish(2)
+
+	v, _ := receive(t, ch)
+	tst.AssertEqual(t, v, 1)
+	select {
+	case v := <-ch:
+		t.Fatalf("expected dropped event, got %d", v)
+	default:
+	}
+}
+
+func TestBus_BlockPolicy(t *testing.T) {
+	bus := generic.NewBusWithConfig[int](generic.BusConfig{Buffer: 0, Policy: generic.BusBlock})
+	ch, unsub := bus.Subscribe()
+	defer unsub()
+
+	done := make(chan struct{})
+	go func() {
+		bus.Publish(1)
+		bus.Publish(2)
+		close(done)
+	}()
+
+	v1, _ := receive(t, ch)
+	v2, _ := receive(t, ch)
+	tst.AssertDeepEqual(t, []int{v1, v2}, []int{1, 2})
+	<-done
+}
+
+func TestBus_BlockPolicyUnsubscribeWhileBlocked(t *testing.T) {
+	bus := generic.NewBusWithConfig[int](generic.BusConfig{Buffer: 0, Policy: generic.BusBlock})
+	_, unsub := bus.Subscribe() // never read
+	other, unsubOther := bus.Subscribe()
+	defer unsubOther()
+
+	published := make(chan struct{})
+	go func() {
+		bus.Publish(1)
+		close(published)
+	}()
+
+	// Let Publish block on the subscriber that never reads
+	time.Sleep(20 * time.Millisecond)
+
+	unsubscribed := make(chan struct{})
+	go func() {
+		unsub()
+		close(unsubscribed)
+	}()
+	select {
+	case <-unsubscribed:
+	case <-time.After(time.Second):
+		t.Fatal("unsubscribe deadlocked behind a blocked Publish")
+	}
+	tst.AssertEqual(t, bus.Len(), 1)
+
+	// Publish moves on to the remaining subscriber
+	v, _ := receive(t, other)
+	tst.AssertEqual(t, v, 1)
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("Publish did not return after the blocked subscriber left")
+	}
+}
+
+func TestBus_BlockPolicyCloseWhileBlocked(t *testing.T) {
+	bus := generic.NewBusWithConfig[int](generic.BusConfig{Buffer: 0, Policy: generic.BusBlock})
+	bus.Subscribe() // never read
+
+	published := make(chan struct{})
+	go func() {
+		bus.Publish(1)
+		close(published)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		bus.Close()
+		close(closed)
+	}()
+
+	for name, ch := range map[string]chan struct{}{"close": closed, "publish": published} {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatalf("%s deadlocked behind a blocked Publish", name)
+		}
+	}
+}
+
+func TestBus_Close(t *testing.T) {
+	bus := generic.NewBus[int]()
+	ch, unsub := bus.Subscribe()
+
+	bus.Close()
+	unsub() // no-op after close
+
+	_, ok := receive(t, ch)
+	tst.AssertFalse(t, ok, "channel should be closed after Close")
+	tst.AssertEqual(t, bus.Len(), 0)
+
+	bus.Publish(1) // ignored
+
+	late, _ := bus.Subscribe()
+	_, ok = receive(t, late)
+	tst.AssertFalse(t, ok, "subscribing to a closed bus returns a closed channel")
+}
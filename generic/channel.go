@@ -0,0 +1,82 @@
+package generic
+
+import (
+	"context"
+	"time"
+)
+
+// BatchChannel groups the values received from in into batches of up to size
+// items, e.g. to turn a stream of records into bulk inserts. A batch is sent
+// as soon as it is full, or when maxWait has passed since its first item
+// arrived, whichever comes first; a maxWait of zero or less disables the
+// time-based flush. When in is closed, any partial batch is sent and the
+// returned channel is closed. If ctx is done, the pending batch is dropped and
+// the returned channel is closed. As with Chunk, a size less than 1 yields no
+// batches: the returned channel is already closed and in is not read.
+func BatchChannel[T any](ctx context.Context, in <-chan T, size int, maxWait time.Duration) <-chan []T {
+	out := make(chan []T)
+	if size < 1 {
+		close(out)
+		return out
+	}
+
+	go func() {
+		defer close(out)
+
+		var (
+			batch   []T
+			timer   *time.Timer
+			timeout <-chan time.Time
+		)
+		stopTimer := func() {
+			if timer != nil {
+				timer.Stop()
+				timer, timeout = nil, nil
+			}
+		}
+		defer stopTimer()
+
+		flush := func() bool {
+			stopTimer()
+			if len(batch) == 0 {
+				return true
+			}
+			select {
+			case out <- batch:
+				batch = nil
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+				if batch == nil {
+					batch = make([]T, 0, size)
+					if maxWait > 0 {
+						timer = time.NewTimer(maxWait)
+						timeout = timer.C
+					}
+				}
+				batch = append(batch, v)
+				if len(batch) == size && !flush() {
+					return
+				}
+			case <-timeout:
+				timer, timeout = nil, nil
+				if !flush() {
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
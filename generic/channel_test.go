@@ -0,0 +1,91 @@
+package generic_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/go-utils/generic"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+// receiveBatch waits up to timeout for the next batch.
+func receiveBatch(t *testing.T, ch <-chan []int, timeout time.Duration) ([]int, bool) {
+	t.Helper()
+	select {
+	case batch, ok := <-ch:
+		return batch, ok
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for batch")
+		return nil, false
+	}
+}
+
+func TestBatchChannel_FullBatches(t *testing.T) {
+	in := make(chan int)
+	out := generic.BatchChannel(context.Background(), in, 3, time.Hour)
+
+	go func() {
+		for i := 1; i <= 6; i++ {
+			in <- i
+		}
+	}()
+
+	// Full batches are emitted without waiting for maxWait
+	batch, _ := receiveBatch(t, out, time.Second)
+	tst.AssertDeepEqual(t, batch, []int{1, 2, 3})
+	batch, _ = receiveBatch(t, out, time.Second)
+	tst.AssertDeepEqual(t, batch, []int{4, 5, 6})
+	close(in)
+
+	_, ok := receiveBatch(t, out, time.Second)
+	tst.AssertFalse(t, ok, "output should close when input closes")
+}
+
+func TestBatchChannel_FlushAfterMaxWait(t *testing.T) {
+	in := make(chan int)
+	out := generic.BatchChannel(context.Background(), in, 10, 20*time.Millisecond)
+	defer close(in)
+
+	start := time.Now()
+	in <- 1
+	in <- 2
+
+	batch, _ := receiveBatch(t, out, time.Second)
+	tst.AssertDeepEqual(t, batch, []int{1, 2})
+	tst.AssertTrue(t, time.Since(start) >= 20*time.Millisecond, "partial batch should wait for maxWait")
+
+	// The timer restarts with the next batch
+	in <- 3
+	batch, _ = receiveBatch(t, out, time.Second)
+	tst.AssertDeepEqual(t, batch, []int{3})
+}
+
+func TestBatchChannel_FlushOnClose(t *testing.T) {
+	in := make(chan int, 2)
+	in <- 1
+	in <- 2
+	close(in)
+
+	out := generic.BatchChannel(context.Background(), in, 5, 0)
+	batch, _ := receiveBatch(t, out, time.Second)
+	tst.AssertDeepEqual(t, batch, []int{1, 2})
+	_, ok := receiveBatch(t, out, time.Second)
+	tst.AssertFalse(t, ok, "output should close after the final batch")
+}
+
+func TestBatchChannel_ContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	out := generic.BatchChannel(ctx, in, 5, time.Hour)
+
+	in <- 1
+	cancel()
+
+	_, ok := receiveBatch(t, out, time.Second)
+	tst.AssertFalse(t, ok, "output should close when ctx is done")
+
+	// A non-positive size yields no batches, like Chunk
+	_, ok = receiveBatch(t, generic.BatchChannel(context.Background(), in, 0, 0), time.Second)
+	tst.AssertFalse(t, ok, "output should be closed for a zero size")
+}
@@ -0,0 +1,122 @@
+package generic
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DeepCopy returns a copy of v that shares no mutable state with it: pointers,
+// slices, maps, arrays, interfaces, and exported struct fields are copied
+// recursively, and pointer cycles are preserved rather than followed forever.
+// Unexported struct fields are copied shallowly, since reflection cannot set
+// them, so types like time.Time copy as values. Non-nil channels and functions
+// cannot be copied and cause an error.
+func DeepCopy[T any](v T) (T, error) {
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+	c := deepCopier{seen: make(map[deepCopyVisit]reflect.Value)}
+	if err := c.copy(dst, src); err != nil {
+		var zero T
+		return zero, err
+	}
+	return dst.Interface().(T), nil
+}
+
+// deepCopyVisit identifies a pointer already copied, so shared and cyclic
+// pointers map to a single copy.
+type deepCopyVisit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type deepCopier struct {
+	seen map[deepCopyVisit]reflect.Value
+}
+
+// copy deep-copies src into dst, which must be settable and of the same type.
+func (c *deepCopier) copy(dst, src reflect.Value) error {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return nil
+		}
+		visit := deepCopyVisit{ptr: src.Pointer(), typ: src.Type()}
+		if copied, ok := c.seen[visit]; ok {
+			dst.Set(copied)
+			return nil
+		}
+		copied := reflect.New(src.Type().Elem())
+		c.seen[visit] = copied
+		if err := c.copy(copied.Elem(), src.Elem()); err != nil {
+			return err
+		}
+		dst.Set(copied)
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return nil
+		}
+		inner := src.Elem()
+		copied := reflect.New(inner.Type()).Elem()
+		if err := c.copy(copied, inner); err != nil {
+			return err
+		}
+		dst.Set(copied)
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return nil
+		}
+		copied := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			if err := c.copy(copied.Index(i), src.Index(i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(copied)
+
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			if err := c.copy(dst.Index(i), src.Index(i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if src.IsNil() {
+			return nil
+		}
+		copied := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			value := reflect.New(src.Type().Elem()).Elem()
+			if err := c.copy(value, iter.Value()); err != nil {
+				return err
+			}
+			copied.SetMapIndex(iter.Key(), value)
+		}
+		dst.Set(copied)
+
+	case reflect.Struct:
+		// Copy everything, including unexported fields, then replace the
+		// exported fields with deep copies
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if !src.Type().Field(i).IsExported() {
+				continue
+			}
+			if err := c.copy(dst.Field(i), src.Field(i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		if !src.IsNil() {
+			return fmt.Errorf("cannot deep copy value of type %s", src.Type())
+		}
+
+	default:
+		dst.Set(src)
+	}
+	return nil
+}
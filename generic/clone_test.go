@@ -0,0 +1,99 @@
+package generic_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/go-utils/generic"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestCloneSlice(t *testing.T) {
+	original := []int{1, 2, 3}
+	clone := generic.CloneSlice(original)
+	tst.AssertDeepEqual(t, clone, original)
+
+	clone[0] = 100
+	_ = append(clone, 4)
+	tst.AssertDeepEqual(t, original, []int{1, 2, 3})
+
+	tst.AssertNil(t, generic.CloneSlice[int](nil), "CloneSlice with nil slice should return nil")
+}
+
+func TestCloneMap(t *testing.T) {
+	original := map[string]int{"a": 1, "b": 2}
+	clone := generic.CloneMap(original)
+	tst.AssertDeepEqual(t, clone, original)
+
+	clone["a"] = 100
+	clone["c"] = 3
+	tst.AssertDeepEqual(t, original, map[string]int{"a": 1, "b": 2})
+
+	tst.AssertNil(t, generic.CloneMap[string, int](nil), "CloneMap with nil map should return nil")
+}
+
+func TestDeepCopy_NestedMaps(t *testing.T) {
+	original := map[string]any{
+		"name": "service",
+		"tags": []any{"a", "b"},
+		"limits": map[string]any{
+			"cpu":    2,
+			"memory": map[string]int{"request": 256, "limit": 512},
+		},
+	}
+
+	clone, err := generic.DeepCopy(original)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, clone, original)
+
+	clone["name"] = "changed"
+	clone["tags"].([]any)[0] = "z"
+	clone["limits"].(map[string]any)["cpu"] = 8
+	clone["limits"].(map[string]any)["memory"].(map[string]int)["limit"] = 4096
+
+	tst.AssertEqual(t, original["name"].(string), "service")
+	tst.AssertEqual(t, original["tags"].([]any)[0].(string), "a")
+	tst.AssertEqual(t, original["limits"].(map[string]any)["cpu"].(int), 2)
+	tst.AssertEqual(t, original["limits"].(map[string]any)["memory"].(map[string]int)["limit"], 512)
+}
+
+type cloneNode struct {
+	Name     string
+	Children []*cloneNode
+	Parent   *cloneNode
+	Labels   map[string][]string
+	Created  time.Time
+	counter  int
+}
+
+func TestDeepCopy_Structs(t *testing.T) {
+	root := &cloneNode{Name: "root", Labels: map[string][]string{"env": {"prod"}}, Created: time.Unix(1700000000, 0), counter: 7}
+	child := &cloneNode{Name: "child", Parent: root}
+	root.Children = []*cloneNode{child}
+
+	clone, err := generic.DeepCopy(root)
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, clone != root, "DeepCopy should allocate a new root")
+	tst.AssertTrue(t, clone.Children[0] != child, "DeepCopy should allocate new children")
+	tst.AssertTrue(t, clone.Children[0].Parent == clone, "cycles should point into the copy")
+	tst.AssertTrue(t, clone.Created.Equal(root.Created), "time.Time should be copied by value")
+	tst.AssertEqual(t, clone.Name, "root")
+	tst.AssertEqual(t, clone.counter, 7)
+
+	clone.Children[0].Name = "changed"
+	clone.Labels["env"][0] = "dev"
+	tst.AssertEqual(t, child.Name, "child")
+	tst.AssertEqual(t, root.Labels["env"][0], "prod")
+}
+
+func TestDeepCopy_Uncopyable(t *testing.T) {
+	_, err := generic.DeepCopy(map[string]any{"ch": make(chan int)})
+	tst.AssertNotNil(t, err, "DeepCopy should reject channels")
+
+	_, err = generic.DeepCopy(struct{ Fn func() }{Fn: func() {}})
+	tst.AssertNotNil(t, err, "DeepCopy should reject functions")
+
+	// Nil channels and functions have nothing to share
+	_, err = generic.DeepCopy(struct{ Fn func() }{})
+	tst.RequireNoError(t, err)
+}
@@ -76,3 +76,19 @@ func ForEach[T any](slice []T, f func(T)) {
 		f(v)
 	}
 }
+
+// Pipe threads value through fns in order, passing each result to the next
+// function, and returns the final result. With no functions it returns value.
+func Pipe[T any](value T, fns ...func(T) T) T {
+	for _, fn := range fns {
+		value = fn(value)
+	}
+	return value
+}
+
+// Tap calls fn with value for its side effects, such as logging, and returns
+// value unchanged. It can be used as a step in a Pipe via a closure.
+func Tap[T any](value T, fn func(T)) T {
+	fn(value)
+	return value
+}
@@ -154,3 +154,28 @@ func TestForEach(t *testing.T) {
 	expected := []string{"HELLO", "WORLD"}
 	tst.AssertDeepEqual(t, result, expected)
 }
+
+func TestPipe(t *testing.T) {
+	result := generic.Pipe("  Hello World  ", strings.TrimSpace, strings.ToLower)
+	tst.AssertEqual(t, result, "hello world")
+
+	// Transforms run in order
+	n := generic.Pipe(3, func(x int) int { return x + 1 }, func(x int) int { return x * 10 })
+	tst.AssertEqual(t, n, 40)
+
+	tst.AssertEqual(t, generic.Pipe("unchanged"), "unchanged")
+}
+
+func TestTap(t *testing.T) {
+	var logged []string
+	logStep := func(s string) string {
+		return generic.Tap(s, func(v string) { logged = append(logged, v) })
+	}
+
+	result := generic.Pipe("  Go  ", strings.TrimSpace, logStep, strings.ToUpper)
+	tst.AssertEqual(t, result, "GO")
+	tst.AssertDeepEqual(t, logged, []string{"Go"})
+
+	input := []int{1, 2, 3}
+	tst.AssertDeepEqual(t, generic.Tap(input, func([]int) {}), input)
+}
@@ -31,6 +31,30 @@ func Default[T any](val T, defaultVal T) T {
 	return val
 }
 
+// Coalesce returns the first of values that is not the zero value for its type,
+// or the zero value if all of them are (or none are given). It is the variadic
+// form of Default, useful for fallback chains such as flag, env, then file.
+func Coalesce[T comparable](values ...T) T {
+	var zero T
+	for _, v := range values {
+		if v != zero {
+			return v
+		}
+	}
+	return zero
+}
+
+// FirstNonNil returns the first non-nil pointer in ptrs, or nil if all of them
+// are nil. Unlike Coalesce, a pointer to a zero value counts as set.
+func FirstNonNil[T any](ptrs ...*T) *T {
+	for _, p := range ptrs {
+		if p != nil {
+			return p
+		}
+	}
+	return nil
+}
+
 // Ptr returns a pointer to the given value.
 // This is useful for creating pointers to literal values or variables.
 func Ptr[T any](v T) *T {
@@ -77,6 +77,26 @@ func TestDefault(t *testing.T) {
 	tst.AssertDeepEqual(t, sliceResult, expected)
 }
 
+func TestCoalesce(t *testing.T) {
+	tst.AssertEqual(t, generic.Coalesce("", "", "from-env", "from-file"), "from-env")
+	tst.AssertEqual(t, generic.Coalesce("flag", "from-env"), "flag")
+	tst.AssertEqual(t, generic.Coalesce("", ""), "")
+	tst.AssertEqual(t, generic.Coalesce[string](), "")
+	tst.AssertEqual(t, generic.Coalesce(0, 0, 8080), 8080)
+}
+
+func TestFirstNonNil(t *testing.T) {
+	zero, one := 0, 1
+	result := generic.FirstNonNil(nil, &zero, &one)
+	tst.AssertTrue(t, result == &zero, "FirstNonNil should return the first non-nil pointer, even to a zero value")
+
+	result = generic.FirstNonNil[int](nil, nil, &one)
+	tst.AssertTrue(t, result == &one, "FirstNonNil should skip nil pointers")
+
+	tst.AssertNil(t, generic.FirstNonNil[int](nil, nil), "FirstNonNil should return nil when all are nil")
+	tst.AssertNil(t, generic.FirstNonNil[int](), "FirstNonNil should return nil with no arguments")
+}
+
 func TestPtr(t *testing.T) {
 	// Test with integer
 	value := 42
@@ -0,0 +1,69 @@
+package generic
+
+import "container/heap"
+
+// Heap is a priority queue ordered by a less function: Pop and Peek return the
+// element for which less reports true against every other element, so
+// less = func(a, b int) bool { return a < b } yields a min-heap. It is not safe
+// for concurrent use.
+type Heap[T any] struct {
+	h heapSlice[T]
+}
+
+// NewHeap creates an empty Heap ordered by less.
+func NewHeap[T any](less func(a, b T) bool) *Heap[T] {
+	return &Heap[T]{h: heapSlice[T]{less: less}}
+}
+
+// Push adds v to the heap in O(log n).
+func (h *Heap[T]) Push(v T) {
+	heap.Push(&h.h, v)
+}
+
+// Pop removes and returns the highest-priority element in O(log n). It returns
+// false if the heap is empty.
+func (h *Heap[T]) Pop() (T, bool) {
+	if len(h.h.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return heap.Pop(&h.h).(T), true
+}
+
+// Peek returns the highest-priority element without removing it. It returns
+// false if the heap is empty.
+func (h *Heap[T]) Peek() (T, bool) {
+	if len(h.h.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.h.items[0], true
+}
+
+// Len returns the number of elements in the heap.
+func (h *Heap[T]) Len() int {
+	return len(h.h.items)
+}
+
+// heapSlice adapts a slice and comparator to heap.Interface.
+type heapSlice[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func (s heapSlice[T]) Len() int           { return len(s.items) }
+func (s heapSlice[T]) Less(i, j int) bool { return s.less(s.items[i], s.items[j]) }
+func (s heapSlice[T]) Swap(i, j int)      { s.items[i], s.items[j] = s.items[j], s.items[i] }
+
+func (s *heapSlice[T]) Push(x any) {
+	s.items = append(s.items, x.(T))
+}
+
+func (s *heapSlice[T]) Pop() any {
+	n := len(s.items) - 1
+	v := s.items[n]
+	var zero T
+	s.items[n] = zero // release the reference for GC
+	s.items = s.items[:n]
+	return v
+}
@@ -0,0 +1,58 @@
+package generic_test
+
+import (
+	"testing"
+
+	"github.com/julianstephens/go-utils/generic"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestHeap_MinOrder(t *testing.T) {
+	h := generic.NewHeap(func(a, b int) bool { return a < b })
+	for _, v := range []int{5, 1, 8, 3, 9, 2, 7} {
+		h.Push(v)
+	}
+	tst.AssertEqual(t, h.Len(), 7)
+
+	top, ok := h.Peek()
+	tst.AssertTrue(t, ok, "Peek on a non-empty heap should succeed")
+	tst.AssertEqual(t, top, 1)
+	tst.AssertEqual(t, h.Len(), 7)
+
+	var got []int
+	for h.Len() > 0 {
+		v, _ := h.Pop()
+		got = append(got, v)
+	}
+	tst.AssertDeepEqual(t, got, []int{1, 2, 3, 5, 7, 8, 9})
+
+	_, ok = h.Pop()
+	tst.AssertFalse(t, ok, "Pop on an empty heap should fail")
+	_, ok = h.Peek()
+	tst.AssertFalse(t, ok, "Peek on an empty heap should fail")
+}
+
+func TestHeap_CustomComparator(t *testing.T) {
+	type task struct {
+		name     string
+		priority int
+	}
+	// Highest priority first; ties broken by name
+	h := generic.NewHeap(func(a, b task) bool {
+		if a.priority != b.priority {
+			return a.priority > b.priority
+		}
+		return a.name < b.name
+	})
+	h.Push(task{"backup", 1})
+	h.Push(task{"deploy", 5})
+	h.Push(task{"alert", 5})
+	h.Push(task{"report", 3})
+
+	var order []string
+	for h.Len() > 0 {
+		v, _ := h.Pop()
+		order = append(order, v.name)
+	}
+	tst.AssertDeepEqual(t, order, []string{"alert", "deploy", "report", "backup"})
+}
@@ -127,3 +127,10 @@ func CopyMap[K comparable, V any](m map[K]V) map[K]V {
 	maps.Copy(result, m)
 	return result
 }
+
+// CloneMap creates a shallow copy of the map. It is equivalent to CopyMap and
+// pairs with CloneSlice; use DeepCopy when values contain maps, slices, or
+// pointers that must not be shared.
+func CloneMap[K comparable, V any](m map[K]V) map[K]V {
+	return CopyMap(m)
+}
@@ -0,0 +1,73 @@
+package generic_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/julianstephens/go-utils/generic"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestOption(t *testing.T) {
+	some := generic.Some(42)
+	v, ok := some.Get()
+	tst.AssertTrue(t, ok, "Some should hold a value")
+	tst.AssertEqual(t, v, 42)
+	tst.AssertTrue(t, some.IsSome(), "Some.IsSome should be true")
+	tst.AssertEqual(t, some.OrElse(7), 42)
+
+	none := generic.None[int]()
+	v, ok = none.Get()
+	tst.AssertFalse(t, ok, "None should not hold a value")
+	tst.AssertEqual(t, v, 0)
+	tst.AssertTrue(t, none.IsNone(), "None.IsNone should be true")
+	tst.AssertEqual(t, none.OrElse(7), 7)
+
+	var zero generic.Option[string]
+	tst.AssertTrue(t, zero.IsNone(), "zero Option should be None")
+
+	tst.AssertTrue(t, generic.OptionFromPtr[int](nil).IsNone(), "nil pointer should be None")
+	tst.AssertEqual(t, generic.OptionFromPtr(generic.Ptr(3)).OrElse(0), 3)
+}
+
+func TestMapOption(t *testing.T) {
+	double := func(n int) int { return n * 2 }
+	tst.AssertEqual(t, generic.MapOption(generic.Some(4), double).OrElse(0), 8)
+	tst.AssertTrue(t, generic.MapOption(generic.None[int](), double).IsNone(), "Map over None should be None")
+
+	// Options compose with the slice helpers
+	opts := []generic.Option[int]{generic.Some(1), generic.None[int](), generic.Some(3)}
+	present := generic.Filter(opts, generic.Option[int].IsSome)
+	values := generic.Map(present, func(o generic.Option[int]) int { return o.OrElse(0) })
+	tst.AssertDeepEqual(t, values, []int{1, 3})
+}
+
+func TestResult(t *testing.T) {
+	ok := generic.Ok("value")
+	tst.AssertTrue(t, ok.IsOk(), "Ok.IsOk should be true")
+	v, err := ok.Unwrap()
+	tst.AssertNoError(t, err)
+	tst.AssertEqual(t, v, "value")
+	tst.AssertEqual(t, ok.UnwrapOr("fallback"), "value")
+
+	boom := errors.New("boom")
+	failed := generic.Err[string](boom)
+	tst.AssertFalse(t, failed.IsOk(), "Err.IsOk should be false")
+	v, err = failed.Unwrap()
+	tst.AssertErrorIs(t, err, boom)
+	tst.AssertEqual(t, v, "")
+	tst.AssertEqual(t, failed.UnwrapOr("fallback"), "fallback")
+	tst.AssertErrorIs(t, failed.Err(), boom)
+}
+
+func TestResultOfAndMapResult(t *testing.T) {
+	parsed := generic.ResultOf(strconv.Atoi("12"))
+	tst.AssertEqual(t, generic.MapResult(parsed, strconv.Itoa).UnwrapOr(""), "12")
+
+	invalid := generic.ResultOf(strconv.Atoi("x"))
+	tst.AssertFalse(t, invalid.IsOk(), "invalid input should fail")
+	mapped := generic.MapResult(invalid, strconv.Itoa)
+	tst.AssertNotNil(t, mapped.Err())
+	tst.AssertEqual(t, mapped.UnwrapOr("n/a"), "n/a")
+}
@@ -0,0 +1,117 @@
+package generic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// OrderedMap is a map that remembers the order in which keys were first
+// inserted. Updating an existing key keeps its position; deleting and
+// re-inserting a key moves it to the end. It is not safe for concurrent use.
+type OrderedMap[K comparable, V any] struct {
+	keys   []K
+	values map[K]V
+}
+
+// NewOrderedMap creates an empty OrderedMap.
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{values: make(map[K]V)}
+}
+
+// Set stores value under key, appending key if it is new.
+func (m *OrderedMap[K, V]) Set(key K, value V) {
+	if m.values == nil {
+		m.values = make(map[K]V)
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get returns the value stored under key and whether it was present.
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// Delete removes key from the map. It is a no-op if key is absent.
+func (m *OrderedMap[K, V]) Delete(key K) {
+	if _, ok := m.values[key]; !ok {
+		return
+	}
+	delete(m.values, key)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Len returns the number of entries.
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.keys)
+}
+
+// Keys returns the keys in insertion order.
+func (m *OrderedMap[K, V]) Keys() []K {
+	keys := make([]K, len(m.keys))
+	copy(keys, m.keys)
+	return keys
+}
+
+// Values returns the values in key insertion order.
+func (m *OrderedMap[K, V]) Values() []V {
+	values := make([]V, len(m.keys))
+	for i, k := range m.keys {
+		values[i] = m.values[k]
+	}
+	return values
+}
+
+// ForEach calls f for each entry in insertion order.
+func (m *OrderedMap[K, V]) ForEach(f func(key K, value V)) {
+	for _, k := range m.keys {
+		f(k, m.values[k])
+	}
+}
+
+// MarshalJSON encodes the map as a JSON object with keys in insertion order.
+// Keys are formatted the same way encoding/json formats map keys: strings
+// as-is, integers in base 10, and encoding.TextMarshaler via MarshalText.
+func (m *OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		keyJSON, err := marshalMapKey(k)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(keyJSON)
+		buf.WriteByte(':')
+
+		valueJSON, err := json.Marshal(m.values[k])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal value for key %v: %w", k, err)
+		}
+		buf.Write(valueJSON)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshalMapKey encodes key as a quoted JSON object key by delegating to
+// encoding/json's map key handling.
+func marshalMapKey[K comparable](key K) ([]byte, error) {
+	data, err := json.Marshal(map[K]struct{}{key: {}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal key %v: %w", key, err)
+	}
+	// data is {"<key>":{}}; strip the braces and the trailing :{}
+	return data[1 : len(data)-len(":{}}")], nil
+}
@@ -0,0 +1,61 @@
+package generic_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/julianstephens/go-utils/generic"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestOrderedMap(t *testing.T) {
+	m := generic.NewOrderedMap[string, int]()
+	m.Set("zebra", 1)
+	m.Set("apple", 2)
+	m.Set("mango", 3)
+	m.Set("apple", 20) // update keeps position
+
+	v, ok := m.Get("apple")
+	tst.AssertTrue(t, ok, "apple should be present")
+	tst.AssertEqual(t, v, 20)
+	tst.AssertDeepEqual(t, m.Keys(), []string{"zebra", "apple", "mango"})
+
+	m.Delete("zebra")
+	m.Delete("missing")
+	m.Set("banana", 4)
+
+	_, ok = m.Get("zebra")
+	tst.AssertFalse(t, ok, "zebra should be deleted")
+	tst.AssertEqual(t, m.Len(), 3)
+	tst.AssertDeepEqual(t, m.Keys(), []string{"apple", "mango", "banana"})
+	tst.AssertDeepEqual(t, m.Values(), []int{20, 3, 4})
+
+	var visited []string
+	m.ForEach(func(k string, _ int) { visited = append(visited, k) })
+	tst.AssertDeepEqual(t, visited, []string{"apple", "mango", "banana"})
+}
+
+func TestOrderedMap_MarshalJSON(t *testing.T) {
+	m := generic.NewOrderedMap[string, any]()
+	m.Set("version", 2)
+	m.Set("name", "svc \"api\"")
+	m.Set("tags", []string{"a", "b"})
+	m.Set("obsolete", true)
+	m.Delete("obsolete")
+
+	data, err := json.Marshal(m)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, string(data), `{"version":2,"name":"svc \"api\"","tags":["a","b"]}`)
+
+	ints := generic.NewOrderedMap[int, string]()
+	ints.Set(10, "ten")
+	ints.Set(2, "two")
+	data, err = json.Marshal(ints)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, string(data), `{"10":"ten","2":"two"}`)
+
+	empty := generic.NewOrderedMap[string, int]()
+	data, err = json.Marshal(empty)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, string(data), `{}`)
+}
@@ -0,0 +1,92 @@
+package generic
+
+import (
+	"fmt"
+	"sync"
+)
+
+// WorkerResult is the outcome of one job processed by a WorkerPool.
+type WorkerResult[In, Out any] struct {
+	// Input is the submitted job
+	Input In
+	// Value is the job's result; it is the zero value if Err is set
+	Value Out
+	// Err is the error returned for the job, if any
+	Err error
+}
+
+// WorkerPool runs jobs submitted over time on a fixed number of goroutines
+// and delivers every outcome on its Results channel. Results arrive in
+// completion order, not submission order.
+type WorkerPool[In, Out any] struct {
+	mu      sync.RWMutex
+	jobs    chan In
+	results chan WorkerResult[In, Out]
+	wg      sync.WaitGroup
+	closed  bool
+}
+
+// NewWorkerPool starts workers goroutines that call fn for each submitted
+// job. The caller must keep receiving from Results until it is closed:
+// workers wait for each result to be received before taking the next job.
+// It returns an error if workers is less than 1.
+func NewWorkerPool[In, Out any](workers int, fn func(In) (Out, error)) (*WorkerPool[In, Out], error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("generic: worker pool needs at least 1 worker, got %d", workers)
+	}
+
+	p := &WorkerPool[In, Out]{
+		jobs:    make(chan In),
+		results: make(chan WorkerResult[In, Out], workers),
+	}
+	p.wg.Add(workers)
+	for range workers {
+		go func() {
+			defer p.wg.Done()
+			for job := range p.jobs {
+				value, err := fn(job)
+				p.results <- WorkerResult[In, Out]{Input: job, Value: value, Err: err}
+			}
+		}()
+	}
+	return p, nil
+}
+
+// Submit queues job, blocking until a worker takes it. It reports whether the
+// job was accepted; after Shutdown it returns false and the job is not run.
+func (p *WorkerPool[In, Out]) Submit(job In) bool {
+	// The read lock keeps the jobs channel open for the duration of the send.
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return false
+	}
+	p.jobs <- job
+	return true
+}
+
+// Results returns the channel on which job outcomes are delivered. It is
+// closed once Shutdown has been called and every accepted job has finished.
+func (p *WorkerPool[In, Out]) Results() <-chan WorkerResult[In, Out] {
+	return p.results
+}
+
+// Shutdown stops accepting jobs, waits for every accepted job to finish and
+// its result to be delivered, then closes Results. Results must still be
+// drained concurrently, or Shutdown blocks. Calling Shutdown more than once
+// is safe.
+func (p *WorkerPool[In, Out]) Shutdown() {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		p.wg.Wait()
+		return
+	}
+	p.closed = true
+	close(p.jobs)
+	p.mu.Unlock()
+
+	p.wg.Wait()
+	close(p.results)
+}
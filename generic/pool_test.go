@@ -0,0 +1,115 @@
+package generic_test
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/go-utils/generic"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestWorkerPool_CollectsAllResults(t *testing.T) {
+	errOdd := errors.New("odd")
+	pool, err := generic.NewWorkerPool(4, func(n int) (int, error) {
+		if n%2 == 1 {
+			return 0, errOdd
+		}
+		return n * n, nil
+	})
+	tst.RequireNoError(t, err)
+
+	const jobs = 100
+	got := make(map[int]generic.WorkerResult[int, int])
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for res := range pool.Results() {
+			got[res.Input] = res
+		}
+	}()
+
+	for i := range jobs {
+		tst.AssertTrue(t, pool.Submit(i), "Submit should accept jobs before Shutdown")
+	}
+	pool.Shutdown()
+	<-done
+
+	tst.AssertEqual(t, len(got), jobs)
+	for i := range jobs {
+		res := got[i]
+		if i%2 == 1 {
+			tst.AssertErrorIs(t, res.Err, errOdd)
+			continue
+		}
+		tst.AssertNoError(t, res.Err)
+		tst.AssertEqual(t, res.Value, i*i)
+	}
+}
+
+func TestWorkerPool_ConcurrentSubmit(t *testing.T) {
+	pool, err := generic.NewWorkerPool(3, func(s string) (int, error) {
+		return len(s), nil
+	})
+	tst.RequireNoError(t, err)
+
+	count := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range pool.Results() {
+			count++
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 10 {
+				pool.Submit("job")
+			}
+		}()
+	}
+	wg.Wait()
+	pool.Shutdown()
+	<-done
+
+	tst.AssertEqual(t, count, 100)
+}
+
+func TestWorkerPool_ShutdownNoLeak(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	pool, err := generic.NewWorkerPool(8, func(n int) (int, error) {
+		time.Sleep(time.Millisecond)
+		return n, nil
+	})
+	tst.RequireNoError(t, err)
+	go func() {
+		for range pool.Results() {
+		}
+	}()
+	for i := range 20 {
+		pool.Submit(i)
+	}
+	pool.Shutdown()
+	pool.Shutdown() // idempotent
+
+	tst.AssertFalse(t, pool.Submit(1), "Submit after Shutdown should report the job as rejected")
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	tst.AssertTrue(t, runtime.NumGoroutine() <= before, "worker goroutines should exit after Shutdown")
+}
+
+func TestWorkerPool_InvalidWorkers(t *testing.T) {
+	pool, err := generic.NewWorkerPool(0, func(n int) (int, error) { return n, nil })
+	tst.AssertErrorContains(t, err, "at least 1 worker")
+	tst.AssertNil(t, pool)
+}
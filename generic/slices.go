@@ -66,6 +66,12 @@ func Reverse[T any](slice []T) []T {
 	return result
 }
 
+// CloneSlice returns a shallow copy of slice, so appending to or reassigning
+// elements of the copy does not affect the original. Returns nil for a nil slice.
+func CloneSlice[T any](slice []T) []T {
+	return slices.Clone(slice)
+}
+
 // DeleteElement removes an element from a slice at the specified index and returns the modified slice.
 // Returns the original slice if the index is out of bounds.
 func DeleteElement[T any](slice []T, index int) []T {
@@ -0,0 +1,83 @@
+package generic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Semaphore bounds the number of concurrent holders, e.g. to limit parallelism.
+type Semaphore struct {
+	slots chan struct{}
+}
+
+// NewSemaphore creates a Semaphore allowing at most n concurrent holders.
+// It returns an error if n is less than 1.
+func NewSemaphore(n int) (*Semaphore, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("generic: semaphore size must be at least 1, got %d", n)
+	}
+	return &Semaphore{slots: make(chan struct{}, n)}, nil
+}
+
+// Acquire blocks until a slot is available or ctx is done, in which case it
+// returns ctx.Err().
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TryAcquire acquires a slot without blocking and reports whether it succeeded.
+func (s *Semaphore) TryAcquire() bool {
+	select {
+	case s.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Release frees a slot acquired with Acquire or TryAcquire. It panics if
+// called more times than the semaphore was acquired.
+func (s *Semaphore) Release() {
+	select {
+	case <-s.slots:
+	default:
+		panic("generic: semaphore released more times than acquired")
+	}
+}
+
+// WaitGroupErr is a sync.WaitGroup that collects the errors returned by its
+// goroutines. The zero value is ready to use.
+type WaitGroupErr struct {
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// Go runs f in a new goroutine and records its error, if any.
+func (g *WaitGroupErr) Go(f func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := f(); err != nil {
+			g.mu.Lock()
+			g.errs = append(g.errs, err)
+			g.mu.Unlock()
+		}
+	}()
+}
+
+// Wait blocks until all goroutines started with Go have returned. It returns
+// nil if none failed, otherwise all errors joined with errors.Join.
+func (g *WaitGroupErr) Wait() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return errors.Join(g.errs...)
+}
@@ -0,0 +1,84 @@
+package generic_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/go-utils/generic"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestSemaphore_BoundsConcurrency(t *testing.T) {
+	const limit = 3
+	sem, err := generic.NewSemaphore(limit)
+	tst.RequireNoError(t, err)
+
+	var current, peak atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := sem.Acquire(context.Background()); err != nil {
+				t.Error(err)
+				return
+			}
+			defer sem.Release()
+
+			n := current.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			current.Add(-1)
+		}()
+	}
+	wg.Wait()
+
+	tst.AssertTrue(t, peak.Load() <= limit, "semaphore allowed more than n concurrent holders")
+	tst.AssertTrue(t, peak.Load() > 0, "semaphore should have been held")
+}
+
+func TestSemaphore_AcquireContext(t *testing.T) {
+	sem, err := generic.NewSemaphore(1)
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, sem.TryAcquire(), "first TryAcquire should succeed")
+	tst.AssertFalse(t, sem.TryAcquire(), "second TryAcquire should fail")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	tst.AssertErrorIs(t, sem.Acquire(ctx), context.DeadlineExceeded)
+
+	sem.Release()
+	tst.AssertPanics(t, func() { sem.Release() }, "over-release should panic")
+
+	_, err = generic.NewSemaphore(0)
+	tst.AssertErrorContains(t, err, "at least 1")
+}
+
+func TestWaitGroupErr(t *testing.T) {
+	errA := errors.New("a failed")
+	errB := errors.New("b failed")
+
+	var g generic.WaitGroupErr
+	var done atomic.Int32
+	g.Go(func() error { done.Add(1); return nil })
+	g.Go(func() error { done.Add(1); return errA })
+	g.Go(func() error { done.Add(1); return errB })
+
+	err := g.Wait()
+	tst.AssertEqual(t, done.Load(), int32(3))
+	tst.AssertErrorIs(t, err, errA)
+	tst.AssertErrorIs(t, err, errB)
+
+	var ok generic.WaitGroupErr
+	ok.Go(func() error { return nil })
+	tst.AssertNoError(t, ok.Wait())
+}
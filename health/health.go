@@ -40,11 +40,12 @@ func (s Status) String() string {
 
 // Check represents a single health check with a name and status
 type Check struct {
-	Name     string // Name of the check (e.g., "database", "cache")
-	Status   Status // Health status
-	Message  string // Detailed message about the status
-	Error    error  // Error if one occurred
-	Repaired bool   // Whether this check was successfully repaired
+	Name     string        // Name of the check (e.g., "database", "cache")
+	Status   Status        // Health status
+	Message  string        // Detailed message about the status
+	Error    error         // Error if one occurred
+	Repaired bool          // Whether this check was successfully repaired
+	Duration time.Duration // How long the check took (set by RunChecks)
 }
 
 // Report aggregates multiple health checks and provides diagnostic information
@@ -80,7 +81,9 @@ func RunChecks(checkers ...Checker) Report {
 	}
 
 	for _, checker := range checkers {
+		start := time.Now()
 		check := checker.Check()
+		check.Duration = time.Since(start)
 		report.Checks = append(report.Checks, check)
 
 		if check.Status == StatusError && report.ExitCode < ExitError {
@@ -0,0 +1,61 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// MetricsContentType is the content type of the Prometheus text exposition format
+const MetricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// MetricsHandler returns an http.Handler that runs the given checkers on each
+// request and exposes their results in the Prometheus text exposition format:
+//
+//	health_check_status{check="database"} 1
+//	health_check_duration_seconds{check="database"} 0.0012
+//
+// health_check_status is 1 when a check is healthy and 0 otherwise.
+// health_check_exit_code reports the aggregated ExitCode of the run.
+func MetricsHandler(checkers ...Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := RunChecks(checkers...)
+		w.Header().Set("Content-Type", MetricsContentType)
+		_, _ = w.Write([]byte(FormatMetrics(report)))
+	})
+}
+
+// FormatMetrics renders a Report in the Prometheus text exposition format.
+func FormatMetrics(report Report) string {
+	var sb strings.Builder
+
+	sb.WriteString("# HELP health_check_status Whether the health check is healthy (1) or not (0).\n")
+	sb.WriteString("# TYPE health_check_status gauge\n")
+	for _, check := range report.Checks {
+		value := 0
+		if check.Status == StatusHealthy {
+			value = 1
+		}
+		sb.WriteString(fmt.Sprintf("health_check_status{check=\"%s\"} %d\n", escapeLabelValue(check.Name), value))
+	}
+
+	sb.WriteString("# HELP health_check_duration_seconds Duration of the health check in seconds.\n")
+	sb.WriteString("# TYPE health_check_duration_seconds gauge\n")
+	for _, check := range report.Checks {
+		sb.WriteString(fmt.Sprintf("health_check_duration_seconds{check=\"%s\"} %g\n",
+			escapeLabelValue(check.Name), check.Duration.Seconds()))
+	}
+
+	sb.WriteString(
+		"# HELP health_check_exit_code Aggregated exit code of all health checks (0=OK, 1=Warning, 2=Error).\n",
+	)
+	sb.WriteString("# TYPE health_check_exit_code gauge\n")
+	sb.WriteString(fmt.Sprintf("health_check_exit_code %d\n", report.ExitCode))
+
+	return sb.String()
+}
+
+// escapeLabelValue escapes a Prometheus label value
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,68 @@
+package health_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/go-utils/health"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	handler := health.MetricsHandler(
+		&MockChecker{name: "database", status: health.StatusHealthy},
+		&MockChecker{name: "cache", status: health.StatusError},
+	)
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	tst.AssertStatus(t, w, http.StatusOK)
+	tst.AssertHeaderEquals(t, w, "Content-Type", health.MetricsContentType)
+	tst.AssertBodyContains(t, w, "# TYPE health_check_status gauge")
+	tst.AssertBodyContains(t, w, `health_check_status{check="database"} 1`)
+	tst.AssertBodyContains(t, w, `health_check_status{check="cache"} 0`)
+	tst.AssertBodyContains(t, w, `health_check_duration_seconds{check="database"} `)
+	tst.AssertBodyContains(t, w, `health_check_duration_seconds{check="cache"} `)
+	tst.AssertBodyContains(t, w, "health_check_exit_code 2")
+}
+
+func TestFormatMetrics(t *testing.T) {
+	report := health.Report{
+		Checks: []health.Check{
+			{Name: `disk "root"`, Status: health.StatusWarning, Duration: 1500 * time.Millisecond},
+		},
+		ExitCode: health.ExitWarning,
+	}
+
+	out := health.FormatMetrics(report)
+	tst.AssertTrue(t, strings.Contains(out, `health_check_status{check="disk \"root\""} 0`), "label should be escaped")
+	tst.AssertTrue(
+		t,
+		strings.Contains(out, `health_check_duration_seconds{check="disk \"root\""} 1.5`),
+		"duration in seconds",
+	)
+	tst.AssertTrue(t, strings.Contains(out, "health_check_exit_code 1"), "exit code gauge")
+}
+
+func TestRunChecks_RecordsDuration(t *testing.T) {
+	report := health.RunChecks(&slowChecker{delay: 10 * time.Millisecond})
+	tst.AssertGreaterThanOrEqual(t, report.Checks[0].Duration, 10*time.Millisecond)
+}
+
+type slowChecker struct {
+	delay time.Duration
+}
+
+func (s *slowChecker) Check() health.Check {
+	time.Sleep(s.delay)
+	return health.NewCheck(s.Name(), health.StatusHealthy, "")
+}
+
+func (s *slowChecker) Name() string {
+	return "slow"
+}
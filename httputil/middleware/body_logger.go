@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/julianstephens/go-utils/jsonutil"
+)
+
+// DefaultBodyLogMaxSize is the default number of body bytes logged per request or response
+const DefaultBodyLogMaxSize = 4096
+
+// BodyLogConfig holds request/response body logging configuration options
+type BodyLogConfig struct {
+	// Enabled turns body logging on. When false the middleware passes requests
+	// through untouched, so it is safe to leave installed in production.
+	Enabled bool
+	// MaxSize caps the number of bytes captured and logged for each body.
+	// Bodies over the cap are truncated. Defaults to DefaultBodyLogMaxSize.
+	MaxSize int
+	// RedactFields lists JSON field paths whose values are masked in JSON
+	// bodies (see jsonutil.RedactJSON), e.g. "password" or "user.token".
+	RedactFields []string
+	// Mask replaces redacted values. Defaults to jsonutil.DefaultRedactMask.
+	Mask string
+	// Logger receives the log lines. Defaults to log.Default().
+	Logger *log.Logger
+}
+
+// DefaultBodyLogConfig returns a default body logging configuration. Logging is
+// disabled and common credential fields are redacted.
+func DefaultBodyLogConfig() BodyLogConfig {
+	return BodyLogConfig{
+		Enabled:      false,
+		MaxSize:      DefaultBodyLogMaxSize,
+		RedactFields: []string{"password", "token", "access_token", "refresh_token", "secret"},
+		Mask:         jsonutil.DefaultRedactMask,
+	}
+}
+
+// BodyLogger creates a middleware that logs request and response bodies for
+// debugging. At most MaxSize bytes of each body are buffered; the rest of the
+// request body is streamed to the handler and the rest of the response is
+// written to the client without being captured. JSON bodies have
+// RedactFields masked before logging.
+func BodyLogger(cfg BodyLogConfig) func(http.Handler) http.Handler {
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = DefaultBodyLogMaxSize
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = log.Default()
+	}
+
+	return func(next http.Handler) http.Handler {
+		if !cfg.Enabled {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var reqBody []byte
+			reqTruncated := false
+			if r.Body != nil && r.Body != http.NoBody {
+				// Read one byte past the cap to detect truncation, then replay
+				// the captured prefix ahead of the unread remainder.
+				prefix, _ := io.ReadAll(io.LimitReader(r.Body, int64(cfg.MaxSize)+1))
+				r.Body = &replayBody{Reader: io.MultiReader(bytes.NewReader(prefix), r.Body), Closer: r.Body}
+				reqBody, reqTruncated = capBody(prefix, cfg.MaxSize)
+			}
+
+			cw := &bodyCaptureWriter{
+				responseWriter: responseWriter{ResponseWriter: w, statusCode: http.StatusOK},
+				max:            cfg.MaxSize,
+			}
+			next.ServeHTTP(cw, r)
+
+			cfg.Logger.Printf("%s %s request body: %s", r.Method, r.URL.Path,
+				cfg.formatBody(reqBody, reqTruncated))
+			cfg.Logger.Printf("%s %s %d response body: %s", r.Method, r.URL.Path, cw.statusCode,
+				cfg.formatBody(cw.body.Bytes(), cw.truncated))
+		})
+	}
+}
+
+// formatBody redacts and annotates a captured body for logging.
+func (cfg BodyLogConfig) formatBody(body []byte, truncated bool) string {
+	if len(body) == 0 {
+		return "<empty>"
+	}
+	if len(cfg.RedactFields) > 0 {
+		trimmed := bytes.TrimSpace(body)
+		if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+			// On truncated JSON the redacted prefix is still returned
+			body, _ = jsonutil.RedactJSON(trimmed, cfg.Mask, cfg.RedactFields...)
+		}
+	}
+	if truncated {
+		return string(body) + "...(truncated)"
+	}
+	return string(body)
+}
+
+// capBody trims data to max bytes and reports whether it was longer.
+func capBody(data []byte, max int) ([]byte, bool) {
+	if len(data) > max {
+		return data[:max], true
+	}
+	return data, false
+}
+
+// replayBody combines a replaying reader with the original body's Close.
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
+
+// bodyCaptureWriter records up to max bytes of the response body.
+type bodyCaptureWriter struct {
+	responseWriter
+	body      bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (w *bodyCaptureWriter) Write(data []byte) (int, error) {
+	if remaining := w.max - w.body.Len(); remaining > 0 {
+		if len(data) > remaining {
+			w.body.Write(data[:remaining])
+			w.truncated = true
+		} else {
+			w.body.Write(data)
+		}
+	} else if len(data) > 0 {
+		w.truncated = true
+	}
+	return w.responseWriter.Write(data)
+}
@@ -0,0 +1,89 @@
+package middleware_test
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/go-utils/httputil/middleware"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestBodyLogger_RedactsFields(t *testing.T) {
+	var logs bytes.Buffer
+	cfg := middleware.DefaultBodyLogConfig()
+	cfg.Enabled = true
+	cfg.Logger = log.New(&logs, "", 0)
+
+	var received string
+	handler := middleware.BodyLogger(cfg)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":1,"token":"abc123"}`))
+	}))
+
+	reqBody := `{"username":"alice","password":"hunter2"}`
+	req := httptest.NewRequest("POST", "/login", strings.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	// The handler and client see the original bodies
+	tst.AssertEqual(t, received, reqBody)
+	tst.AssertStatus(t, w, http.StatusCreated)
+	tst.AssertBodyContains(t, w, `"token":"abc123"`)
+
+	out := logs.String()
+	tst.AssertTrue(t, strings.Contains(out, `POST /login request body: {"username":"alice","password":"[REDACTED]"}`),
+		"request password should be masked: "+out)
+	tst.AssertTrue(t, strings.Contains(out, `POST /login 201 response body: {"id":1,"token":"[REDACTED]"}`),
+		"response token should be masked: "+out)
+	tst.AssertFalse(t, strings.Contains(out, "hunter2") || strings.Contains(out, "abc123"), "secrets must not be logged")
+}
+
+func TestBodyLogger_TruncatesOverCap(t *testing.T) {
+	var logs bytes.Buffer
+	handler := middleware.BodyLogger(middleware.BodyLogConfig{
+		Enabled: true,
+		MaxSize: 8,
+		Logger:  log.New(&logs, "", 0),
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+
+	reqBody := strings.Repeat("a", 20)
+	req := httptest.NewRequest("PUT", "/data", strings.NewReader(reqBody))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	// The full body still reaches the handler and the client
+	tst.AssertEqual(t, w.Body.String(), reqBody)
+
+	out := logs.String()
+	tst.AssertTrue(t, strings.Contains(out, "request body: aaaaaaaa...(truncated)"), "request should be truncated: "+out)
+	tst.AssertTrue(t, strings.Contains(out, "response body: aaaaaaaa...(truncated)"), "response should be truncated: "+out)
+	tst.AssertFalse(t, strings.Contains(out, strings.Repeat("a", 9)), "nothing beyond the cap should be logged")
+}
+
+func TestBodyLogger_Disabled(t *testing.T) {
+	var logs bytes.Buffer
+	cfg := middleware.DefaultBodyLogConfig()
+	cfg.Logger = log.New(&logs, "", 0)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := middleware.BodyLogger(cfg)(next)
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader("body"))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	tst.AssertStatus(t, w, http.StatusOK)
+	tst.AssertEqual(t, logs.Len(), 0)
+}
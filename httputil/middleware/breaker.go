@@ -0,0 +1,272 @@
+package middleware
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by Breaker.Execute when the circuit is open and the
+// call was rejected without running.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets calls through and counts consecutive failures.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects calls until the cooldown elapses.
+	BreakerOpen
+	// BreakerHalfOpen lets a limited number of trial calls through; a success
+	// closes the circuit and a failure opens it again.
+	BreakerHalfOpen
+)
+
+// String returns the name of the state.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerConfig holds circuit breaker configuration options
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit.
+	FailureThreshold int
+	// Cooldown is how long the circuit stays open before allowing trial calls.
+	Cooldown time.Duration
+	// HalfOpenMaxCalls caps concurrent trial calls while half-open.
+	HalfOpenMaxCalls int
+	// IsFailure reports whether a response status counts as a failure for the
+	// CircuitBreaker middleware. Defaults to status >= 500.
+	IsFailure func(status int) bool
+	// OnStateChange, if set, is called after every state transition.
+	OnStateChange func(from, to BreakerState)
+	// Now returns the current time. Defaults to time.Now; override in tests.
+	Now func() time.Time
+}
+
+// DefaultBreakerConfig returns a default circuit breaker configuration: the
+// circuit opens after 5 consecutive failures and allows one trial call after
+// 30 seconds.
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		FailureThreshold: 5,
+		Cooldown:         30 * time.Second,
+		HalfOpenMaxCalls: 1,
+	}
+}
+
+// Breaker is a circuit breaker that fails fast while a dependency is unhealthy.
+// It is safe for concurrent use and can guard any call, not just HTTP handlers.
+type Breaker struct {
+	cfg BreakerConfig
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	trials   int
+	// generation is bumped on every state change so results from calls
+	// admitted under an earlier state are ignored.
+	generation uint64
+}
+
+// NewBreaker creates a closed Breaker. Zero-valued config fields take their
+// values from DefaultBreakerConfig.
+func NewBreaker(cfg BreakerConfig) *Breaker {
+	defaults := DefaultBreakerConfig()
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = defaults.FailureThreshold
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = defaults.Cooldown
+	}
+	if cfg.HalfOpenMaxCalls <= 0 {
+		cfg.HalfOpenMaxCalls = defaults.HalfOpenMaxCalls
+	}
+	if cfg.IsFailure == nil {
+		cfg.IsFailure = func(status int) bool { return status >= http.StatusInternalServerError }
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &Breaker{cfg: cfg}
+}
+
+// Execute runs fn if the circuit allows it and records the outcome; a non-nil
+// error from fn counts as a failure. It returns ErrCircuitOpen without calling
+// fn when the circuit is open.
+func (b *Breaker) Execute(fn func() error) error {
+	gen, err := b.allow()
+	if err != nil {
+		return err
+	}
+
+	// A panicking fn counts as a failure so half-open trial slots are released
+	completed := false
+	defer func() {
+		if !completed {
+			b.record(gen, false)
+		}
+	}()
+	err = fn()
+	completed = true
+	b.record(gen, err == nil)
+	return err
+}
+
+// State returns the current state of the circuit.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	from := b.state
+	b.refresh()
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return to
+}
+
+// retryAfter returns the time remaining until an open circuit allows trial calls.
+func (b *Breaker) retryAfter() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state != BreakerOpen {
+		return 0
+	}
+	return b.cfg.Cooldown - b.cfg.Now().Sub(b.openedAt)
+}
+
+// allow reserves permission for a call or returns ErrCircuitOpen. On success
+// it returns the generation the call was admitted under, which must be passed
+// to record.
+func (b *Breaker) allow() (uint64, error) {
+	b.mu.Lock()
+	from := b.state
+	b.refresh()
+	to := b.state
+	gen := b.generation
+
+	var err error
+	switch b.state {
+	case BreakerOpen:
+		err = ErrCircuitOpen
+	case BreakerHalfOpen:
+		if b.trials >= b.cfg.HalfOpenMaxCalls {
+			err = ErrCircuitOpen
+		} else {
+			b.trials++
+		}
+	}
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return gen, err
+}
+
+// record updates the circuit with the outcome of a call admitted under gen.
+// Outcomes from an earlier generation are stale and ignored: a slow call
+// admitted while closed must not consume a half-open trial slot or decide
+// the trial's result.
+func (b *Breaker) record(gen uint64, success bool) {
+	b.mu.Lock()
+	if gen != b.generation {
+		b.mu.Unlock()
+		return
+	}
+	from := b.state
+	switch b.state {
+	case BreakerClosed:
+		if success {
+			b.failures = 0
+		} else {
+			b.failures++
+			if b.failures >= b.cfg.FailureThreshold {
+				b.open()
+			}
+		}
+	case BreakerHalfOpen:
+		b.trials--
+		if success {
+			b.state = BreakerClosed
+			b.failures = 0
+			b.generation++
+		} else {
+			b.open()
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+// refresh moves an open circuit to half-open once the cooldown has elapsed.
+// The caller must hold b.mu.
+func (b *Breaker) refresh() {
+	if b.state == BreakerOpen && b.cfg.Now().Sub(b.openedAt) >= b.cfg.Cooldown {
+		b.state = BreakerHalfOpen
+		b.trials = 0
+		b.generation++
+	}
+}
+
+// open trips the circuit. The caller must hold b.mu.
+func (b *Breaker) open() {
+	b.state = BreakerOpen
+	b.openedAt = b.cfg.Now()
+	b.failures = 0
+	b.trials = 0
+	b.generation++
+}
+
+// notify invokes OnStateChange outside the lock if the state changed.
+func (b *Breaker) notify(from, to BreakerState) {
+	if from != to && b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(from, to)
+	}
+}
+
+// CircuitBreaker creates a middleware that guards next with a Breaker built from
+// cfg. Responses whose status satisfies cfg.IsFailure count as failures. While
+// the circuit is open, requests are rejected with 503 Service Unavailable and a
+// Retry-After header instead of reaching next.
+func CircuitBreaker(cfg BreakerConfig) func(http.Handler) http.Handler {
+	breaker := NewBreaker(cfg)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gen, err := breaker.allow()
+			if err != nil {
+				if wait := breaker.retryAfter(); wait > 0 {
+					w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				}
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+
+			completed := false
+			defer func() {
+				if !completed {
+					breaker.record(gen, false)
+				}
+			}()
+			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(rw, r)
+			completed = true
+			breaker.record(gen, !breaker.cfg.IsFailure(rw.statusCode))
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/go-utils/httputil/middleware"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+// fakeClock is a manually advanced time source for breaker tests.
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func TestBreaker_OpensAfterThreshold(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	var transitions []string
+	b := middleware.NewBreaker(middleware.BreakerConfig{
+		FailureThreshold: 3,
+		Cooldown:         10 * time.Second,
+		Now:              clock.Now,
+		OnStateChange: func(from, to middleware.BreakerState) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+	boom := errors.New("boom")
+	fail := func() error { return boom }
+
+	for i := 0; i < 2; i++ {
+		tst.AssertErrorIs(t, b.Execute(fail), boom)
+	}
+	tst.AssertEqual(t, b.State(), middleware.BreakerClosed)
+
+	tst.AssertErrorIs(t, b.Execute(fail), boom)
+	tst.AssertEqual(t, b.State(), middleware.BreakerOpen)
+
+	called := false
+	err := b.Execute(func() error { called = true; return nil })
+	tst.AssertErrorIs(t, err, middleware.ErrCircuitOpen)
+	tst.AssertFalse(t, called, "open circuit should not run fn")
+
+	clock.Advance(10 * time.Second)
+	tst.AssertEqual(t, b.State(), middleware.BreakerHalfOpen)
+
+	tst.AssertNoError(t, b.Execute(func() error { return nil }))
+	tst.AssertEqual(t, b.State(), middleware.BreakerClosed)
+	tst.AssertDeepEqual(t, transitions, []string{"closed->open", "open->half-open", "half-open->closed"})
+}
+
+func TestBreaker_HalfOpenFailureReopens(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	b := middleware.NewBreaker(middleware.BreakerConfig{FailureThreshold: 1, Cooldown: time.Second, Now: clock.Now})
+
+	_ = b.Execute(func() error { return errors.New("down") })
+	clock.Advance(time.Second)
+	tst.AssertEqual(t, b.State(), middleware.BreakerHalfOpen)
+
+	_ = b.Execute(func() error { return errors.New("still down") })
+	tst.AssertEqual(t, b.State(), middleware.BreakerOpen)
+
+	// A success resets the consecutive failure count while closed
+	clock.Advance(time.Second)
+	_ = b.Execute(func() error { return nil })
+	tst.AssertEqual(t, b.State(), middleware.BreakerClosed)
+}
+
+func TestBreaker_StaleResultIgnoredWhileHalfOpen(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	b := middleware.NewBreaker(middleware.BreakerConfig{
+		FailureThreshold: 1,
+		Cooldown:         time.Second,
+		HalfOpenMaxCalls: 1,
+		Now:              clock.Now,
+	})
+
+	// block runs fn on a goroutine until release is closed
+	block := func() (release, done chan struct{}) {
+		started := make(chan struct{})
+		release, done = make(chan struct{}), make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = b.Execute(func() error {
+				close(started)
+				<-release
+				return nil
+			})
+		}()
+		<-started
+		return release, done
+	}
+
+	// A slow call admitted while closed is still running when the circuit trips
+	releaseSlow, slowDone := block()
+	_ = b.Execute(func() error { return errors.New("down") })
+	clock.Advance(time.Second)
+	tst.AssertEqual(t, b.State(), middleware.BreakerHalfOpen)
+
+	releaseTrial, trialDone := block()
+
+	// The slow call finishes during half-open and must not close the circuit
+	// or free the trial slot
+	close(releaseSlow)
+	<-slowDone
+	tst.AssertEqual(t, b.State(), middleware.BreakerHalfOpen)
+	tst.AssertErrorIs(t, b.Execute(func() error { return nil }), middleware.ErrCircuitOpen)
+
+	close(releaseTrial)
+	<-trialDone
+	tst.AssertEqual(t, b.State(), middleware.BreakerClosed)
+}
+
+func TestCircuitBreaker_Middleware(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	status := http.StatusBadGateway
+	handler := middleware.CircuitBreaker(middleware.BreakerConfig{
+		FailureThreshold: 2,
+		Cooldown:         5 * time.Second,
+		Now:              clock.Now,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+
+	serve := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		return w
+	}
+
+	tst.AssertEqual(t, serve().Code, http.StatusBadGateway)
+	tst.AssertEqual(t, serve().Code, http.StatusBadGateway)
+
+	w := serve()
+	tst.AssertEqual(t, w.Code, http.StatusServiceUnavailable)
+	tst.AssertEqual(t, w.Header().Get("Retry-After"), "5")
+
+	clock.Advance(5 * time.Second)
+	status = http.StatusOK
+	tst.AssertEqual(t, serve().Code, http.StatusOK)
+	tst.AssertEqual(t, serve().Code, http.StatusOK)
+}
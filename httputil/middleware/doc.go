@@ -10,6 +10,8 @@ Available Middleware:
   - Recovery - Recovers from panics and returns HTTP 500
   - CORS - Handles Cross-Origin Resource Sharing
   - RequestID - Injects unique request IDs into requests
+  - TraceContext - Extracts or generates distributed trace IDs (W3C traceparent)
+  - BodyLogger - Logs size-capped, redacted request and response bodies for debugging
 
 Basic Usage:
 
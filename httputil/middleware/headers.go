@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/julianstephens/go-utils/httputil/response"
+)
+
+// HeaderSpec describes a request header contract enforced by RequireHeaders.
+type HeaderSpec struct {
+	// Name is the header name, matched case-insensitively.
+	Name string
+	// Required rejects requests that omit the header.
+	Required bool
+	// Allowed, if non-empty, lists the accepted values. For Content-Type the
+	// media types are compared case-insensitively and parameters such as
+	// charset are ignored; other headers must match a value exactly.
+	Allowed []string
+}
+
+// RequireHeaders creates middleware that enforces header contracts, such as a
+// required API version header or accepted content types. A request missing a
+// required header, or with a value not in Allowed, is rejected with a JSON
+// 400 Bad Request; a Content-Type that is not allowed is rejected with 415
+// Unsupported Media Type. Optional headers are only checked when present.
+func RequireHeaders(specs ...HeaderSpec) func(http.Handler) http.Handler {
+	responder := response.NewEmpty()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, spec := range specs {
+				value := r.Header.Get(spec.Name)
+				if strings.TrimSpace(value) == "" {
+					if spec.Required {
+						responder.BadRequest(w, r, fmt.Sprintf("missing required header: %s", spec.Name), nil)
+						return
+					}
+					continue
+				}
+				if len(spec.Allowed) == 0 {
+					continue
+				}
+
+				if http.CanonicalHeaderKey(spec.Name) == "Content-Type" {
+					if !isMediaTypeAllowed(value, spec.Allowed) {
+						responder.ErrorWithStatus(w, r, http.StatusUnsupportedMediaType, fmt.Errorf(
+							"unsupported content type %q; allowed: %s", value, strings.Join(spec.Allowed, ", ")), nil)
+						return
+					}
+					continue
+				}
+
+				if !slices.Contains(spec.Allowed, value) {
+					responder.BadRequest(w, r, fmt.Sprintf("invalid value %q for header %s; allowed: %s",
+						value, spec.Name, strings.Join(spec.Allowed, ", ")), nil)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isMediaTypeAllowed reports whether the media type of contentType, without
+// parameters, matches one of allowed.
+func isMediaTypeAllowed(contentType string, allowed []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(mediaType, strings.TrimSpace(a)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/julianstephens/go-utils/httputil/middleware"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func serveWithHeaders(mw func(http.Handler) http.Handler, headers map[string]string) *httptest.ResponseRecorder {
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("POST", "/api/items", nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequireHeaders(t *testing.T) {
+	mw := middleware.RequireHeaders(
+		middleware.HeaderSpec{Name: "X-API-Version", Required: true, Allowed: []string{"1", "2"}},
+		middleware.HeaderSpec{Name: "Content-Type", Required: true, Allowed: []string{"application/json"}},
+		middleware.HeaderSpec{Name: "X-Client", Allowed: []string{"web", "mobile"}},
+	)
+
+	t.Run("valid", func(t *testing.T) {
+		w := serveWithHeaders(mw, map[string]string{
+			"X-API-Version": "2",
+			"Content-Type":  "Application/JSON; charset=utf-8",
+		})
+		tst.AssertStatus(t, w, http.StatusOK)
+	})
+
+	t.Run("missing required header", func(t *testing.T) {
+		w := serveWithHeaders(mw, map[string]string{"Content-Type": "application/json"})
+		tst.AssertStatus(t, w, http.StatusBadRequest)
+		tst.AssertBodyContains(t, w, "missing required header: X-API-Version")
+	})
+
+	t.Run("wrong content type", func(t *testing.T) {
+		w := serveWithHeaders(mw, map[string]string{
+			"X-API-Version": "1",
+			"Content-Type":  "text/plain",
+		})
+		tst.AssertStatus(t, w, http.StatusUnsupportedMediaType)
+		tst.AssertBodyContains(t, w, "unsupported content type")
+	})
+
+	t.Run("disallowed value", func(t *testing.T) {
+		w := serveWithHeaders(mw, map[string]string{
+			"X-API-Version": "3",
+			"Content-Type":  "application/json",
+		})
+		tst.AssertStatus(t, w, http.StatusBadRequest)
+		tst.AssertBodyContains(t, w, "X-API-Version")
+	})
+
+	t.Run("optional header checked when present", func(t *testing.T) {
+		w := serveWithHeaders(mw, map[string]string{
+			"X-API-Version": "1",
+			"Content-Type":  "application/json",
+			"X-Client":      "cli",
+		})
+		tst.AssertStatus(t, w, http.StatusBadRequest)
+	})
+}
@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+const (
+	// TraceIDKey is the context key for the distributed trace ID
+	TraceIDKey contextKey = "trace_id"
+	// TraceParentHeader is the W3C Trace Context header name
+	TraceParentHeader = "traceparent"
+	// TraceIDHeader is the default fallback header name for trace IDs
+	TraceIDHeader = "X-Trace-ID"
+
+	// maxFallbackTraceIDLen bounds trace IDs accepted from the fallback header
+	maxFallbackTraceIDLen = 128
+)
+
+// TraceConfig holds trace context configuration options
+type TraceConfig struct {
+	// Header is read when no valid traceparent header is present and is
+	// set on the response with the resolved trace ID. Defaults to X-Trace-ID.
+	// Values longer than 128 characters or containing characters other than
+	// letters, digits, '-', '_', '.' and ':' are ignored.
+	Header string
+}
+
+// DefaultTraceConfig returns a default trace context configuration
+func DefaultTraceConfig() TraceConfig {
+	return TraceConfig{
+		Header: TraceIDHeader,
+	}
+}
+
+// TraceContext creates a middleware that extracts a distributed trace ID from
+// the incoming request and stores it in the request context. The W3C
+// traceparent header takes precedence, followed by the X-Trace-ID header.
+// If neither holds a valid trace ID, a new one is generated.
+func TraceContext() func(http.Handler) http.Handler {
+	return TraceContextWithConfig(DefaultTraceConfig())
+}
+
+// TraceContextWithConfig is like TraceContext but allows specifying the
+// fallback trace ID header.
+func TraceContextWithConfig(config TraceConfig) func(http.Handler) http.Handler {
+	if config.Header == "" {
+		config.Header = TraceIDHeader
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceID, ok := parseTraceParent(r.Header.Get(TraceParentHeader))
+			if !ok {
+				traceID = strings.TrimSpace(r.Header.Get(config.Header))
+				if !isValidFallbackTraceID(traceID) {
+					traceID = newTraceID()
+				}
+			}
+
+			// Add trace ID to response header
+			w.Header().Set(config.Header, traceID)
+
+			// Add trace ID to context
+			ctx := context.WithValue(r.Context(), TraceIDKey, traceID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// TraceIDFromContext retrieves the trace ID from the context
+func TraceIDFromContext(ctx context.Context) string {
+	if traceID, ok := ctx.Value(TraceIDKey).(string); ok {
+		return traceID
+	}
+	return ""
+}
+
+// PropagateTrace sets the traceparent header on an outbound request using the
+// trace ID stored in ctx, so downstream services join the same trace. A new
+// parent (span) ID is generated for each call. The trace ID is also set in the
+// X-Trace-ID header; if it is not in W3C format, only that header is set.
+func PropagateTrace(ctx context.Context, req *http.Request) {
+	PropagateTraceWithConfig(ctx, req, DefaultTraceConfig())
+}
+
+// PropagateTraceWithConfig is like PropagateTrace but sets config.Header
+// instead of X-Trace-ID, matching a TraceContextWithConfig middleware.
+func PropagateTraceWithConfig(ctx context.Context, req *http.Request, config TraceConfig) {
+	traceID := TraceIDFromContext(ctx)
+	if traceID == "" {
+		return
+	}
+	if config.Header == "" {
+		config.Header = TraceIDHeader
+	}
+
+	req.Header.Set(config.Header, traceID)
+	if isValidTraceID(traceID) {
+		req.Header.Set(TraceParentHeader, "00-"+traceID+"-"+randomHex(8)+"-01")
+	}
+}
+
+// parseTraceParent extracts the trace ID from a W3C traceparent header value
+// of the form "version-traceid-parentid-flags".
+func parseTraceParent(header string) (string, bool) {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) < 4 {
+		return "", false
+	}
+
+	version, traceID, parentID, flags := parts[0], parts[1], parts[2], parts[3]
+	if len(version) != 2 || !isHex(version) || version == "ff" {
+		return "", false
+	}
+	// Version 00 defines exactly four fields
+	if version == "00" && len(parts) != 4 {
+		return "", false
+	}
+	if !isValidTraceID(traceID) {
+		return "", false
+	}
+	if len(parentID) != 16 || !isHex(parentID) || parentID == strings.Repeat("0", 16) {
+		return "", false
+	}
+	if len(flags) != 2 || !isHex(flags) {
+		return "", false
+	}
+
+	return traceID, true
+}
+
+// isValidTraceID reports whether id is a 32-character lowercase hex string
+// that is not all zeros.
+func isValidTraceID(id string) bool {
+	return len(id) == 32 && isHex(id) && id != strings.Repeat("0", 32)
+}
+
+// isValidFallbackTraceID reports whether id is a non-empty, bounded trace ID
+// made of letters, digits, '-', '_', '.' and ':'.
+func isValidFallbackTraceID(id string) bool {
+	if id == "" || len(id) > maxFallbackTraceIDLen {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isHex reports whether s consists only of lowercase hex digits
+func isHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// newTraceID generates a new W3C-compatible trace ID
+func newTraceID() string {
+	return randomHex(16)
+}
+
+// randomHex returns n random bytes encoded as lowercase hex
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
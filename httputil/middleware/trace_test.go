@@ -0,0 +1,127 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/go-utils/httputil/middleware"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func serveTrace(
+	t *testing.T,
+	mw func(http.Handler) http.Handler,
+	headers map[string]string,
+) (string, *httptest.ResponseRecorder) {
+	t.Helper()
+	var captured string
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured = middleware.TraceIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return captured, w
+}
+
+func TestTraceContext_TraceParent(t *testing.T) {
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	traceID, w := serveTrace(t, middleware.TraceContext(), map[string]string{
+		middleware.TraceParentHeader: traceparent,
+	})
+
+	tst.AssertEqual(t, traceID, "4bf92f3577b34da6a3ce929d0e0e4736")
+	tst.AssertHeaderEquals(t, w, middleware.TraceIDHeader, traceID)
+}
+
+func TestTraceContext_FallbackHeader(t *testing.T) {
+	traceID, _ := serveTrace(t, middleware.TraceContext(), map[string]string{
+		middleware.TraceIDHeader: "upstream-trace-id",
+	})
+	tst.AssertEqual(t, traceID, "upstream-trace-id")
+
+	// Invalid traceparent falls back to the configured header
+	config := middleware.TraceConfig{Header: "X-Correlation-ID"}
+	traceID, w := serveTrace(t, middleware.TraceContextWithConfig(config), map[string]string{
+		middleware.TraceParentHeader: "00-invalid-00f067aa0ba902b7-01",
+		"X-Correlation-ID":           "correlation-123",
+	})
+	tst.AssertEqual(t, traceID, "correlation-123")
+	tst.AssertHeaderEquals(t, w, "X-Correlation-ID", "correlation-123")
+}
+
+func TestTraceContext_RejectsInvalidFallback(t *testing.T) {
+	invalid := []string{
+		"<script>alert(1)</script>",
+		"trace id with spaces",
+		"trace\x00id",
+		strings.Repeat("a", 129),
+	}
+
+	for _, header := range invalid {
+		traceID, w := serveTrace(t, middleware.TraceContext(), map[string]string{
+			middleware.TraceIDHeader: header,
+		})
+		tst.AssertEqual(t, len(traceID), 32)
+		tst.AssertTrue(t, traceID != header, "Should not accept fallback "+header)
+		tst.AssertHeaderEquals(t, w, middleware.TraceIDHeader, traceID)
+	}
+}
+
+func TestTraceContext_GeneratesID(t *testing.T) {
+	traceID, w := serveTrace(t, middleware.TraceContext(), nil)
+
+	tst.AssertEqual(t, len(traceID), 32)
+	tst.AssertHeaderEquals(t, w, middleware.TraceIDHeader, traceID)
+
+	other, _ := serveTrace(t, middleware.TraceContext(), nil)
+	tst.AssertTrue(t, traceID != other, "Generated trace IDs should be unique")
+}
+
+func TestTraceContext_RejectsInvalidTraceParent(t *testing.T) {
+	invalid := []string{
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+	}
+
+	for _, header := range invalid {
+		traceID, _ := serveTrace(t, middleware.TraceContext(), map[string]string{
+			middleware.TraceParentHeader: header,
+		})
+		tst.AssertTrue(t, traceID != "4bf92f3577b34da6a3ce929d0e0e4736", "Should not accept "+header)
+	}
+}
+
+func TestPropagateTrace(t *testing.T) {
+	traceID := "4bf92f3577b34da6a3ce929d0e0e4736"
+	ctx := context.WithValue(context.Background(), middleware.TraceIDKey, traceID)
+
+	req := httptest.NewRequest("GET", "http://downstream/api", nil)
+	middleware.PropagateTrace(ctx, req)
+
+	tst.AssertEqual(t, req.Header.Get(middleware.TraceIDHeader), traceID)
+	traceparent := req.Header.Get(middleware.TraceParentHeader)
+	tst.AssertTrue(t, strings.HasPrefix(traceparent, "00-"+traceID+"-"), "traceparent should carry trace ID")
+
+	// The configured header is used instead of X-Trace-ID
+	req = httptest.NewRequest("GET", "http://downstream/api", nil)
+	middleware.PropagateTraceWithConfig(ctx, req, middleware.TraceConfig{Header: "X-Correlation-ID"})
+	tst.AssertEqual(t, req.Header.Get("X-Correlation-ID"), traceID)
+	tst.AssertEqual(t, req.Header.Get(middleware.TraceIDHeader), "")
+
+	// Empty context leaves the request untouched
+	req = httptest.NewRequest("GET", "http://downstream/api", nil)
+	middleware.PropagateTrace(context.Background(), req)
+	tst.AssertEqual(t, req.Header.Get(middleware.TraceParentHeader), "")
+}
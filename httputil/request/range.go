@@ -0,0 +1,143 @@
+package request
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxRanges is the most ranges ParseRange accepts in one Range header. Larger
+// lists are rejected with ErrRangeNotSatisfiable, since many small ranges make
+// a cheap request expensive to serve.
+const MaxRanges = 16
+
+var (
+	// ErrInvalidRange is returned when the Range header is malformed. Servers
+	// may ignore such a header and send the full content.
+	ErrInvalidRange = errors.New("invalid range header")
+	// ErrRangeNotSatisfiable is returned when none of the requested ranges
+	// overlap the content or more than MaxRanges are requested. Respond with
+	// 416 Range Not Satisfiable and a "Content-Range: bytes */<size>" header.
+	ErrRangeNotSatisfiable = errors.New("range not satisfiable")
+)
+
+// HTTPRange is a byte range of a resource, as requested by a Range header.
+type HTTPRange struct {
+	Start  int64
+	Length int64
+}
+
+// ContentRange returns the Content-Range header value for the range within
+// content of the given total size, e.g. "bytes 0-499/1234".
+func (r HTTPRange) ContentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.Start, r.Start+r.Length-1, size)
+}
+
+// ParseRange parses the request's Range header into byte ranges clamped to
+// content of the given size. It supports "bytes=start-end", open-ended
+// "bytes=start-", suffix "bytes=-n" (the last n bytes), and comma-separated
+// lists of these. It returns nil ranges and a nil error if the header is
+// absent, ErrInvalidRange if it is malformed, and ErrRangeNotSatisfiable if
+// no range overlaps the content or the header lists more than MaxRanges
+// ranges. Ranges that do not overlap the content are dropped when at least one
+// other range does. The result is sorted by start, with overlapping and
+// adjacent ranges merged, so no byte is sent twice.
+func ParseRange(r *http.Request, size int64) ([]HTTPRange, error) {
+	header := r.Header.Get("Range")
+	if header == "" {
+		return nil, nil
+	}
+	if size < 0 {
+		return nil, fmt.Errorf("%w: negative content size", ErrInvalidRange)
+	}
+
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported unit in %q", ErrInvalidRange, header)
+	}
+
+	var ranges []HTTPRange
+	requested := 0
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		requested++
+		if requested > MaxRanges {
+			return nil, fmt.Errorf("%w: more than %d ranges", ErrRangeNotSatisfiable, MaxRanges)
+		}
+
+		startStr, endStr, ok := strings.Cut(part, "-")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRange, part)
+		}
+		startStr, endStr = strings.TrimSpace(startStr), strings.TrimSpace(endStr)
+
+		if startStr == "" {
+			// Suffix range: the last n bytes
+			n, err := parseRangeInt(endStr)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidRange, part)
+			}
+			if n == 0 || size == 0 {
+				continue
+			}
+			n = min(n, size)
+			ranges = append(ranges, HTTPRange{Start: size - n, Length: n})
+			continue
+		}
+
+		start, err := parseRangeInt(startStr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidRange, part)
+		}
+		end := size - 1
+		if endStr != "" {
+			e, err := parseRangeInt(endStr)
+			if err != nil || e < start {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidRange, part)
+			}
+			end = min(e, size-1)
+		}
+		if start >= size {
+			continue
+		}
+		ranges = append(ranges, HTTPRange{Start: start, Length: end - start + 1})
+	}
+
+	if requested == 0 {
+		return nil, fmt.Errorf("%w: no ranges in %q", ErrInvalidRange, header)
+	}
+	if len(ranges) == 0 {
+		return nil, ErrRangeNotSatisfiable
+	}
+	return mergeRanges(ranges), nil
+}
+
+// mergeRanges sorts ranges by start and merges those that overlap or touch.
+func mergeRanges(ranges []HTTPRange) []HTTPRange {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if end := last.Start + last.Length; r.Start <= end {
+			last.Length = max(end, r.Start+r.Length) - last.Start
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// parseRangeInt parses a non-negative decimal range bound.
+func parseRangeInt(s string) (int64, error) {
+	if s == "" || s[0] == '+' || s[0] == '-' {
+		return 0, strconv.ErrSyntax
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
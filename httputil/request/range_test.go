@@ -0,0 +1,113 @@
+package request_test
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/go-utils/httputil/request"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func rangeRequest(header string) *http.Request {
+	req, _ := http.NewRequest("GET", "/file", nil)
+	if header != "" {
+		req.Header.Set("Range", header)
+	}
+	return req
+}
+
+func TestParseRange_Single(t *testing.T) {
+	ranges, err := request.ParseRange(rangeRequest("bytes=0-499"), 1000)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, ranges, []request.HTTPRange{{Start: 0, Length: 500}})
+	tst.AssertEqual(t, ranges[0].ContentRange(1000), "bytes 0-499/1000")
+
+	// An open or overlong end is clamped to the content
+	ranges, err = request.ParseRange(rangeRequest("bytes=900-"), 1000)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, ranges, []request.HTTPRange{{Start: 900, Length: 100}})
+	ranges, err = request.ParseRange(rangeRequest("bytes=900-5000"), 1000)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, ranges, []request.HTTPRange{{Start: 900, Length: 100}})
+}
+
+func TestParseRange_Multiple(t *testing.T) {
+	ranges, err := request.ParseRange(rangeRequest("bytes=0-99, 200-299,-50"), 1000)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, ranges, []request.HTTPRange{
+		{Start: 0, Length: 100},
+		{Start: 200, Length: 100},
+		{Start: 950, Length: 50},
+	})
+
+	// Ranges past the end are dropped when others are satisfiable
+	ranges, err = request.ParseRange(rangeRequest("bytes=0-9,5000-6000"), 1000)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, ranges, []request.HTTPRange{{Start: 0, Length: 10}})
+}
+
+func TestParseRange_Merges(t *testing.T) {
+	// Overlapping and adjacent ranges collapse, and the result is sorted
+	ranges, err := request.ParseRange(rangeRequest("bytes=500-599,0-99,50-149,150-199,-450"), 1000)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, ranges, []request.HTTPRange{
+		{Start: 0, Length: 200},
+		{Start: 500, Length: 500},
+	})
+
+	// Repeating the same range does not multiply the response
+	ranges, err = request.ParseRange(rangeRequest("bytes=0-,0-,0-"), 1000)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, ranges, []request.HTTPRange{{Start: 0, Length: 1000}})
+}
+
+func TestParseRange_TooMany(t *testing.T) {
+	parts := make([]string, request.MaxRanges+1)
+	for i := range parts {
+		parts[i] = strconv.Itoa(i*10) + "-" + strconv.Itoa(i*10+1)
+	}
+
+	_, err := request.ParseRange(rangeRequest("bytes="+strings.Join(parts, ",")), 1000)
+	tst.AssertErrorIs(t, err, request.ErrRangeNotSatisfiable)
+
+	ranges, err := request.ParseRange(rangeRequest("bytes="+strings.Join(parts[:request.MaxRanges], ",")), 1000)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, len(ranges), request.MaxRanges)
+}
+
+func TestParseRange_Suffix(t *testing.T) {
+	ranges, err := request.ParseRange(rangeRequest("bytes=-500"), 1234)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, ranges, []request.HTTPRange{{Start: 734, Length: 500}})
+	tst.AssertEqual(t, ranges[0].ContentRange(1234), "bytes 734-1233/1234")
+
+	// A suffix longer than the content selects all of it
+	ranges, err = request.ParseRange(rangeRequest("bytes=-500"), 100)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, ranges, []request.HTTPRange{{Start: 0, Length: 100}})
+}
+
+func TestParseRange_Unsatisfiable(t *testing.T) {
+	for _, header := range []string{"bytes=1000-1999", "bytes=1000-", "bytes=-0", "bytes=1000-1999,2000-"} {
+		_, err := request.ParseRange(rangeRequest(header), 1000)
+		tst.AssertErrorIs(t, err, request.ErrRangeNotSatisfiable)
+	}
+
+	_, err := request.ParseRange(rangeRequest("bytes=-10"), 0)
+	tst.AssertErrorIs(t, err, request.ErrRangeNotSatisfiable)
+}
+
+func TestParseRange_Invalid(t *testing.T) {
+	for _, header := range []string{"items=0-9", "bytes=", "bytes=abc", "bytes=9-0", "bytes=-", "bytes=1--2", "bytes=+1-2"} {
+		_, err := request.ParseRange(rangeRequest(header), 1000)
+		tst.AssertErrorIs(t, err, request.ErrInvalidRange)
+	}
+}
+
+func TestParseRange_NoHeader(t *testing.T) {
+	ranges, err := request.ParseRange(rangeRequest(""), 1000)
+	tst.RequireNoError(t, err)
+	tst.AssertNil(t, ranges)
+}
@@ -0,0 +1,91 @@
+package response
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Attachment streams content as a file download named filename.
+// It sets Content-Type and a Content-Disposition header carrying both an ASCII
+// fallback filename and an RFC 5987 encoded filename* parameter, so non-ASCII
+// names survive intact in modern browsers. An empty contentType defaults to
+// application/octet-stream. The returned error reports a failure while
+// streaming; by then the headers have already been sent.
+func (r *Responder) Attachment(
+	w http.ResponseWriter,
+	req *http.Request,
+	filename string,
+	contentType string,
+	content io.Reader,
+) error {
+	if r.Before != nil {
+		r.Before(w, req, filename)
+	}
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", contentDisposition("attachment", filename))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, content); err != nil {
+		return fmt.Errorf("failed to stream attachment: %w", err)
+	}
+
+	if r.After != nil {
+		r.After(w, req, filename)
+	}
+	return nil
+}
+
+// contentDisposition builds a Content-Disposition header value for the given
+// disposition type ("attachment" or "inline") and filename. Non-ASCII names are
+// emitted as an RFC 5987 filename* parameter alongside an ASCII fallback.
+func contentDisposition(disposition, filename string) string {
+	fallback := asciiFilename(filename)
+	if fallback == filename {
+		return fmt.Sprintf("%s; filename=%q", disposition, filename)
+	}
+	return fmt.Sprintf("%s; filename=%q; filename*=UTF-8''%s", disposition, fallback, encodeRFC5987(filename))
+}
+
+// asciiFilename replaces characters that cannot appear in a quoted-string
+// filename parameter with underscores.
+func asciiFilename(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e || r == '"' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// encodeRFC5987 percent-encodes s as an RFC 5987 ext-value, leaving only
+// attr-char bytes unescaped.
+func encodeRFC5987(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isAttrChar(c) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0x0f])
+	}
+	return b.String()
+}
+
+func isAttrChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", c) >= 0
+}
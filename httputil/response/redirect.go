@@ -0,0 +1,118 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/julianstephens/go-utils/security"
+)
+
+// FlashCookieName is the name of the cookie carrying flash messages between a
+// redirect and the request that follows it.
+const FlashCookieName = "flash"
+
+// FlashMaxAge is the lifetime of a flash cookie. It only needs to survive the
+// redirect, so a flash that is never read expires quickly instead of lasting
+// the whole browser session.
+const FlashMaxAge = time.Minute
+
+var (
+	// ErrInvalidRedirectStatus is returned when a redirect is requested with a
+	// status code outside the 3xx range.
+	ErrInvalidRedirectStatus = errors.New("redirect status must be 3xx")
+	// ErrNilFlashCodec is returned when a flash cookie is written or read
+	// without a cookie codec.
+	ErrNilFlashCodec = errors.New("flash cookie codec is nil")
+)
+
+// Redirect replies with a redirect to url using status, which must be a 3xx
+// code (typically 303 See Other after a form POST, or 302/307/308). Relative
+// URLs are resolved against the request path as http.Redirect does. It returns
+// ErrInvalidRedirectStatus without writing anything for any other status.
+func (r *Responder) Redirect(w http.ResponseWriter, req *http.Request, url string, status int) error {
+	if status < 300 || status > 399 {
+		return fmt.Errorf("%w: got %d", ErrInvalidRedirectStatus, status)
+	}
+
+	if r.Before != nil {
+		r.Before(w, req, url)
+	}
+
+	http.Redirect(w, req, url, status)
+
+	if r.After != nil {
+		r.After(w, req, url)
+	}
+	return nil
+}
+
+// RedirectWithFlash is like Redirect but also sets a flash cookie holding
+// flash, signed (and encrypted, if the codec has a block key) by codec. Read
+// it on the next request with ReadFlash; the cookie expires after FlashMaxAge.
+// flash must be JSON-serializable and small enough to fit in a cookie. It
+// returns ErrNilFlashCodec without writing anything if codec is nil.
+func (r *Responder) RedirectWithFlash(
+	w http.ResponseWriter,
+	req *http.Request,
+	url string,
+	status int,
+	codec *security.CookieCodec,
+	flash any,
+) error {
+	if status < 300 || status > 399 {
+		return fmt.Errorf("%w: got %d", ErrInvalidRedirectStatus, status)
+	}
+	if codec == nil {
+		return ErrNilFlashCodec
+	}
+
+	value, err := codec.Encode(FlashCookieName, flash)
+	if err != nil {
+		return fmt.Errorf("failed to encode flash cookie: %w", err)
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     FlashCookieName,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   int(FlashMaxAge / time.Second),
+		HttpOnly: true,
+		Secure:   req.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return r.Redirect(w, req, url, status)
+}
+
+// ReadFlash decodes the flash cookie set by RedirectWithFlash into dst and
+// clears it so the message is shown only once. It reports whether a flash was
+// present. A cookie that fails verification is cleared and its error returned
+// (wrapping security.ErrInvalidCookie when tampered with). Call it before
+// writing the response body so the clearing cookie can still be sent. It
+// returns ErrNilFlashCodec if codec is nil.
+func ReadFlash(w http.ResponseWriter, req *http.Request, codec *security.CookieCodec, dst any) (bool, error) {
+	if codec == nil {
+		return false, ErrNilFlashCodec
+	}
+
+	cookie, err := req.Cookie(FlashCookieName)
+	if err != nil {
+		return false, nil
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     FlashCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   req.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	if err := codec.Decode(FlashCookieName, cookie.Value, dst); err != nil {
+		return false, err
+	}
+	return true, nil
+}
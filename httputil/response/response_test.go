@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/julianstephens/go-utils/httputil/response"
+	"github.com/julianstephens/go-utils/security"
 	testhelpers "github.com/julianstephens/go-utils/tests"
 )
 
@@ -255,3 +256,149 @@ func TestErrorWithStatus(t *testing.T) {
 		}
 	})
 }
+
+func TestResponder_Attachment(t *testing.T) {
+	responder := response.New()
+	req, w := testhelpers.NewRequestAndRecorder("GET", "/download")
+
+	err := responder.Attachment(w, req, "résumé 2024.pdf", "application/pdf", strings.NewReader("file contents"))
+	if err != nil {
+		t.Fatalf("Attachment returned error: %v", err)
+	}
+
+	testhelpers.AssertStatus(t, w, 200)
+	testhelpers.AssertBodyEquals(t, w, "file contents")
+	if got := w.Header().Get("Content-Type"); got != "application/pdf" {
+		t.Errorf("Expected Content-Type application/pdf, got %s", got)
+	}
+	want := `attachment; filename="r_sum_ 2024.pdf"; filename*=UTF-8''r%C3%A9sum%C3%A9%202024.pdf`
+	if got := w.Header().Get("Content-Disposition"); got != want {
+		t.Errorf("Expected Content-Disposition %s, got %s", want, got)
+	}
+}
+
+func TestResponder_AttachmentASCII(t *testing.T) {
+	responder := response.New()
+	req, w := testhelpers.NewRequestAndRecorder("GET", "/download")
+
+	if err := responder.Attachment(w, req, "report.csv", "", strings.NewReader("a,b")); err != nil {
+		t.Fatalf("Attachment returned error: %v", err)
+	}
+
+	if got := w.Header().Get("Content-Disposition"); got != `attachment; filename="report.csv"` {
+		t.Errorf("Unexpected Content-Disposition: %s", got)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/octet-stream" {
+		t.Errorf("Expected default Content-Type, got %s", got)
+	}
+}
+
+func TestResponder_Redirect(t *testing.T) {
+	var beforeData, afterData any
+	responder := response.New()
+	responder.Before = func(w http.ResponseWriter, r *http.Request, data any) { beforeData = data }
+	responder.After = func(w http.ResponseWriter, r *http.Request, data any) { afterData = data }
+	req, w := testhelpers.NewRequestAndRecorder("POST", "/items")
+
+	if err := responder.Redirect(w, req, "/items/42", http.StatusSeeOther); err != nil {
+		t.Fatalf("Redirect returned error: %v", err)
+	}
+
+	testhelpers.AssertStatus(t, w, http.StatusSeeOther)
+	if got := w.Header().Get("Location"); got != "/items/42" {
+		t.Errorf("Expected Location /items/42, got %s", got)
+	}
+	if beforeData != "/items/42" || afterData != "/items/42" {
+		t.Errorf("Expected hooks to receive the URL, got %v and %v", beforeData, afterData)
+	}
+}
+
+func TestResponder_RedirectInvalidStatus(t *testing.T) {
+	responder := response.New()
+	for _, status := range []int{http.StatusOK, http.StatusNotFound, 0} {
+		req, w := testhelpers.NewRequestAndRecorder("GET", "/")
+		err := responder.Redirect(w, req, "/elsewhere", status)
+		if !errors.Is(err, response.ErrInvalidRedirectStatus) {
+			t.Errorf("Expected ErrInvalidRedirectStatus for %d, got %v", status, err)
+		}
+		if got := w.Header().Get("Location"); got != "" {
+			t.Errorf("Expected no Location header for %d, got %s", status, got)
+		}
+	}
+}
+
+func TestResponder_RedirectWithFlash(t *testing.T) {
+	codec, err := security.NewCookieCodec([]byte("0123456789abcdef0123456789abcdef"), nil)
+	if err != nil {
+		t.Fatalf("NewCookieCodec returned error: %v", err)
+	}
+	responder := response.New()
+	req, w := testhelpers.NewRequestAndRecorder("POST", "/items")
+
+	err = responder.RedirectWithFlash(w, req, "/items", http.StatusSeeOther, codec, map[string]string{"notice": "Item saved"})
+	if err != nil {
+		t.Fatalf("RedirectWithFlash returned error: %v", err)
+	}
+	testhelpers.AssertStatus(t, w, http.StatusSeeOther)
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != response.FlashCookieName {
+		t.Fatalf("Expected one flash cookie, got %v", cookies)
+	}
+	if cookies[0].MaxAge != int(response.FlashMaxAge.Seconds()) {
+		t.Errorf("Expected the flash cookie to expire after %v, got MaxAge %d", response.FlashMaxAge, cookies[0].MaxAge)
+	}
+
+	// The next request reads and clears the flash
+	next := httptest.NewRequest("GET", "/items", nil)
+	next.AddCookie(cookies[0])
+	nextW := httptest.NewRecorder()
+	var flash map[string]string
+	found, err := response.ReadFlash(nextW, next, codec, &flash)
+	if err != nil || !found {
+		t.Fatalf("Expected flash to be found, got found=%v err=%v", found, err)
+	}
+	if flash["notice"] != "Item saved" {
+		t.Errorf("Expected flash notice, got %v", flash)
+	}
+	cleared := nextW.Result().Cookies()
+	if len(cleared) != 1 || cleared[0].MaxAge >= 0 {
+		t.Errorf("Expected the flash cookie to be cleared, got %v", cleared)
+	}
+
+	// No cookie means no flash
+	found, err = response.ReadFlash(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil), codec, &flash)
+	if err != nil || found {
+		t.Errorf("Expected no flash, got found=%v err=%v", found, err)
+	}
+
+	// A tampered cookie is rejected
+	tampered := httptest.NewRequest("GET", "/items", nil)
+	tampered.AddCookie(&http.Cookie{Name: response.FlashCookieName, Value: cookies[0].Value + "x"})
+	_, err = response.ReadFlash(httptest.NewRecorder(), tampered, codec, &flash)
+	if !errors.Is(err, security.ErrInvalidCookie) {
+		t.Errorf("Expected ErrInvalidCookie, got %v", err)
+	}
+
+	req, w = testhelpers.NewRequestAndRecorder("POST", "/items")
+	err = responder.RedirectWithFlash(w, req, "/items", http.StatusOK, codec, "ignored")
+	if !errors.Is(err, response.ErrInvalidRedirectStatus) {
+		t.Errorf("Expected ErrInvalidRedirectStatus, got %v", err)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("Expected no cookie for an invalid redirect status")
+	}
+
+	// A nil codec is an error, not a panic
+	req, w = testhelpers.NewRequestAndRecorder("POST", "/items")
+	err = responder.RedirectWithFlash(w, req, "/items", http.StatusSeeOther, nil, "ignored")
+	if !errors.Is(err, response.ErrNilFlashCodec) {
+		t.Errorf("Expected ErrNilFlashCodec, got %v", err)
+	}
+	if w.Header().Get("Location") != "" || len(w.Result().Cookies()) != 0 {
+		t.Error("Expected nothing written for a nil codec")
+	}
+	if _, err := response.ReadFlash(httptest.NewRecorder(), next, nil, &flash); !errors.Is(err, response.ErrNilFlashCodec) {
+		t.Errorf("Expected ErrNilFlashCodec from ReadFlash, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// errExtractDone stops the token walk once every requested path is found.
+var errExtractDone = errors.New("extract done")
+
+// Extract reads a JSON document from r as a token stream and returns the
+// values found at the requested paths, keyed by path. Each path is a
+// dot-separated list of object keys and array indexes from the document root,
+// such as "user.address.city" or "items.0.id"; keys are matched exactly.
+// Matched values are decoded as by json.Unmarshal into an any; subtrees that
+// cannot contain a requested path are skipped without being decoded. Reading
+// stops as soon as all paths have been found, so the rest of a large document
+// is never consumed.
+//
+// Paths that do not occur in the document are absent from the result. If the
+// document is invalid before all paths are found, the values collected so far
+// are returned with a non-nil error.
+func Extract(r io.Reader, paths []string) (map[string]any, error) {
+	e := &extractor{
+		dec:      json.NewDecoder(r),
+		want:     make(map[string]struct{}, len(paths)),
+		prefixes: make(map[string]struct{}),
+		found:    make(map[string]any, len(paths)),
+	}
+	for _, p := range paths {
+		if p == "" {
+			continue
+		}
+		e.want[p] = struct{}{}
+		for i := 0; i < len(p); i++ {
+			if p[i] == '.' {
+				e.prefixes[p[:i]] = struct{}{}
+			}
+		}
+	}
+	if len(e.want) == 0 {
+		return e.found, nil
+	}
+
+	if err := e.walk(""); err != nil && !errors.Is(err, errExtractDone) {
+		return e.found, fmt.Errorf("jsonutil: extract failed: %w", err)
+	}
+	return e.found, nil
+}
+
+type extractor struct {
+	dec      *json.Decoder
+	want     map[string]struct{}
+	prefixes map[string]struct{}
+	found    map[string]any
+}
+
+// walk reads the container value at path, visiting its children.
+func (e *extractor) walk(path string) error {
+	tok, err := e.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil
+	}
+
+	switch delim {
+	case '{':
+		for e.dec.More() {
+			keyTok, err := e.dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := keyTok.(string)
+			if err := e.visit(joinPath(path, key)); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for i := 0; e.dec.More(); i++ {
+			if err := e.visit(joinPath(path, strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = e.dec.Token()
+	return err
+}
+
+// visit handles the next value in the stream, which is located at path.
+func (e *extractor) visit(path string) error {
+	if _, ok := e.want[path]; ok {
+		if _, seen := e.found[path]; !seen {
+			var v any
+			if err := e.dec.Decode(&v); err != nil {
+				return err
+			}
+			e.found[path] = v
+			e.collectNested(path, v)
+			if len(e.found) == len(e.want) {
+				return errExtractDone
+			}
+			return nil
+		}
+	}
+	if _, ok := e.prefixes[path]; ok {
+		return e.walk(path)
+	}
+	return skipValue(e.dec)
+}
+
+// collectNested records requested paths below path from its decoded value v,
+// since the stream has already moved past them.
+func (e *extractor) collectNested(path string, v any) {
+	prefix := path + "."
+	for want := range e.want {
+		rest, ok := strings.CutPrefix(want, prefix)
+		if !ok {
+			continue
+		}
+		if _, seen := e.found[want]; seen {
+			continue
+		}
+		if nested, ok := lookupPath(v, strings.Split(rest, ".")); ok {
+			e.found[want] = nested
+		}
+	}
+}
+
+// lookupPath resolves keys against a value decoded into any.
+func lookupPath(v any, keys []string) (any, bool) {
+	for _, key := range keys {
+		switch node := v.(type) {
+		case map[string]any:
+			child, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			v = child
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// joinPath appends a key or index to a dot-separated path.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,67 @@
+package jsonutil_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/go-utils/jsonutil"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+const extractDoc = `{
+	"meta": {"version": 3, "tags": ["a", "b"]},
+	"items": [
+		{"id": 1, "name": "first", "attrs": {"color": "red"}},
+		{"id": 2, "name": "second", "attrs": {"color": "blue"}}
+	],
+	"user": {"name": "alice", "address": {"city": "Lisbon", "zip": "1000"}},
+	"trailer": {"checksum": "abc"}
+}`
+
+func TestExtract(t *testing.T) {
+	got, err := jsonutil.Extract(strings.NewReader(extractDoc), []string{"user.address.city", "items.1.attrs.color"})
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, got, map[string]any{
+		"user.address.city":   "Lisbon",
+		"items.1.attrs.color": "blue",
+	})
+}
+
+func TestExtract_ContainersAndMissing(t *testing.T) {
+	got, err := jsonutil.Extract(strings.NewReader(extractDoc),
+		[]string{"meta.tags", "meta.version", "user", "user.name", "missing.path", "items.5.id"})
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, got, map[string]any{
+		"meta.tags":    []any{"a", "b"},
+		"meta.version": float64(3),
+		"user": map[string]any{
+			"name":    "alice",
+			"address": map[string]any{"city": "Lisbon", "zip": "1000"},
+		},
+		"user.name": "alice",
+	})
+}
+
+// failingReader returns an error if it is ever read.
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read past the requested fields")
+}
+
+func TestExtract_StopsEarly(t *testing.T) {
+	head := `{"id": "42", "owner": {"name": "bob"}, "payload": `
+	r := io.MultiReader(strings.NewReader(head), failingReader{})
+
+	got, err := jsonutil.Extract(r, []string{"id", "owner.name"})
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, got, map[string]any{"id": "42", "owner.name": "bob"})
+}
+
+func TestExtract_InvalidDocument(t *testing.T) {
+	got, err := jsonutil.Extract(strings.NewReader(`{"a": 1, "b": [1, 2`), []string{"a", "c"})
+	tst.AssertErrorContains(t, err, "jsonutil: extract failed")
+	tst.AssertDeepEqual(t, got, map[string]any{"a": float64(1)})
+}
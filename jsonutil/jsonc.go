@@ -0,0 +1,114 @@
+package jsonutil
+
+import (
+	"errors"
+	"fmt"
+)
+
+// UnmarshalJSONC unmarshals JSONC (JSON with comments) into v. Line (//) and
+// block (/* */) comments and trailing commas before a closing } or ] are
+// removed before decoding; comment-like sequences inside strings, such as
+// "https://example.com", are left untouched.
+func UnmarshalJSONC(data []byte, v any) error {
+	clean, err := StripJSONC(data)
+	if err != nil {
+		return fmt.Errorf("jsonutil: jsonc unmarshal failed: %w", err)
+	}
+	return Unmarshal(clean, v)
+}
+
+// StripJSONC converts JSONC to standard JSON by removing comments and trailing
+// commas. Comments are replaced by whitespace (newlines are kept) so that
+// offsets and line numbers in later decoding errors still match the input.
+func StripJSONC(data []byte) ([]byte, error) {
+	out, err := stripComments(data)
+	if err != nil {
+		return nil, err
+	}
+	stripTrailingCommas(out)
+	return out, nil
+}
+
+// stripComments returns a copy of data with comments outside strings blanked out.
+func stripComments(data []byte) ([]byte, error) {
+	out := make([]byte, len(data))
+	copy(out, data)
+
+	inString := false
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		if inString {
+			switch c {
+			case '\\':
+				i++ // skip the escaped character
+			case '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			start := i
+			out[i], out[i+1] = ' ', ' '
+			for i += 2; ; i++ {
+				if i+1 >= len(out) {
+					return nil, fmt.Errorf("unterminated block comment at offset %d", start)
+				}
+				if out[i] == '*' && out[i+1] == '/' {
+					out[i], out[i+1] = ' ', ' '
+					i++
+					break
+				}
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+		}
+	}
+	if inString {
+		return nil, errors.New("unterminated string")
+	}
+	return out, nil
+}
+
+// stripTrailingCommas blanks out commas that are followed (ignoring whitespace)
+// by a closing } or ]. data must not contain comments.
+func stripTrailingCommas(data []byte) {
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			switch c {
+			case '\\':
+				i++
+			case '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case ',':
+			j := i + 1
+			for j < len(data) && isJSONSpace(data[j]) {
+				j++
+			}
+			if j < len(data) && (data[j] == '}' || data[j] == ']') {
+				data[i] = ' '
+			}
+		}
+	}
+}
+
+func isJSONSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,56 @@
+package jsonutil_test
+
+import (
+	"testing"
+
+	"github.com/julianstephens/go-utils/jsonutil"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestUnmarshalJSONC(t *testing.T) {
+	data := []byte(`{
+	// service settings
+	"name": "api", /* inline */
+	"homepage": "https://example.com/docs",
+	"pattern": "/* not a comment */",
+	"escaped": "quote \" // still a string",
+	"ports": [
+		8080,
+		8443, // trailing comma below
+	],
+	/*
+	 * multi-line block
+	 */
+	"debug": true,
+}`)
+
+	var cfg struct {
+		Name     string `json:"name"`
+		Homepage string `json:"homepage"`
+		Pattern  string `json:"pattern"`
+		Escaped  string `json:"escaped"`
+		Ports    []int  `json:"ports"`
+		Debug    bool   `json:"debug"`
+	}
+	err := jsonutil.UnmarshalJSONC(data, &cfg)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, cfg.Name, "api")
+	tst.AssertEqual(t, cfg.Homepage, "https://example.com/docs")
+	tst.AssertEqual(t, cfg.Pattern, "/* not a comment */")
+	tst.AssertEqual(t, cfg.Escaped, `quote " // still a string`)
+	tst.AssertDeepEqual(t, cfg.Ports, []int{8080, 8443})
+	tst.AssertTrue(t, cfg.Debug, "debug should be true")
+}
+
+func TestStripJSONC(t *testing.T) {
+	out, err := jsonutil.StripJSONC([]byte("{\"a\": [1, 2,], // c\n}"))
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, jsonutil.Valid(out), "stripped output should be valid JSON")
+	tst.AssertEqual(t, len(out), len("{\"a\": [1, 2,], // c\n}"))
+
+	_, err = jsonutil.StripJSONC([]byte(`{"a": 1 /* open`))
+	tst.AssertErrorContains(t, err, "unterminated block comment")
+
+	err = jsonutil.UnmarshalJSONC([]byte(`{"a": "open`), &map[string]any{})
+	tst.AssertErrorContains(t, err, "unterminated string")
+}
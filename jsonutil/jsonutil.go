@@ -138,6 +138,40 @@ func UnmarshalWithOptions(data []byte, v any, opts *UnmarshalOptions) error {
 	return nil
 }
 
+// UnmarshalPreserveNumbers unmarshals JSON data into a generic map, decoding
+// numbers as json.Number instead of float64. This keeps integers larger than
+// 2^53 (such as 64-bit IDs) exact, which would otherwise lose precision. As
+// with json.Unmarshal, data must hold exactly one JSON value; trailing data
+// other than whitespace is an error.
+func UnmarshalPreserveNumbers(data []byte, v *map[string]any) error {
+	if v == nil {
+		return fmt.Errorf("jsonutil: unmarshal preserve numbers failed: nil destination")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("jsonutil: unmarshal preserve numbers failed: %w", err)
+	}
+	// Like json.Unmarshal, reject anything but whitespace after the value
+	var extra json.RawMessage
+	if err := decoder.Decode(&extra); err != io.EOF {
+		return fmt.Errorf("jsonutil: unmarshal preserve numbers failed: unexpected data after top-level value")
+	}
+	return nil
+}
+
+// MarshalFromNumbers marshals a value produced by UnmarshalPreserveNumbers,
+// writing json.Number values verbatim so numeric precision is preserved.
+// It returns an error if any json.Number is not a valid JSON number literal.
+func MarshalFromNumbers(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("jsonutil: marshal from numbers failed: %w", err)
+	}
+	return data, nil
+}
+
 // EncodeWriter encodes the given value as JSON and writes it to the provided writer.
 func EncodeWriter(w io.Writer, v any, opts *EncoderOptions) error {
 	encoder := json.NewEncoder(w)
@@ -259,6 +259,48 @@ func TestUnmarshalWithOptions(t *testing.T) {
 	}
 }
 
+func TestPreserveNumbersRoundTrip(t *testing.T) {
+	input := `{"id":9007199254740993,"big":1234567890123456789,"ratio":0.1,"nested":{"ids":[9223372036854775807]}}`
+
+	var m map[string]any
+	tst.RequireNoError(t, jsonutil.UnmarshalPreserveNumbers([]byte(input), &m))
+
+	id, ok := m["big"].(json.Number)
+	tst.AssertTrue(t, ok, "Expected big to be json.Number")
+	tst.AssertEqual(t, id.String(), "1234567890123456789")
+
+	out, err := jsonutil.MarshalFromNumbers(m)
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, strings.Contains(string(out), `"big":1234567890123456789`), "19-digit integer should survive")
+	tst.AssertTrue(t, strings.Contains(string(out), `"id":9007199254740993`), "2^53+1 should survive")
+	tst.AssertTrue(t, strings.Contains(string(out), `9223372036854775807`), "nested max int64 should survive")
+
+	// Standard decoding loses precision, demonstrating the problem being solved
+	var lossy map[string]any
+	tst.RequireNoError(t, jsonutil.Unmarshal([]byte(input), &lossy))
+	lossyOut, err := jsonutil.Marshal(lossy)
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, strings.Contains(string(lossyOut), "9007199254740993"), "float64 decoding should lose precision")
+}
+
+func TestPreserveNumbersErrors(t *testing.T) {
+	var m map[string]any
+	err := jsonutil.UnmarshalPreserveNumbers([]byte(`{invalid`), &m)
+	tst.AssertErrorContains(t, err, "jsonutil: unmarshal preserve numbers failed")
+
+	err = jsonutil.UnmarshalPreserveNumbers([]byte(`{}`), nil)
+	tst.AssertErrorContains(t, err, "nil destination")
+
+	for _, input := range []string{`{"a":1}{"b":2}`, `{"a":1} garbage`, `{"a":1}]`} {
+		err = jsonutil.UnmarshalPreserveNumbers([]byte(input), &m)
+		tst.AssertErrorContains(t, err, "unexpected data after top-level value")
+	}
+	tst.RequireNoError(t, jsonutil.UnmarshalPreserveNumbers([]byte("{\"a\":1}\n\t "), &m))
+
+	_, err = jsonutil.MarshalFromNumbers(map[string]any{"bad": json.Number("12abc")})
+	tst.AssertErrorContains(t, err, "jsonutil: marshal from numbers failed")
+}
+
 func TestEncodeWriter(t *testing.T) {
 	input := testStruct{Name: "Alice", Age: 30, Active: true}
 
@@ -0,0 +1,172 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DefaultRedactMask is the replacement value used by RedactJSON when mask is empty.
+const DefaultRedactMask = "[REDACTED]"
+
+// RedactJSON returns a compact copy of the JSON document data with the values
+// of matching fields replaced by mask (a JSON string). Each path is a
+// dot-separated list of object keys matched case-insensitively against the end
+// of a field's key path, so "password" masks every password field at any depth
+// and "user.token" masks token fields directly inside a "user" object. Array
+// elements share the path of their array.
+//
+// Input is processed as a token stream, so truncated or otherwise invalid
+// documents produce the redacted output up to the point of failure together
+// with a non-nil error. Values of matching fields are never copied to the
+// output, even when they are cut off.
+func RedactJSON(data []byte, mask string, paths ...string) ([]byte, error) {
+	if mask == "" {
+		mask = DefaultRedactMask
+	}
+	r := &redactor{
+		dec:  json.NewDecoder(bytes.NewReader(data)),
+		mask: mask,
+	}
+	r.dec.UseNumber()
+	for _, p := range paths {
+		if p != "" {
+			r.paths = append(r.paths, strings.Split(strings.ToLower(p), "."))
+		}
+	}
+
+	if err := r.value(nil); err != nil {
+		return r.out.Bytes(), fmt.Errorf("jsonutil: redact failed: %w", err)
+	}
+	return r.out.Bytes(), nil
+}
+
+type redactor struct {
+	dec   *json.Decoder
+	out   bytes.Buffer
+	mask  string
+	paths [][]string
+}
+
+// value copies the next JSON value from the decoder to the output.
+func (r *redactor) value(path []string) error {
+	tok, err := r.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return r.writeJSON(tok)
+	}
+
+	switch delim {
+	case '{':
+		r.out.WriteByte('{')
+		for i := 0; r.dec.More(); i++ {
+			if i > 0 {
+				r.out.WriteByte(',')
+			}
+			keyTok, err := r.dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := keyTok.(string)
+			if err := r.writeJSON(key); err != nil {
+				return err
+			}
+			r.out.WriteByte(':')
+
+			child := append(path[:len(path):len(path)], strings.ToLower(key))
+			if r.matches(child) {
+				if err := r.skip(); err != nil {
+					return err
+				}
+				if err := r.writeJSON(r.mask); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := r.value(child); err != nil {
+				return err
+			}
+		}
+		if _, err := r.dec.Token(); err != nil {
+			return err
+		}
+		r.out.WriteByte('}')
+	case '[':
+		r.out.WriteByte('[')
+		for i := 0; r.dec.More(); i++ {
+			if i > 0 {
+				r.out.WriteByte(',')
+			}
+			if err := r.value(path); err != nil {
+				return err
+			}
+		}
+		if _, err := r.dec.Token(); err != nil {
+			return err
+		}
+		r.out.WriteByte(']')
+	}
+	return nil
+}
+
+// skip consumes the next JSON value without writing it.
+func (r *redactor) skip() error {
+	return skipValue(r.dec)
+}
+
+// skipValue consumes the next JSON value from dec without decoding it.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if delim, ok := tok.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// matches reports whether any redaction path matches the end of path.
+func (r *redactor) matches(path []string) bool {
+	for _, p := range r.paths {
+		if len(p) > len(path) {
+			continue
+		}
+		tail := path[len(path)-len(p):]
+		match := true
+		for i := range p {
+			if p[i] != tail[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *redactor) writeJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	r.out.Write(data)
+	return nil
+}
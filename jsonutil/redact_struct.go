@@ -0,0 +1,322 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RedactedValue replaces the values of `redact:"true"` and `secret:"true"` fields
+// in MarshalRedacted output.
+const RedactedValue = "***"
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// MarshalRedacted marshals v like json.Marshal, except that struct fields
+// tagged `redact:"true"` are emitted as RedactedValue. Fields tagged
+// `secret:"true"`, which config.Dump redacts, are treated the same, so one tag
+// covers both outputs. It is intended for logging structs that hold secrets;
+// v itself is never modified. Tagged fields are found at any depth through
+// pointers, slices, arrays, maps, and embedded structs, including unexported
+// ones whose fields encoding/json promotes. Values whose types implement
+// json.Marshaler or encoding.TextMarshaler are encoded as-is. Like
+// json.Marshal, it returns an error if v contains a cycle.
+func MarshalRedacted(v any) ([]byte, error) {
+	var r structRedactor
+	redacted, err := r.redactValue(reflect.ValueOf(v))
+	if err != nil {
+		return nil, fmt.Errorf("jsonutil: marshal redacted failed: %w", err)
+	}
+	data, err := json.Marshal(redacted)
+	if err != nil {
+		return nil, fmt.Errorf("jsonutil: marshal redacted failed: %w", err)
+	}
+	return data, nil
+}
+
+// redactedField is a single member of a redactedObject.
+type redactedField struct {
+	name  string
+	value any
+}
+
+// redactedObject encodes struct fields as a JSON object in declaration order.
+type redactedObject []redactedField
+
+func (o redactedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(f.name)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// visitKey identifies a pointer, map, or slice being redacted, for cycle
+// detection. Slices sharing a backing array differ by length.
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// structRedactor holds the values on the current path through the value being
+// redacted, so self-referential values are reported instead of recursing
+// forever.
+type structRedactor struct {
+	visiting map[visitKey]struct{}
+}
+
+// enter marks v as being visited and returns a function that unmarks it, or an
+// error if v is already on the current path.
+func (r *structRedactor) enter(v reflect.Value) (func(), error) {
+	key := visitKey{ptr: v.Pointer(), typ: v.Type()}
+	if v.Kind() == reflect.Slice {
+		key.len = v.Len()
+	}
+	if _, ok := r.visiting[key]; ok {
+		return nil, fmt.Errorf("encountered a cycle via %s", v.Type())
+	}
+	if r.visiting == nil {
+		r.visiting = make(map[visitKey]struct{})
+	}
+	r.visiting[key] = struct{}{}
+	return func() { delete(r.visiting, key) }, nil
+}
+
+// redactValue returns a value that encodes like v with tagged fields masked.
+func (r *structRedactor) redactValue(v reflect.Value) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if implementsMarshaler(v.Type()) {
+		return v.Interface(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return r.redactValue(v.Elem())
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+		leave, err := r.enter(v)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		return r.redactValue(v.Elem())
+	case reflect.Struct:
+		obj := redactedObject{}
+		if err := r.redactStruct(v, &obj); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface(), nil
+		}
+		leave, err := r.enter(v)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		return r.redactElems(v)
+	case reflect.Array:
+		return r.redactElems(v)
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		leave, err := r.enter(v)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+
+		out := reflect.MakeMapWithSize(reflect.MapOf(v.Type().Key(), reflect.TypeOf((*any)(nil)).Elem()), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			val, err := r.redactValue(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			if val == nil {
+				out.SetMapIndex(iter.Key(), reflect.Zero(out.Type().Elem()))
+				continue
+			}
+			out.SetMapIndex(iter.Key(), reflect.ValueOf(val))
+		}
+		return out.Interface(), nil
+	default:
+		return v.Interface(), nil
+	}
+}
+
+// redactElems redacts each element of the slice or array v.
+func (r *structRedactor) redactElems(v reflect.Value) (any, error) {
+	out := make([]any, v.Len())
+	for i := range out {
+		val, err := r.redactValue(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		out[i] = val
+	}
+	return out, nil
+}
+
+// redactStruct appends the JSON fields of struct v to obj, inlining the fields
+// of untagged embedded structs as encoding/json does, including the exported
+// fields of unexported embedded structs.
+func (r *structRedactor) redactStruct(v reflect.Value, obj *redactedObject) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() && (!sf.Anonymous || sf.Type.Kind() != reflect.Struct) {
+			// encoding/json ignores unexported fields other than embedded
+			// structs, including embedded pointers to unexported structs.
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fv := v.Field(i)
+
+		if name == "" && sf.Anonymous {
+			inlined, err := r.redactEmbedded(fv, obj)
+			if err != nil {
+				return err
+			}
+			if inlined {
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+		if hasTagOption(opts, "omitempty") && isEmptyValue(fv) {
+			continue
+		}
+
+		var value any
+		switch {
+		case isRedactedField(sf):
+			value = RedactedValue
+		case hasTagOption(opts, "string") && isQuotableKind(fv.Kind()):
+			encoded, _ := json.Marshal(fv.Interface())
+			value = string(encoded)
+		default:
+			val, err := r.redactValue(fv)
+			if err != nil {
+				return err
+			}
+			value = val
+		}
+		*obj = append(*obj, redactedField{name: name, value: value})
+	}
+	return nil
+}
+
+// redactEmbedded inlines the fields of the embedded struct or struct pointer
+// fv into obj and reports whether fv was handled. A nil pointer contributes no
+// fields.
+func (r *structRedactor) redactEmbedded(fv reflect.Value, obj *redactedObject) (bool, error) {
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			return true, nil
+		}
+		if fv.Elem().Kind() != reflect.Struct || implementsMarshaler(fv.Elem().Type()) {
+			return false, nil
+		}
+		leave, err := r.enter(fv)
+		if err != nil {
+			return true, err
+		}
+		defer leave()
+		fv = fv.Elem()
+	}
+	if fv.Kind() != reflect.Struct || implementsMarshaler(fv.Type()) {
+		return false, nil
+	}
+	return true, r.redactStruct(fv, obj)
+}
+
+// isRedactedField reports whether sf is tagged `redact:"true"` or `secret:"true"`.
+func isRedactedField(sf reflect.StructField) bool {
+	return sf.Tag.Get("redact") == "true" || sf.Tag.Get("secret") == "true"
+}
+
+func implementsMarshaler(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType)
+}
+
+func hasTagOption(opts, option string) bool {
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isQuotableKind reports whether the ",string" tag option applies to kind k.
+func isQuotableKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isEmptyValue mirrors the omitempty rules of encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package jsonutil_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/go-utils/jsonutil"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password" redact:"true"`
+}
+
+type account struct {
+	Credentials
+	ID       int               `json:"id"`
+	APIKey   *string           `json:"api_key,omitempty" redact:"true"`
+	Tokens   []Credentials     `json:"tokens"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Created  time.Time         `json:"created"`
+	Internal string            `json:"-"`
+}
+
+func TestMarshalRedacted(t *testing.T) {
+	c := Credentials{Username: "alice", Password: "hunter2"}
+
+	out, err := jsonutil.MarshalRedacted(c)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, string(out), `{"username":"alice","password":"***"}`)
+
+	// The original value is not modified
+	tst.AssertEqual(t, c.Password, "hunter2")
+}
+
+func TestMarshalRedacted_SecretTag(t *testing.T) {
+	// Fields tagged for config.Dump are redacted as well
+	cfg := struct {
+		User        string `json:"user"`
+		DatabaseURL string `json:"database_url" secret:"true"`
+	}{User: "admin", DatabaseURL: "postgres://admin:hunter2@db/app"}
+
+	out, err := jsonutil.MarshalRedacted(cfg)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, string(out), `{"user":"admin","database_url":"***"}`)
+}
+
+func TestMarshalRedacted_Nested(t *testing.T) {
+	key := "sk-live-123"
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	acct := &account{
+		Credentials: Credentials{Username: "bob", Password: "secret"},
+		ID:          7,
+		APIKey:      &key,
+		Tokens:      []Credentials{{Username: "svc", Password: "tok"}},
+		Created:     created,
+		Internal:    "hidden",
+	}
+
+	out, err := jsonutil.MarshalRedacted(acct)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, string(out),
+		`{"username":"bob","password":"***","id":7,"api_key":"***",`+
+			`"tokens":[{"username":"svc","password":"***"}],"created":"2024-01-02T03:04:05Z"}`)
+	tst.AssertEqual(t, key, "sk-live-123")
+
+	// Without tagged fields the output matches encoding/json
+	plain := map[string][]int{"a": {1, 2}, "b": nil}
+	got, err := jsonutil.MarshalRedacted(plain)
+	tst.RequireNoError(t, err)
+	want, _ := json.Marshal(plain)
+	tst.AssertEqual(t, string(got), string(want))
+}
+
+type login struct {
+	User   string `json:"user"`
+	Secret string `json:"secret" redact:"true"`
+}
+
+type session struct {
+	login
+	ID int `json:"id"`
+}
+
+func TestMarshalRedacted_UnexportedEmbedded(t *testing.T) {
+	// encoding/json promotes the exported fields of unexported embedded structs
+	s := session{login: login{User: "carol", Secret: "pw"}, ID: 3}
+
+	out, err := jsonutil.MarshalRedacted(s)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, string(out), `{"user":"carol","secret":"***","id":3}`)
+}
+
+type node struct {
+	Name string `json:"name"`
+	Next *node  `json:"next,omitempty"`
+}
+
+func TestMarshalRedacted_Cycle(t *testing.T) {
+	n := &node{Name: "a"}
+	n.Next = n
+
+	_, err := jsonutil.MarshalRedacted(n)
+	tst.AssertErrorContains(t, err, "cycle")
+
+	// Shared but acyclic pointers are not cycles
+	shared := &Credentials{Username: "x"}
+	out, err := jsonutil.MarshalRedacted([]*Credentials{shared, shared})
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, string(out), `[{"username":"x","password":"***"},{"username":"x","password":"***"}]`)
+}
@@ -0,0 +1,37 @@
+package jsonutil_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/go-utils/jsonutil"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestRedactJSON(t *testing.T) {
+	data := []byte(`{
+		"user": {"name": "alice", "Password": "hunter2", "token": {"value": "abc"}},
+		"items": [{"id": 1, "password": "x"}, {"id": 2}],
+		"token": "top-level",
+		"count": 12345678901234567890
+	}`)
+
+	out, err := jsonutil.RedactJSON(data, "", "password", "user.token")
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, string(out),
+		`{"user":{"name":"alice","Password":"[REDACTED]","token":"[REDACTED]"},`+
+			`"items":[{"id":1,"password":"[REDACTED]"},{"id":2}],`+
+			`"token":"top-level","count":12345678901234567890}`)
+}
+
+func TestRedactJSON_Truncated(t *testing.T) {
+	data := []byte(`{"name":"alice","password":"hunter2","bio":"long te`)
+
+	out, err := jsonutil.RedactJSON(data, "***", "password")
+	tst.AssertNotNil(t, err, "truncated input should return an error")
+	tst.AssertEqual(t, string(out), `{"name":"alice","password":"***","bio":`)
+
+	// A secret cut off mid-value is never emitted
+	out, _ = jsonutil.RedactJSON([]byte(`{"password":"hunt`), "", "password")
+	tst.AssertFalse(t, strings.Contains(string(out), "hunt"), "partial secret must not leak")
+}
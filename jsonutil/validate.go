@@ -0,0 +1,67 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// SyntaxError reports where a JSON document is malformed. It wraps the
+// *json.SyntaxError from encoding/json, so errors.As works with either type.
+type SyntaxError struct {
+	// Offset is the number of bytes read before the error, as in
+	// json.SyntaxError.Offset.
+	Offset int64
+	// Line and Column locate the offending character, both starting at 1.
+	// Columns count characters, not bytes. For input that ends too early they
+	// point just past the last character.
+	Line   int
+	Column int
+	Err    *json.SyntaxError
+}
+
+// Error returns the error message with its position.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("jsonutil: invalid JSON at line %d, column %d (offset %d): %s",
+		e.Line, e.Column, e.Offset, e.Err.Error())
+}
+
+// Unwrap returns the underlying *json.SyntaxError.
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateDetailed is like Valid but returns a *SyntaxError giving the line,
+// column, and byte offset of the first syntax error, or nil if data is valid
+// JSON. Use it to point users at the mistake in a malformed config file.
+func ValidateDetailed(data []byte) error {
+	var raw json.RawMessage
+	err := json.Unmarshal(data, &raw)
+	if err == nil {
+		return nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return fmt.Errorf("jsonutil: validate failed: %w", err)
+	}
+
+	// Offset counts the offending byte itself, except when the input ends
+	// early, where it is the input length
+	pos := syntaxErr.Offset - 1
+	if pos < 0 || syntaxErr.Error() == "unexpected end of JSON input" {
+		pos = syntaxErr.Offset
+	}
+	pos = min(pos, int64(len(data)))
+
+	before := data[:pos]
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	return &SyntaxError{
+		Offset: syntaxErr.Offset,
+		Line:   bytes.Count(before, []byte{'\n'}) + 1,
+		Column: utf8.RuneCount(before[lineStart:]) + 1,
+		Err:    syntaxErr,
+	}
+}
@@ -0,0 +1,48 @@
+package jsonutil_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/julianstephens/go-utils/jsonutil"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestValidateDetailed(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		line   int
+		column int
+	}{
+		{"trailing comma", "{\n  \"name\": \"app\",\n  \"port\": 8080,\n}", 4, 1},
+		{"missing colon", "{\n  \"name\" \"app\"\n}", 2, 10},
+		{"bad value", "[1,\n 2,\n x]", 3, 2},
+		{"multibyte characters before error", "{\"émoji\": \"✓\" x}", 1, 15},
+		{"unexpected end", "{\n  \"a\": [1, 2", 2, 13},
+		{"empty input", "", 1, 1},
+		{"trailing data", "{} {}", 1, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := jsonutil.ValidateDetailed([]byte(tt.data))
+			var syntaxErr *jsonutil.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("expected *jsonutil.SyntaxError, got %v", err)
+			}
+			tst.AssertEqual(t, syntaxErr.Line, tt.line)
+			tst.AssertEqual(t, syntaxErr.Column, tt.column)
+
+			var stdErr *json.SyntaxError
+			tst.AssertTrue(t, errors.As(err, &stdErr), "should unwrap to *json.SyntaxError")
+			tst.AssertEqual(t, syntaxErr.Offset, stdErr.Offset)
+		})
+	}
+
+	err := jsonutil.ValidateDetailed([]byte("{\n  \"port\": 8080,\n}"))
+	tst.AssertErrorContains(t, err, "line 3, column 1 (offset 19)")
+
+	tst.AssertNoError(t, jsonutil.ValidateDetailed([]byte(`{"name": "app", "tags": ["a", "b"]}`)))
+	tst.AssertNoError(t, jsonutil.ValidateDetailed([]byte("  42  ")))
+}
@@ -0,0 +1,49 @@
+package security
+
+// AESKey is an AES key whose length was validated when it was created, so it
+// cannot be mistaken for a key of another size or for arbitrary bytes. It is
+// an opt-in alternative to passing raw []byte keys to Encrypt and Decrypt.
+type AESKey struct {
+	key []byte
+}
+
+// NewAESKey generates a random AES key of size bytes (16, 24, or 32). It
+// returns ErrInvalidKeySize for any other size.
+func NewAESKey(size int) (*AESKey, error) {
+	key, err := GenerateAESKey(size)
+	if err != nil {
+		return nil, err
+	}
+	return &AESKey{key: key}, nil
+}
+
+// AESKeyFromBytes returns an AESKey holding a copy of b. It returns
+// ErrInvalidKeySize unless b is 16, 24, or 32 bytes long.
+func AESKeyFromBytes(b []byte) (*AESKey, error) {
+	if len(b) != 16 && len(b) != 24 && len(b) != 32 {
+		return nil, ErrInvalidKeySize
+	}
+	return &AESKey{key: append([]byte(nil), b...)}, nil
+}
+
+// Size returns the key length in bytes: 16, 24, or 32.
+func (k *AESKey) Size() int {
+	return len(k.key)
+}
+
+// Bytes returns a copy of the raw key, for storage or for the byte-slice
+// functions.
+func (k *AESKey) Bytes() []byte {
+	return append([]byte(nil), k.key...)
+}
+
+// Encrypt encrypts plaintext with AES-GCM under k, like Encrypt. Returns the
+// encrypted data with nonce prepended.
+func (k *AESKey) Encrypt(plaintext []byte) ([]byte, error) {
+	return Encrypt(k.key, plaintext)
+}
+
+// Decrypt decrypts ciphertext produced by Encrypt under k, like Decrypt.
+func (k *AESKey) Decrypt(ciphertext []byte) ([]byte, error) {
+	return Decrypt(k.key, ciphertext)
+}
@@ -0,0 +1,59 @@
+package security_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestNewAESKey(t *testing.T) {
+	for _, size := range []int{16, 24, 32} {
+		t.Run(fmt.Sprintf("Size%d", size), func(t *testing.T) {
+			key, err := security.NewAESKey(size)
+			tst.RequireNoError(t, err)
+			tst.AssertEqual(t, key.Size(), size)
+			tst.AssertEqual(t, len(key.Bytes()), size)
+
+			plaintext := []byte("typed keys travel with their cipher")
+			ciphertext, err := key.Encrypt(plaintext)
+			tst.RequireNoError(t, err)
+			decrypted, err := key.Decrypt(ciphertext)
+			tst.RequireNoError(t, err)
+			tst.AssertDeepEqual(t, decrypted, plaintext)
+
+			// Interoperates with the byte-slice functions
+			decrypted, err = security.Decrypt(key.Bytes(), ciphertext)
+			tst.RequireNoError(t, err)
+			tst.AssertDeepEqual(t, decrypted, plaintext)
+		})
+	}
+
+	_, err := security.NewAESKey(20)
+	tst.AssertErrorIs(t, err, security.ErrInvalidKeySize)
+}
+
+func TestAESKeyFromBytes(t *testing.T) {
+	raw := make([]byte, 32)
+	key, err := security.AESKeyFromBytes(raw)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, key.Size(), 32)
+
+	// The key keeps its own copy
+	raw[0] = 0xff
+	tst.AssertEqual(t, key.Bytes()[0], byte(0))
+	key.Bytes()[1] = 0xff
+	tst.AssertEqual(t, key.Bytes()[1], byte(0))
+
+	for _, size := range []int{0, 15, 31, 64} {
+		_, err := security.AESKeyFromBytes(make([]byte, size))
+		tst.AssertErrorIs(t, err, security.ErrInvalidKeySize)
+	}
+
+	other, _ := security.NewAESKey(32)
+	ciphertext, err := key.Encrypt([]byte("secret"))
+	tst.RequireNoError(t, err)
+	_, err = other.Decrypt(ciphertext)
+	tst.AssertErrorIs(t, err, security.ErrDecryptionFailed)
+}
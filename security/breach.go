@@ -0,0 +1,47 @@
+package security
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec // SHA-1 is mandated by the k-anonymity range protocol
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// BreachLookupFunc returns the hash suffixes known to share the given 5-character
+// uppercase SHA-1 hex prefix. Entries may be bare 35-character suffixes or use the
+// Have I Been Pwned range format "SUFFIX:COUNT".
+type BreachLookupFunc func(prefix string) ([]string, error)
+
+// IsBreached reports whether password appears in a breached-password corpus using
+// the Have I Been Pwned k-anonymity protocol. The password is hashed with SHA-1 and
+// only the first 5 hex characters are passed to lookup; the returned suffixes are
+// compared locally, so neither the password nor its full hash leaves the process.
+// lookup performs the network call (e.g. GET https://api.pwnedpasswords.com/range/{prefix}),
+// which keeps this package free of HTTP dependencies. ctx is checked before the
+// lookup is made.
+func IsBreached(ctx context.Context, password string, lookup BreachLookupFunc) (bool, error) {
+	if lookup == nil {
+		return false, fmt.Errorf("breach lookup function is nil")
+	}
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	sum := sha1.Sum([]byte(password)) //nolint:gosec // required by the range protocol
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	candidates, err := lookup(prefix)
+	if err != nil {
+		return false, fmt.Errorf("breach lookup failed: %w", err)
+	}
+
+	for _, candidate := range candidates {
+		candidate, _, _ = strings.Cut(strings.TrimSpace(candidate), ":")
+		if strings.EqualFold(candidate, suffix) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
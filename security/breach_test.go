@@ -0,0 +1,52 @@
+package security_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+// SHA-1("password") = 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+const passwordPrefix, passwordSuffix = "5BAA6", "1E4C9B93F3F0682250B6CF8331B7EE68FD8"
+
+func TestIsBreached(t *testing.T) {
+	ctx := context.Background()
+	var gotPrefix string
+	lookup := func(prefix string) ([]string, error) {
+		gotPrefix = prefix
+		return []string{
+			"0018A45C4D1DEF81644B54AB7F969B88D65:1",
+			passwordSuffix + ":9659365",
+		}, nil
+	}
+
+	breached, err := security.IsBreached(ctx, "password", lookup)
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, breached, "password should be reported as breached")
+	tst.AssertEqual(t, gotPrefix, passwordPrefix)
+
+	breached, err = security.IsBreached(ctx, "correct horse battery staple 42!", lookup)
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, breached, "unlisted password should not be reported as breached")
+}
+
+func TestIsBreached_Errors(t *testing.T) {
+	lookupErr := errors.New("network down")
+	_, err := security.IsBreached(context.Background(), "password", func(string) ([]string, error) {
+		return nil, lookupErr
+	})
+	tst.AssertErrorIs(t, err, lookupErr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	called := false
+	_, err = security.IsBreached(ctx, "password", func(string) ([]string, error) {
+		called = true
+		return nil, nil
+	})
+	tst.AssertErrorIs(t, err, context.Canceled)
+	tst.AssertFalse(t, called, "lookup should not run after cancellation")
+}
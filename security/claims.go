@@ -0,0 +1,79 @@
+package security
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrInvalidToken is returned when an encrypted token is malformed, has
+	// been tampered with, or was encrypted under a different key
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrTokenExpired is returned when an encrypted token is past its expiry
+	ErrTokenExpired = errors.New("token has expired")
+)
+
+// claimsEnvelope is the plaintext of an encrypted claims token.
+type claimsEnvelope struct {
+	// ExpiresAt is the expiry time in Unix milliseconds
+	ExpiresAt int64          `json:"exp"`
+	Claims    map[string]any `json:"claims"`
+}
+
+// EncryptClaims returns a confidential, stateless token carrying claims. The
+// claims and an expiry of now+ttl are serialized as JSON, encrypted with
+// AES-GCM under key, and base64url-encoded. Unlike a signed JWT, the claims
+// cannot be read without the key. key must be 16, 24, or 32 bytes.
+func EncryptClaims(key []byte, claims map[string]any, ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		return "", fmt.Errorf("token ttl must be positive, got %v", ttl)
+	}
+
+	data, err := json.Marshal(claimsEnvelope{
+		ExpiresAt: time.Now().Add(ttl).UnixMilli(),
+		Claims:    claims,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize claims: %w", err)
+	}
+
+	ciphertext, err := Encrypt(key, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt claims: %w", err)
+	}
+	return EncodeBase64URL(ciphertext), nil
+}
+
+// DecryptClaims decrypts a token created by EncryptClaims and returns its
+// claims. It returns ErrInvalidToken if the token cannot be decoded or
+// authenticated and ErrTokenExpired if it is past its expiry. Claim values
+// are decoded as by json.Unmarshal, so numbers are returned as float64.
+func DecryptClaims(key []byte, token string) (map[string]any, error) {
+	ciphertext, err := DecodeBase64URL(token)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	data, err := Decrypt(key, ciphertext)
+	if err != nil {
+		if errors.Is(err, ErrInvalidKeySize) {
+			return nil, err
+		}
+		return nil, ErrInvalidToken
+	}
+
+	var envelope claimsEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if time.Now().UnixMilli() >= envelope.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+
+	if envelope.Claims == nil {
+		envelope.Claims = map[string]any{}
+	}
+	return envelope.Claims, nil
+}
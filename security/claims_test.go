@@ -0,0 +1,84 @@
+package security_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestEncryptClaims_RoundTrip(t *testing.T) {
+	key, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+
+	token, err := security.EncryptClaims(key, map[string]any{
+		"sub":   "user-42",
+		"roles": []string{"admin", "billing"},
+		"level": 3,
+	}, time.Hour)
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, strings.Contains(token, "user-42"), "claims should not be readable in the token")
+
+	claims, err := security.DecryptClaims(key, token)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, claims, map[string]any{
+		"sub":   "user-42",
+		"roles": []any{"admin", "billing"},
+		"level": float64(3),
+	})
+}
+
+func TestDecryptClaims_Expired(t *testing.T) {
+	key, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+
+	token, err := security.EncryptClaims(key, map[string]any{"sub": "user-42"}, 10*time.Millisecond)
+	tst.RequireNoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = security.DecryptClaims(key, token)
+	tst.AssertErrorIs(t, err, security.ErrTokenExpired)
+}
+
+func TestDecryptClaims_Tampered(t *testing.T) {
+	key, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+	otherKey, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+
+	token, err := security.EncryptClaims(key, map[string]any{"sub": "user-42"}, time.Hour)
+	tst.RequireNoError(t, err)
+
+	raw, err := security.DecodeBase64URL(token)
+	tst.RequireNoError(t, err)
+	raw[len(raw)/2] ^= 0x01
+
+	tests := []struct {
+		name  string
+		key   []byte
+		token string
+	}{
+		{"modified ciphertext", key, security.EncodeBase64URL(raw)},
+		{"wrong key", otherKey, token},
+		{"not base64", key, "not a token!"},
+		{"truncated", key, security.EncodeBase64URL(raw[:8])},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := security.DecryptClaims(tt.key, tt.token)
+			tst.AssertErrorIs(t, err, security.ErrInvalidToken)
+		})
+	}
+}
+
+func TestEncryptClaims_InvalidArguments(t *testing.T) {
+	_, err := security.EncryptClaims([]byte("short"), map[string]any{}, time.Hour)
+	tst.AssertErrorIs(t, err, security.ErrInvalidKeySize)
+
+	key, err := security.GenerateRandomKey(16)
+	tst.RequireNoError(t, err)
+	_, err = security.EncryptClaims(key, map[string]any{}, 0)
+	tst.AssertErrorContains(t, err, "ttl must be positive")
+}
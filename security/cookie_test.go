@@ -0,0 +1,72 @@
+package security_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+type session struct {
+	UserID int    `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+func newCookieCodecs(t *testing.T) map[string]*security.CookieCodec {
+	t.Helper()
+	hashKey, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+	blockKey, err := security.GenerateAESKey(32)
+	tst.RequireNoError(t, err)
+
+	signed, err := security.NewCookieCodec(hashKey, nil)
+	tst.RequireNoError(t, err)
+	encrypted, err := security.NewCookieCodec(hashKey, blockKey)
+	tst.RequireNoError(t, err)
+	return map[string]*security.CookieCodec{"signed": signed, "encrypted": encrypted}
+}
+
+func TestCookieCodec_RoundTrip(t *testing.T) {
+	for name, codec := range newCookieCodecs(t) {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := codec.Encode("session", session{UserID: 42, Role: "admin"})
+			tst.RequireNoError(t, err)
+
+			var got session
+			tst.RequireNoError(t, codec.Decode("session", encoded, &got))
+			tst.AssertDeepEqual(t, got, session{UserID: 42, Role: "admin"})
+		})
+	}
+
+	// Encrypted cookies do not expose the plaintext
+	encoded, err := newCookieCodecs(t)["encrypted"].Encode("session", session{Role: "admin"})
+	tst.RequireNoError(t, err)
+	decoded, _ := security.DecodeBase64URL(strings.SplitN(encoded, ".", 2)[0])
+	tst.AssertFalse(t, strings.Contains(string(decoded), "admin"), "encrypted cookie should not contain plaintext")
+}
+
+func TestCookieCodec_Tampering(t *testing.T) {
+	for name, codec := range newCookieCodecs(t) {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := codec.Encode("session", session{UserID: 1, Role: "user"})
+			tst.RequireNoError(t, err)
+
+			var got session
+			tampered := []byte(encoded)
+			tampered[3] ^= 0x01
+			tst.AssertErrorIs(t, codec.Decode("session", string(tampered), &got), security.ErrInvalidCookie)
+
+			tst.AssertErrorIs(t, codec.Decode("other", encoded, &got), security.ErrInvalidCookie)
+			tst.AssertErrorIs(t, codec.Decode("session", "not-a-cookie", &got), security.ErrInvalidCookie)
+		})
+	}
+}
+
+func TestNewCookieCodec_InvalidKeys(t *testing.T) {
+	_, err := security.NewCookieCodec(nil, nil)
+	tst.AssertErrorIs(t, err, security.ErrEmptyKey)
+
+	_, err = security.NewCookieCodec([]byte("hash-key"), []byte("short"))
+	tst.AssertErrorIs(t, err, security.ErrInvalidKeySize)
+}
@@ -0,0 +1,101 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+// Ed25519 Signatures
+
+// GenerateEd25519KeyPair generates an Ed25519 key pair: a 32-byte public key
+// and a 64-byte private key.
+func GenerateEd25519KeyPair() (pub, priv []byte, err error) {
+	pubKey, privKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate Ed25519 key pair: %w", err)
+	}
+	return pubKey, privKey, nil
+}
+
+// Sign returns the Ed25519 signature of message under the 64-byte private key
+// priv. It returns nil if priv is not a valid private key; use SignEd25519 to
+// get an error instead.
+func Sign(priv, message []byte) []byte {
+	sig, err := SignEd25519(priv, message)
+	if err != nil {
+		return nil
+	}
+	return sig
+}
+
+// Verify reports whether sig is a valid Ed25519 signature of message for the
+// 32-byte public key pub. Malformed keys or signatures return false rather
+// than panicking.
+func Verify(pub, message, sig []byte) bool {
+	return VerifyEd25519(pub, message, sig)
+}
+
+// MarshalEd25519PublicKeyPEM encodes a 32-byte Ed25519 public key as a PKIX
+// "PUBLIC KEY" PEM block.
+func MarshalEd25519PublicKeyPEM(pub []byte) ([]byte, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKeySize
+	}
+	der, err := x509.MarshalPKIXPublicKey(ed25519.PublicKey(pub))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// ParseEd25519PublicKeyPEM parses a PKIX "PUBLIC KEY" PEM block holding an
+// Ed25519 public key and returns the raw 32-byte key.
+func ParseEd25519PublicKeyPEM(data []byte) ([]byte, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, ErrInvalidPEM
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: public key is %T, not Ed25519", ErrInvalidPEM, key)
+	}
+	return pub, nil
+}
+
+// MarshalEd25519PrivateKeyPEM encodes a 64-byte Ed25519 private key as an
+// unencrypted PKCS #8 "PRIVATE KEY" PEM block.
+func MarshalEd25519PrivateKeyPEM(priv []byte) ([]byte, error) {
+	if len(priv) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidKeySize
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(ed25519.PrivateKey(priv))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// ParseEd25519PrivateKeyPEM parses an unencrypted PKCS #8 "PRIVATE KEY" PEM
+// block holding an Ed25519 private key and returns the raw 64-byte key.
+func ParseEd25519PrivateKeyPEM(data []byte) ([]byte, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, ErrInvalidPEM
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: private key is %T, not Ed25519", ErrInvalidPEM, key)
+	}
+	return priv, nil
+}
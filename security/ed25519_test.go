@@ -0,0 +1,74 @@
+package security_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestSignVerify(t *testing.T) {
+	pub, priv, err := security.GenerateEd25519KeyPair()
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, len(pub), 32)
+	tst.AssertEqual(t, len(priv), 64)
+	message := []byte("release-1.4.0.tar.gz contents")
+
+	sig := security.Sign(priv, message)
+	tst.AssertEqual(t, len(sig), 64)
+	tst.AssertTrue(t, security.Verify(pub, message, sig), "valid signature should verify")
+	tst.AssertFalse(t, security.Verify(pub, []byte("release-1.4.1.tar.gz contents"), sig), "tampered message should not verify")
+
+	otherPub, _, err := security.GenerateEd25519KeyPair()
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, security.Verify(otherPub, message, sig), "wrong public key should not verify")
+
+	// Malformed inputs return false or nil instead of panicking
+	tst.AssertFalse(t, security.Verify(nil, message, sig), "nil public key")
+	tst.AssertFalse(t, security.Verify(pub[:31], message, sig), "short public key")
+	tst.AssertFalse(t, security.Verify(pub, message, nil), "nil signature")
+	tst.AssertFalse(t, security.Verify(pub, message, append(sig, 0)), "long signature")
+	tst.AssertNil(t, security.Sign(priv[:32], message), "short private key should not sign")
+}
+
+func TestEd25519KeysPEM(t *testing.T) {
+	pub, priv, err := security.GenerateEd25519KeyPair()
+	tst.RequireNoError(t, err)
+
+	pubPEM, err := security.MarshalEd25519PublicKeyPEM(pub)
+	tst.RequireNoError(t, err)
+	parsedPub, err := security.ParseEd25519PublicKeyPEM(pubPEM)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, parsedPub, pub)
+
+	privPEM, err := security.MarshalEd25519PrivateKeyPEM(priv)
+	tst.RequireNoError(t, err)
+	parsedPriv, err := security.ParseEd25519PrivateKeyPEM(privPEM)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, parsedPriv, priv)
+
+	message := []byte("payload")
+	tst.AssertTrue(t, security.Verify(parsedPub, message, security.Sign(parsedPriv, message)), "round-tripped keys should work")
+
+	_, err = security.MarshalEd25519PublicKeyPEM(pub[:10])
+	tst.AssertErrorIs(t, err, security.ErrInvalidKeySize)
+	_, err = security.MarshalEd25519PrivateKeyPEM(priv[:10])
+	tst.AssertErrorIs(t, err, security.ErrInvalidKeySize)
+
+	_, err = security.ParseEd25519PublicKeyPEM([]byte("not pem"))
+	tst.AssertErrorIs(t, err, security.ErrInvalidPEM)
+	_, err = security.ParseEd25519PrivateKeyPEM(pubPEM)
+	tst.AssertErrorIs(t, err, security.ErrInvalidPEM)
+
+	// RSA keys in the same PEM types are rejected
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	tst.RequireNoError(t, err)
+	rsaPub, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	tst.RequireNoError(t, err)
+	_, err = security.ParseEd25519PublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: rsaPub}))
+	tst.AssertErrorIs(t, err, security.ErrInvalidPEM)
+}
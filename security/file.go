@@ -0,0 +1,95 @@
+package security
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/julianstephens/go-utils/helpers"
+)
+
+// EncryptFile encrypts the contents of srcPath in the streaming format of
+// NewEncryptingWriter and atomically writes the result to dstPath. The
+// destination keeps the source file's permission bits. srcPath and dstPath may
+// be the same file to encrypt in place. The file is processed in frames, so
+// memory use does not grow with its size.
+func EncryptFile(key []byte, srcPath, dstPath string) error {
+	return transformFile(srcPath, dstPath, func(dst io.Writer, src io.Reader) error {
+		w, err := NewEncryptingWriter(dst, key)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(w, src); err != nil {
+			return err
+		}
+		return w.Close()
+	})
+}
+
+// DecryptFile reverses EncryptFile, atomically writing the plaintext of srcPath
+// to dstPath with the source file's permission bits. Nothing is written to
+// dstPath unless the whole file authenticates.
+func DecryptFile(key []byte, srcPath, dstPath string) error {
+	return transformFile(srcPath, dstPath, func(dst io.Writer, src io.Reader) error {
+		r, err := NewDecryptingReader(src, key)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(dst, r)
+		return err
+	})
+}
+
+// transformFile streams srcPath through fn into a temporary file next to
+// dstPath, then renames it into place with the permissions of srcPath. The
+// temporary file is removed if anything fails.
+func transformFile(srcPath, dstPath string, fn func(dst io.Writer, src io.Reader) error) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to open source file: %w", err)
+	}
+	defer func() { _ = src.Close() }()
+
+	info, err := src.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat source file: %w", err)
+	}
+
+	dir := filepath.Dir(dstPath)
+	tmpFile, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	shouldCleanup := true
+	defer func() {
+		if shouldCleanup {
+			_ = tmpFile.Close()
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	if err := fn(tmpFile, src); err != nil {
+		return err
+	}
+	if err := tmpFile.Chmod(info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to set file permissions: %w", err)
+	}
+	if err := helpers.SafeFileSync(tmpFile); err != nil {
+		return fmt.Errorf("failed to sync temp file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, dstPath); err != nil {
+		return fmt.Errorf("failed to write destination file: %w", err)
+	}
+	shouldCleanup = false
+
+	if err := helpers.SafeDirSync(dir); err != nil {
+		return fmt.Errorf("failed to sync directory: %w", err)
+	}
+	return nil
+}
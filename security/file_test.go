@@ -0,0 +1,106 @@
+package security_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestEncryptDecryptFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "config.yaml")
+	enc := filepath.Join(dir, "config.yaml.enc")
+	dec := filepath.Join(dir, "config.yaml.dec")
+
+	content := []byte("db_password: hunter2\n")
+	tst.RequireNoError(t, os.WriteFile(src, content, 0o640))
+
+	key, err := security.GenerateAESKey(32)
+	tst.RequireNoError(t, err)
+
+	tst.RequireNoError(t, security.EncryptFile(key, src, enc))
+	encrypted, err := os.ReadFile(enc)
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, bytes.Contains(encrypted, []byte("hunter2")), "encrypted file should not contain plaintext")
+
+	tst.RequireNoError(t, security.DecryptFile(key, enc, dec))
+	decrypted, err := os.ReadFile(dec)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, decrypted, content)
+
+	for _, path := range []string{enc, dec} {
+		info, err := os.Stat(path)
+		tst.RequireNoError(t, err)
+		tst.AssertEqual(t, info.Mode().Perm(), os.FileMode(0o640))
+	}
+}
+
+func TestEncryptFile_InPlace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret.txt")
+	content := []byte("top secret")
+	tst.RequireNoError(t, os.WriteFile(path, content, 0o600))
+
+	key, err := security.GenerateAESKey(32)
+	tst.RequireNoError(t, err)
+
+	tst.RequireNoError(t, security.EncryptFile(key, path, path))
+	tst.RequireNoError(t, security.DecryptFile(key, path, path))
+
+	got, err := os.ReadFile(path)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, got, content)
+}
+
+func TestDecryptFile_WrongKey(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "plain")
+	enc := filepath.Join(dir, "enc")
+	tst.RequireNoError(t, os.WriteFile(src, []byte("data"), 0o600))
+
+	key, _ := security.GenerateAESKey(32)
+	other, _ := security.GenerateAESKey(32)
+	tst.RequireNoError(t, security.EncryptFile(key, src, enc))
+
+	err := security.DecryptFile(other, enc, filepath.Join(dir, "out"))
+	tst.AssertErrorIs(t, err, security.ErrDecryptionFailed)
+	_, statErr := os.Stat(filepath.Join(dir, "out"))
+	tst.AssertTrue(t, os.IsNotExist(statErr), "no output should be written on failure")
+	temps, _ := filepath.Glob(filepath.Join(dir, ".tmp-*"))
+	tst.AssertEqual(t, len(temps), 0)
+
+	err = security.EncryptFile(key, filepath.Join(dir, "missing"), enc)
+	tst.AssertErrorContains(t, err, "failed to open source file")
+}
+
+func TestEncryptFile_MultipleFrames(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "backup.tar")
+	content := bytes.Repeat([]byte("0123456789abcdef"), 3*security.StreamFrameSize/16+100)
+	tst.RequireNoError(t, os.WriteFile(src, content, 0o600))
+
+	key, err := security.GenerateAESKey(32)
+	tst.RequireNoError(t, err)
+
+	tst.RequireNoError(t, security.EncryptFile(key, src, src+".enc"))
+	encrypted, err := os.ReadFile(src + ".enc")
+	tst.RequireNoError(t, err)
+
+	// The file uses the streaming format, so it decrypts with the stream reader
+	r, err := security.NewDecryptingReader(bytes.NewReader(encrypted), key)
+	tst.RequireNoError(t, err)
+	plaintext, err := io.ReadAll(r)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, plaintext, content)
+
+	// A truncated file is rejected without writing the destination
+	tst.RequireNoError(t, os.WriteFile(src+".enc", encrypted[:len(encrypted)-1], 0o600))
+	err = security.DecryptFile(key, src+".enc", src+".out")
+	tst.AssertErrorIs(t, err, security.ErrDecryptionFailed)
+	_, statErr := os.Stat(src + ".out")
+	tst.AssertTrue(t, os.IsNotExist(statErr), "no output should be written on failure")
+}
@@ -0,0 +1,212 @@
+package security
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// AES-GCM-SIV Encryption/Decryption
+//
+// AES-GCM-SIV (RFC 8452) is a nonce misuse-resistant AEAD. Plain AES-GCM
+// leaks the XOR of plaintexts and allows forgeries once a nonce repeats under
+// the same key; with AES-GCM-SIV a repeated nonce only reveals whether the
+// same plaintext and aad were encrypted twice. The tradeoffs are that
+// encryption needs two passes over the plaintext, so it cannot be streamed,
+// and this pure Go implementation is considerably slower than hardware AES-GCM.
+// Prefer Encrypt for bulk data and EncryptGCMSIV where nonces may repeat, such
+// as very high message volumes under one key or a weak random source.
+
+const (
+	gcmSIVNonceSize = 12
+	gcmSIVTagSize   = 16
+)
+
+// EncryptGCMSIV encrypts plaintext using AES-GCM-SIV with the provided 16 or
+// 32-byte key, authenticating aad alongside it. Returns the encrypted data
+// with nonce prepended. aad may be nil and must be passed unchanged to
+// DecryptGCMSIV.
+func EncryptGCMSIV(key, plaintext, aad []byte) ([]byte, error) {
+	if len(key) != 16 && len(key) != 32 {
+		return nil, ErrInvalidKeySize
+	}
+
+	nonce := make([]byte, gcmSIVNonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return sealGCMSIV(key, nonce, plaintext, aad)
+}
+
+// DecryptGCMSIV decrypts ciphertext produced by EncryptGCMSIV. It returns
+// ErrInvalidCiphertext if ciphertext is too short and ErrDecryptionFailed if
+// it has been tampered with, aad differs, or the key is wrong.
+func DecryptGCMSIV(key, ciphertext, aad []byte) ([]byte, error) {
+	if len(key) != 16 && len(key) != 32 {
+		return nil, ErrInvalidKeySize
+	}
+	if len(ciphertext) < gcmSIVNonceSize+gcmSIVTagSize {
+		return nil, ErrInvalidCiphertext
+	}
+
+	nonce, sealed := ciphertext[:gcmSIVNonceSize], ciphertext[gcmSIVNonceSize:]
+	encKey, authKey, err := gcmSIVKeys(key, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := sealed[len(sealed)-gcmSIVTagSize:]
+	plaintext := make([]byte, len(sealed)-gcmSIVTagSize)
+	gcmSIVCTR(encKey, tag, plaintext, sealed[:len(plaintext)])
+
+	expected := gcmSIVTag(encKey, authKey, nonce, plaintext, aad)
+	if subtle.ConstantTimeCompare(tag, expected) != 1 {
+		return nil, ErrDecryptionFailed
+	}
+	return plaintext, nil
+}
+
+// sealGCMSIV encrypts plaintext under key and nonce and returns nonce,
+// ciphertext, and tag concatenated.
+func sealGCMSIV(key, nonce, plaintext, aad []byte) ([]byte, error) {
+	encKey, authKey, err := gcmSIVKeys(key, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := gcmSIVTag(encKey, authKey, nonce, plaintext, aad)
+	out := make([]byte, gcmSIVNonceSize+len(plaintext)+gcmSIVTagSize)
+	copy(out, nonce)
+	gcmSIVCTR(encKey, tag, out[gcmSIVNonceSize:gcmSIVNonceSize+len(plaintext)], plaintext)
+	copy(out[gcmSIVNonceSize+len(plaintext):], tag)
+	return out, nil
+}
+
+// gcmSIVKeys derives the per-nonce encryption block and POLYVAL key from the
+// key-generating key.
+func gcmSIVKeys(key, nonce []byte) (cipher.Block, []byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	blocks := 4
+	if len(key) == 32 {
+		blocks = 6
+	}
+	derived := make([]byte, 0, blocks*8)
+	var in, out [aes.BlockSize]byte
+	copy(in[4:], nonce)
+	for i := range blocks {
+		binary.LittleEndian.PutUint32(in[:4], uint32(i))
+		block.Encrypt(out[:], in[:])
+		derived = append(derived, out[:8]...)
+	}
+
+	encBlock, err := aes.NewCipher(derived[16:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return encBlock, derived[:16], nil
+}
+
+// gcmSIVTag computes the authentication tag over aad and plaintext.
+func gcmSIVTag(encKey cipher.Block, authKey, nonce, plaintext, aad []byte) []byte {
+	var p polyval
+	p.init(authKey)
+	p.update(aad)
+	p.update(plaintext)
+
+	var lengths [16]byte
+	binary.LittleEndian.PutUint64(lengths[:8], uint64(len(aad))*8)
+	binary.LittleEndian.PutUint64(lengths[8:], uint64(len(plaintext))*8)
+	p.update(lengths[:])
+
+	s := p.sum()
+	subtle.XORBytes(s[:gcmSIVNonceSize], s[:gcmSIVNonceSize], nonce)
+	s[15] &= 0x7f
+
+	tag := make([]byte, gcmSIVTagSize)
+	encKey.Encrypt(tag, s[:])
+	return tag
+}
+
+// gcmSIVCTR XORs src with the AES-CTR keystream whose initial counter block
+// is the tag with its top bit set, writing the result to dst.
+func gcmSIVCTR(encKey cipher.Block, tag, dst, src []byte) {
+	var counter, keystream [aes.BlockSize]byte
+	copy(counter[:], tag)
+	counter[15] |= 0x80
+
+	for len(src) > 0 {
+		encKey.Encrypt(keystream[:], counter[:])
+		n := subtle.XORBytes(dst, src, keystream[:])
+		dst, src = dst[n:], src[n:]
+		binary.LittleEndian.PutUint32(counter[:4], binary.LittleEndian.Uint32(counter[:4])+1)
+	}
+}
+
+// polyval computes the POLYVAL universal hash from RFC 8452. Elements of
+// GF(2^128) are stored little-endian, so bit i of lo|hi<<64 is the
+// coefficient of x^i.
+type polyval struct {
+	hLo, hHi uint64
+	sLo, sHi uint64
+}
+
+func (p *polyval) init(key []byte) {
+	p.hLo = binary.LittleEndian.Uint64(key[:8])
+	p.hHi = binary.LittleEndian.Uint64(key[8:])
+	p.sLo, p.sHi = 0, 0
+}
+
+// update absorbs data, zero-padding the final partial block.
+func (p *polyval) update(data []byte) {
+	var block [16]byte
+	for len(data) > 0 {
+		n := copy(block[:], data)
+		clear(block[n:])
+		data = data[n:]
+
+		p.sLo ^= binary.LittleEndian.Uint64(block[:8])
+		p.sHi ^= binary.LittleEndian.Uint64(block[8:])
+		p.sLo, p.sHi = polyvalDot(p.sLo, p.sHi, p.hLo, p.hHi)
+	}
+}
+
+func (p *polyval) sum() [16]byte {
+	var out [16]byte
+	binary.LittleEndian.PutUint64(out[:8], p.sLo)
+	binary.LittleEndian.PutUint64(out[8:], p.sHi)
+	return out
+}
+
+// polyvalDot returns a*b*x^-128 reduced modulo x^128 + x^127 + x^126 +
+// x^121 + 1. Each bit of b adds a into the accumulator, which is then
+// multiplied by x^-1, so bit i ends up scaled by x^(i-128). The loop runs in
+// constant time.
+func polyvalDot(aLo, aHi, bLo, bHi uint64) (uint64, uint64) {
+	var zLo, zHi uint64
+	for i := range 128 {
+		var bit uint64
+		if i < 64 {
+			bit = (bLo >> i) & 1
+		} else {
+			bit = (bHi >> (i - 64)) & 1
+		}
+		mask := -bit
+		zLo ^= aLo & mask
+		zHi ^= aHi & mask
+
+		// x^-1 = x^127 + x^126 + x^125 + x^120
+		carry := -(zLo & 1)
+		zLo = zLo>>1 | zHi<<63
+		zHi = zHi>>1 ^ (0xe100000000000000 & carry)
+	}
+	return zLo, zHi
+}
@@ -0,0 +1,150 @@
+package security
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex %q: %v", s, err)
+	}
+	return b
+}
+
+func TestPolyvalRFC8452(t *testing.T) {
+	var p polyval
+	p.init(mustHex(t, "25629347589242761d31f826ba4b757b"))
+	p.update(mustHex(t, "4f4f95668c83dfb6401762bb2d01a262d1a24ddd2721d006bbe45f20d3c9f362"))
+	sum := p.sum()
+	if want := mustHex(t, "f7a3b47b846119fae5b7866cf5e5b77e"); !bytes.Equal(sum[:], want) {
+		t.Errorf("POLYVAL = %x, want %x", sum, want)
+	}
+}
+
+func TestSealGCMSIVRFC8452(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       string
+		nonce     string
+		plaintext string
+		aad       string
+		want      string
+	}{
+		{
+			name:  "AES-128 empty",
+			key:   "01000000000000000000000000000000",
+			nonce: "030000000000000000000000",
+			want:  "dc20e2d83f25705bb49e439eca56de25",
+		},
+		{
+			name:      "AES-128 8 bytes",
+			key:       "01000000000000000000000000000000",
+			nonce:     "030000000000000000000000",
+			plaintext: "0100000000000000",
+			want:      "b5d839330ac7b786578782fff6013b815b287c22493a364c",
+		},
+		{
+			name:  "AES-256 empty",
+			key:   "0100000000000000000000000000000000000000000000000000000000000000",
+			nonce: "030000000000000000000000",
+			want:  "07f5f4169bbf55a8400cd47ea6fd400f",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nonce := mustHex(t, tt.nonce)
+			got, err := sealGCMSIV(mustHex(t, tt.key), nonce, mustHex(t, tt.plaintext), mustHex(t, tt.aad))
+			if err != nil {
+				t.Fatalf("sealGCMSIV failed: %v", err)
+			}
+			if want := append(nonce, mustHex(t, tt.want)...); !bytes.Equal(got, want) {
+				t.Errorf("sealGCMSIV = %x, want %x", got, want)
+			}
+		})
+	}
+}
+
+func TestGCMSIVRoundTrip(t *testing.T) {
+	for _, size := range []int{16, 32} {
+		key, err := GenerateRandomKey(size)
+		if err != nil {
+			t.Fatalf("GenerateRandomKey failed: %v", err)
+		}
+		for _, plaintext := range [][]byte{nil, []byte("x"), bytes.Repeat([]byte("gcm-siv "), 100)} {
+			aad := []byte("record-42")
+			ciphertext, err := EncryptGCMSIV(key, plaintext, aad)
+			if err != nil {
+				t.Fatalf("EncryptGCMSIV failed: %v", err)
+			}
+			decrypted, err := DecryptGCMSIV(key, ciphertext, aad)
+			if err != nil {
+				t.Fatalf("DecryptGCMSIV failed: %v", err)
+			}
+			if !bytes.Equal(decrypted, plaintext) {
+				t.Errorf("round trip mismatch for %d-byte key: got %q", size, decrypted)
+			}
+		}
+	}
+}
+
+func TestGCMSIVNonceReuse(t *testing.T) {
+	key, _ := GenerateRandomKey(32)
+	nonce := bytes.Repeat([]byte{7}, gcmSIVNonceSize)
+
+	first, err := sealGCMSIV(key, nonce, []byte("attack at dawn"), nil)
+	if err != nil {
+		t.Fatalf("sealGCMSIV failed: %v", err)
+	}
+	second, _ := sealGCMSIV(key, nonce, []byte("attack at dawn"), nil)
+	other, _ := sealGCMSIV(key, nonce, []byte("attack at dusk"), nil)
+
+	// A repeated nonce reveals only that identical messages were encrypted
+	if !bytes.Equal(first, second) {
+		t.Error("identical inputs under a repeated nonce should produce identical ciphertexts")
+	}
+	if bytes.Equal(first[gcmSIVNonceSize:gcmSIVNonceSize+14], other[gcmSIVNonceSize:gcmSIVNonceSize+14]) {
+		t.Error("different plaintexts under a repeated nonce should not share a keystream")
+	}
+
+	for _, ct := range [][]byte{first, other} {
+		if _, err := DecryptGCMSIV(key, ct, nil); err != nil {
+			t.Errorf("ciphertext under a repeated nonce should still authenticate: %v", err)
+		}
+	}
+
+	// XOR-ing the ciphertexts, which breaks plain GCM under nonce reuse,
+	// yields a forgery that fails authentication
+	forged := append([]byte(nil), first...)
+	for i := gcmSIVNonceSize; i < gcmSIVNonceSize+14; i++ {
+		forged[i] ^= first[i] ^ other[i]
+	}
+	if _, err := DecryptGCMSIV(key, forged, nil); !errors.Is(err, ErrDecryptionFailed) {
+		t.Errorf("forged ciphertext should fail with ErrDecryptionFailed, got %v", err)
+	}
+}
+
+func TestGCMSIVErrors(t *testing.T) {
+	key, _ := GenerateRandomKey(16)
+	ciphertext, _ := EncryptGCMSIV(key, []byte("secret"), []byte("aad"))
+
+	if _, err := EncryptGCMSIV(make([]byte, 24), nil, nil); !errors.Is(err, ErrInvalidKeySize) {
+		t.Errorf("24-byte key should be rejected, got %v", err)
+	}
+	if _, err := DecryptGCMSIV(key, ciphertext[:10], nil); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Errorf("short ciphertext should fail with ErrInvalidCiphertext, got %v", err)
+	}
+	if _, err := DecryptGCMSIV(key, ciphertext, []byte("other")); !errors.Is(err, ErrDecryptionFailed) {
+		t.Errorf("wrong aad should fail with ErrDecryptionFailed, got %v", err)
+	}
+
+	tampered := append([]byte(nil), ciphertext...)
+	tampered[gcmSIVNonceSize] ^= 1
+	if _, err := DecryptGCMSIV(key, tampered, []byte("aad")); !errors.Is(err, ErrDecryptionFailed) {
+		t.Errorf("tampered ciphertext should fail with ErrDecryptionFailed, got %v", err)
+	}
+}
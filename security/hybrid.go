@@ -0,0 +1,132 @@
+package security
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Hybrid Public-Key Stream Encryption
+//
+// SealStreamTo encrypts a stream to an X25519 public key. It generates an
+// ephemeral key pair, derives a 32-byte AES key from the shared secret with
+// HKDF-SHA256 (salted with both public keys), and writes the ephemeral public
+// key followed by the body encrypted with NewEncryptingWriter.
+
+// x25519KeySize is the size of X25519 public and private keys.
+const x25519KeySize = 32
+
+// sealStreamInfo is the HKDF info string for SealStreamTo stream keys.
+const sealStreamInfo = "go-utils/seal-stream"
+
+// GenerateX25519KeyPair generates an X25519 key pair for SealStreamTo and
+// OpenStreamFrom. Both keys are 32 bytes.
+func GenerateX25519KeyPair() (pub, priv []byte, err error) {
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate X25519 key pair: %w", err)
+	}
+	return key.PublicKey().Bytes(), key.Bytes(), nil
+}
+
+// SealStreamTo encrypts everything read from src so that only the holder of
+// the private key for the 32-byte X25519 recipientPub can decrypt it, writing
+// the result to dst. The body is encrypted in frames, so arbitrarily large
+// streams use constant memory. It returns ErrInvalidKeySize if recipientPub is
+// not a valid X25519 public key.
+func SealStreamTo(recipientPub []byte, dst io.Writer, src io.Reader) error {
+	if len(recipientPub) != x25519KeySize {
+		return ErrInvalidKeySize
+	}
+	recipient, err := ecdh.X25519().NewPublicKey(recipientPub)
+	if err != nil {
+		return ErrInvalidKeySize
+	}
+
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return fmt.Errorf("failed to generate ephemeral key: %w", err)
+	}
+	shared, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return ErrInvalidKeySize
+	}
+	ephemeralPub := ephemeral.PublicKey().Bytes()
+	key, err := sealStreamKey(shared, ephemeralPub, recipientPub)
+	if err != nil {
+		return err
+	}
+
+	if _, err := dst.Write(ephemeralPub); err != nil {
+		return fmt.Errorf("failed to write stream header: %w", err)
+	}
+	w, err := NewEncryptingWriter(dst, key)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, src); err != nil {
+		return fmt.Errorf("failed to encrypt stream: %w", err)
+	}
+	return w.Close()
+}
+
+// OpenStreamFrom decrypts a stream produced by SealStreamTo using the 32-byte
+// X25519 recipientPriv, writing the plaintext to dst. It returns
+// ErrInvalidCiphertext if the header is missing or malformed and
+// ErrDecryptionFailed if the stream was encrypted to a different key or has
+// been tampered with or truncated. Frames are verified as they are read, so on
+// error dst may already hold a prefix of the plaintext; that prefix is
+// authentic, but the caller should discard it.
+func OpenStreamFrom(recipientPriv []byte, dst io.Writer, src io.Reader) error {
+	if len(recipientPriv) != x25519KeySize {
+		return ErrInvalidKeySize
+	}
+	recipient, err := ecdh.X25519().NewPrivateKey(recipientPriv)
+	if err != nil {
+		return ErrInvalidKeySize
+	}
+
+	ephemeralPub := make([]byte, x25519KeySize)
+	if _, err := io.ReadFull(src, ephemeralPub); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return ErrInvalidCiphertext
+		}
+		return fmt.Errorf("failed to read stream header: %w", err)
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(ephemeralPub)
+	if err != nil {
+		return ErrInvalidCiphertext
+	}
+	shared, err := recipient.ECDH(ephemeral)
+	if err != nil {
+		// Low-order points yield an all-zero shared secret
+		return ErrInvalidCiphertext
+	}
+	key, err := sealStreamKey(shared, ephemeralPub, recipient.PublicKey().Bytes())
+	if err != nil {
+		return err
+	}
+
+	r, err := NewDecryptingReader(src, key)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, r); err != nil {
+		if errors.Is(err, ErrDecryptionFailed) {
+			return err
+		}
+		return fmt.Errorf("failed to decrypt stream: %w", err)
+	}
+	return nil
+}
+
+// sealStreamKey derives the stream key from an X25519 shared secret, binding
+// it to both public keys.
+func sealStreamKey(shared, ephemeralPub, recipientPub []byte) ([]byte, error) {
+	salt := make([]byte, 0, 2*x25519KeySize)
+	salt = append(salt, ephemeralPub...)
+	salt = append(salt, recipientPub...)
+	return DeriveKeyHKDF(shared, string(salt), sealStreamInfo, 32)
+}
@@ -0,0 +1,69 @@
+package security_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestSealStreamTo_RoundTrip(t *testing.T) {
+	pub, priv, err := security.GenerateX25519KeyPair()
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, len(pub), 32)
+	tst.AssertEqual(t, len(priv), 32)
+
+	for _, size := range []int{0, 1, 3<<20 + 17} {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		tst.RequireNoError(t, err)
+
+		var sealed bytes.Buffer
+		tst.RequireNoError(t, security.SealStreamTo(pub, &sealed, bytes.NewReader(data)))
+
+		var opened bytes.Buffer
+		tst.RequireNoError(t, security.OpenStreamFrom(priv, &opened, bytes.NewReader(sealed.Bytes())))
+		tst.AssertTrue(t, bytes.Equal(opened.Bytes(), data), "opened stream should match the original")
+	}
+}
+
+func TestSealStreamTo_Errors(t *testing.T) {
+	pub, priv, err := security.GenerateX25519KeyPair()
+	tst.RequireNoError(t, err)
+	_, otherPriv, err := security.GenerateX25519KeyPair()
+	tst.RequireNoError(t, err)
+
+	var sealed bytes.Buffer
+	tst.RequireNoError(t, security.SealStreamTo(pub, &sealed, bytes.NewReader([]byte("attack at dawn"))))
+	ciphertext := sealed.Bytes()
+
+	var out bytes.Buffer
+	err = security.OpenStreamFrom(otherPriv, &out, bytes.NewReader(ciphertext))
+	tst.AssertErrorIs(t, err, security.ErrDecryptionFailed)
+
+	tampered := append([]byte(nil), ciphertext...)
+	tampered[len(tampered)-1] ^= 0x01
+	err = security.OpenStreamFrom(priv, &out, bytes.NewReader(tampered))
+	tst.AssertErrorIs(t, err, security.ErrDecryptionFailed)
+
+	// Swapping the ephemeral key changes the derived stream key
+	swapped := append([]byte(nil), ciphertext...)
+	copy(swapped, pub)
+	err = security.OpenStreamFrom(priv, &out, bytes.NewReader(swapped))
+	tst.AssertErrorIs(t, err, security.ErrDecryptionFailed)
+
+	err = security.OpenStreamFrom(priv, &out, bytes.NewReader(ciphertext[:10]))
+	tst.AssertErrorIs(t, err, security.ErrInvalidCiphertext)
+
+	// An all-zero ephemeral key is a low-order point
+	zeroHeader := append(make([]byte, 32), ciphertext[32:]...)
+	err = security.OpenStreamFrom(priv, &out, bytes.NewReader(zeroHeader))
+	tst.AssertErrorIs(t, err, security.ErrInvalidCiphertext)
+
+	err = security.SealStreamTo(pub[:31], &out, bytes.NewReader(nil))
+	tst.AssertErrorIs(t, err, security.ErrInvalidKeySize)
+	err = security.OpenStreamFrom(priv[:31], &out, bytes.NewReader(ciphertext))
+	tst.AssertErrorIs(t, err, security.ErrInvalidKeySize)
+}
@@ -0,0 +1,35 @@
+package security
+
+import "fmt"
+
+// EncryptWithKeyID encrypts plaintext with AES-GCM under key, like Encrypt, and
+// prepends keyID so the key version that produced the ciphertext can be
+// identified later. The output is keyID || nonce || ciphertext. The key ID is
+// authenticated as additional data, so changing it makes decryption fail
+// rather than selecting a different key.
+func EncryptWithKeyID(keyID byte, key, plaintext []byte) ([]byte, error) {
+	sealed, err := EncryptWithAAD(key, plaintext, []byte{keyID})
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{keyID}, sealed...), nil
+}
+
+// DecryptWithKeyResolver decrypts ciphertext produced by EncryptWithKeyID. It
+// reads the leading key ID and calls resolve to fetch the matching key, so
+// ciphertexts from old and new keys can be decrypted side by side during a
+// rotation. Errors from resolve are returned wrapped. It returns
+// ErrInvalidCiphertext if ciphertext is empty and ErrDecryptionFailed if it
+// was tampered with.
+func DecryptWithKeyResolver(ciphertext []byte, resolve func(keyID byte) ([]byte, error)) ([]byte, error) {
+	if len(ciphertext) == 0 {
+		return nil, ErrInvalidCiphertext
+	}
+	keyID := ciphertext[0]
+
+	key, err := resolve(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve key %d: %w", keyID, err)
+	}
+	return DecryptWithAAD(key, ciphertext[1:], []byte{keyID})
+}
@@ -0,0 +1,67 @@
+package security_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestEncryptWithKeyID_Rotation(t *testing.T) {
+	oldKey, err := security.GenerateAESKey(32)
+	tst.RequireNoError(t, err)
+	newKey, err := security.GenerateAESKey(32)
+	tst.RequireNoError(t, err)
+	keys := map[byte][]byte{1: oldKey, 2: newKey}
+	errUnknownKey := errors.New("unknown key")
+	resolve := func(keyID byte) ([]byte, error) {
+		key, ok := keys[keyID]
+		if !ok {
+			return nil, errUnknownKey
+		}
+		return key, nil
+	}
+
+	oldCiphertext, err := security.EncryptWithKeyID(1, oldKey, []byte("written before rotation"))
+	tst.RequireNoError(t, err)
+	newCiphertext, err := security.EncryptWithKeyID(2, newKey, []byte("written after rotation"))
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, oldCiphertext[0], byte(1))
+	tst.AssertEqual(t, newCiphertext[0], byte(2))
+
+	plaintext, err := security.DecryptWithKeyResolver(oldCiphertext, resolve)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, string(plaintext), "written before rotation")
+	plaintext, err = security.DecryptWithKeyResolver(newCiphertext, resolve)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, string(plaintext), "written after rotation")
+
+	// Retiring the old key surfaces the resolver's error
+	delete(keys, 1)
+	_, err = security.DecryptWithKeyResolver(oldCiphertext, resolve)
+	tst.AssertErrorIs(t, err, errUnknownKey)
+}
+
+func TestDecryptWithKeyResolver_Tampered(t *testing.T) {
+	key, err := security.GenerateAESKey(32)
+	tst.RequireNoError(t, err)
+	resolve := func(byte) ([]byte, error) { return key, nil }
+
+	ciphertext, err := security.EncryptWithKeyID(7, key, []byte("secret"))
+	tst.RequireNoError(t, err)
+
+	// The key ID is authenticated even when it resolves to the same key
+	relabeled := append([]byte(nil), ciphertext...)
+	relabeled[0] = 8
+	_, err = security.DecryptWithKeyResolver(relabeled, resolve)
+	tst.AssertErrorIs(t, err, security.ErrDecryptionFailed)
+
+	_, err = security.DecryptWithKeyResolver(nil, resolve)
+	tst.AssertErrorIs(t, err, security.ErrInvalidCiphertext)
+	_, err = security.DecryptWithKeyResolver([]byte{7, 1, 2}, resolve)
+	tst.AssertErrorIs(t, err, security.ErrInvalidCiphertext)
+
+	_, err = security.EncryptWithKeyID(1, []byte("short"), []byte("secret"))
+	tst.AssertErrorIs(t, err, security.ErrInvalidKeySize)
+}
@@ -0,0 +1,125 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrNoSigningKey is returned by RotatingKeyset.SigningKey when no key is
+// active at the requested time
+var ErrNoSigningKey = errors.New("no active signing key")
+
+// KeyInfo describes one key in a RotatingKeyset and the period in which it is
+// valid.
+type KeyInfo struct {
+	// Key is the secret key material.
+	Key []byte
+	// ActivatesAt is when the key becomes valid for signing and verification.
+	ActivatesAt time.Time
+	// ExpiresAt is when the key stops being valid. The zero value means the
+	// key never expires.
+	ExpiresAt time.Time
+}
+
+// activeAt reports whether the key is valid at now.
+func (k KeyInfo) activeAt(now time.Time) bool {
+	return !now.Before(k.ActivatesAt) && (k.ExpiresAt.IsZero() || now.Before(k.ExpiresAt))
+}
+
+// RotatingKeyset holds keys identified by key ID with overlapping validity
+// periods, so keys can be rotated without downtime: a new key is added with a
+// future activation time, takes over signing once active, and the old key
+// keeps verifying until it expires. It is safe for concurrent use.
+type RotatingKeyset struct {
+	mu   sync.RWMutex
+	keys map[string]KeyInfo
+}
+
+// NewRotatingKeyset creates a keyset from keys, indexed by key ID. It returns
+// an error if any key is invalid (see Add).
+func NewRotatingKeyset(keys map[string]KeyInfo) (*RotatingKeyset, error) {
+	ks := &RotatingKeyset{keys: make(map[string]KeyInfo, len(keys))}
+	for id, info := range keys {
+		if err := ks.Add(id, info); err != nil {
+			return nil, err
+		}
+	}
+	return ks, nil
+}
+
+// Add adds or replaces the key with the given ID. The key material is copied,
+// so later changes to info.Key do not affect the keyset. It returns
+// ErrEmptyKey if the key material is empty and an error if the ID is empty or
+// the key expires before it activates.
+func (ks *RotatingKeyset) Add(id string, info KeyInfo) error {
+	if id == "" {
+		return fmt.Errorf("key ID cannot be empty")
+	}
+	if len(info.Key) == 0 {
+		return fmt.Errorf("key %q: %w", id, ErrEmptyKey)
+	}
+	if !info.ExpiresAt.IsZero() && !info.ExpiresAt.After(info.ActivatesAt) {
+		return fmt.Errorf("key %q expires before it activates", id)
+	}
+
+	info.Key = append([]byte(nil), info.Key...)
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.keys[id] = info
+	return nil
+}
+
+// Remove deletes the key with the given ID, if present.
+func (ks *RotatingKeyset) Remove(id string) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	delete(ks.keys, id)
+}
+
+// SigningKey returns the ID and material of the key to sign with at now: the
+// most recently activated key that has not expired. Ties are broken by the
+// greater key ID so the choice is deterministic. The returned key is a copy.
+// It returns ErrNoSigningKey if no key is active.
+func (ks *RotatingKeyset) SigningKey(now time.Time) (string, []byte, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	var (
+		bestID string
+		best   KeyInfo
+		found  bool
+	)
+	for id, info := range ks.keys {
+		if !info.activeAt(now) {
+			continue
+		}
+		if !found || info.ActivatesAt.After(best.ActivatesAt) ||
+			(info.ActivatesAt.Equal(best.ActivatesAt) && id > bestID) {
+			bestID, best, found = id, info, true
+		}
+	}
+	if !found {
+		return "", nil, ErrNoSigningKey
+	}
+	return bestID, append([]byte(nil), best.Key...), nil
+}
+
+// VerificationKeys returns every key valid at now, indexed by key ID. This
+// includes the current signing key and retired keys that have been superseded
+// but have not yet expired, so signatures made before a rotation still verify.
+// The returned keys are copies.
+func (ks *RotatingKeyset) VerificationKeys(now time.Time) map[string][]byte {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	keys := make(map[string][]byte)
+	for id, info := range ks.keys {
+		if info.activeAt(now) {
+			keys[id] = append([]byte(nil), info.Key...)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,98 @@
+package security_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestRotatingKeyset_Rotation(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	oldKey := []byte("old-signing-key-0123456789abcdef")
+	newKey := []byte("new-signing-key-0123456789abcdef")
+
+	ks, err := security.NewRotatingKeyset(map[string]security.KeyInfo{
+		"2025-01": {Key: oldKey, ActivatesAt: t0, ExpiresAt: t0.Add(48 * time.Hour)},
+	})
+	tst.RequireNoError(t, err)
+
+	// Before rotation the old key signs
+	id, key, err := ks.SigningKey(t0.Add(time.Hour))
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, id, "2025-01")
+	message := []byte("payload")
+	oldSig, err := security.SignDetached(key, message)
+	tst.RequireNoError(t, err)
+
+	// Add the next key, activating a day later
+	tst.RequireNoError(t, ks.Add("2025-02", security.KeyInfo{Key: newKey, ActivatesAt: t0.Add(24 * time.Hour)}))
+	id, _, err = ks.SigningKey(t0.Add(12 * time.Hour))
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, id, "2025-01")
+
+	// During the overlap the new key signs and the old key still verifies
+	now := t0.Add(30 * time.Hour)
+	id, key, err = ks.SigningKey(now)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, id, "2025-02")
+	tst.AssertDeepEqual(t, key, newKey)
+
+	verify := ks.VerificationKeys(now)
+	tst.AssertEqual(t, len(verify), 2)
+	tst.AssertTrue(t, security.VerifyDetached(verify["2025-01"], message, oldSig), "old signature should verify during overlap")
+
+	// After expiry the old key is no longer accepted
+	verify = ks.VerificationKeys(t0.Add(48 * time.Hour))
+	tst.AssertEqual(t, len(verify), 1)
+	_, ok := verify["2025-01"]
+	tst.AssertFalse(t, ok, "expired key should not verify")
+}
+
+func TestRotatingKeyset_NoSigningKey(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ks, err := security.NewRotatingKeyset(map[string]security.KeyInfo{
+		"k1": {Key: []byte("key"), ActivatesAt: t0, ExpiresAt: t0.Add(time.Hour)},
+	})
+	tst.RequireNoError(t, err)
+
+	_, _, err = ks.SigningKey(t0.Add(-time.Minute))
+	tst.AssertErrorIs(t, err, security.ErrNoSigningKey)
+	_, _, err = ks.SigningKey(t0.Add(time.Hour))
+	tst.AssertErrorIs(t, err, security.ErrNoSigningKey)
+	tst.AssertEqual(t, len(ks.VerificationKeys(t0.Add(-time.Minute))), 0)
+
+	ks.Remove("k1")
+	_, _, err = ks.SigningKey(t0)
+	tst.AssertErrorIs(t, err, security.ErrNoSigningKey)
+}
+
+func TestRotatingKeyset_CopiesKeys(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	key := []byte("signing-key")
+	ks, err := security.NewRotatingKeyset(map[string]security.KeyInfo{"k1": {Key: key, ActivatesAt: t0}})
+	tst.RequireNoError(t, err)
+
+	// Mutating the caller's slice must not change the stored key
+	key[0] = 'X'
+	_, got, err := ks.SigningKey(t0)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, got, []byte("signing-key"))
+
+	// Nor must mutating a returned key
+	got[0] = 'Y'
+	tst.AssertDeepEqual(t, ks.VerificationKeys(t0)["k1"], []byte("signing-key"))
+}
+
+func TestNewRotatingKeyset_Invalid(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := security.NewRotatingKeyset(map[string]security.KeyInfo{"k1": {ActivatesAt: t0}})
+	tst.AssertErrorIs(t, err, security.ErrEmptyKey)
+
+	_, err = security.NewRotatingKeyset(map[string]security.KeyInfo{
+		"k1": {Key: []byte("key"), ActivatesAt: t0, ExpiresAt: t0.Add(-time.Hour)},
+	})
+	tst.AssertErrorContains(t, err, "expires before it activates")
+}
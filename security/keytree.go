@@ -0,0 +1,99 @@
+package security
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// KeyTreeKeySize is the size in bytes of keys returned by KeyTree.Derive
+const KeyTreeKeySize = 32
+
+// keyTreeInfoPrefix domain-separates KeyTree expansions from other HKDF uses
+// of the same root key.
+const keyTreeInfoPrefix = "go-utils/keytree:"
+
+// ErrEmptyPath is returned by KeyTree.Derive when no path labels are given
+var ErrEmptyPath = errors.New("key path cannot be empty")
+
+// KeyTree derives a hierarchy of keys from a single root key. Each path label
+// selects a child of the previous node using HKDF-Expand with SHA-256, so
+// tree.Derive("tenant42", "encryption") is the "encryption" child of the
+// "tenant42" node. Derivation is deterministic, and knowing a node's key does
+// not reveal its parent or siblings. Intermediate nodes are cached. A KeyTree
+// is safe for concurrent use.
+type KeyTree struct {
+	root []byte
+
+	mu    sync.RWMutex
+	cache map[string][]byte
+}
+
+// NewKeyTree creates a KeyTree rooted at root. The root should be at least 32
+// bytes of high-entropy key material; it is passed through HKDF-Extract before
+// use, so it need not be uniformly random.
+func NewKeyTree(root []byte) *KeyTree {
+	t := &KeyTree{cache: make(map[string][]byte)}
+	if len(root) > 0 {
+		t.root = hkdf.Extract(sha256.New, root, nil)
+	}
+	return t
+}
+
+// Derive returns the KeyTreeKeySize-byte key at path. The same path always
+// yields the same key for a given root, and different paths yield independent
+// keys. It returns ErrEmptyKey if the tree has no root key and ErrEmptyPath if
+// path is empty.
+func (t *KeyTree) Derive(path ...string) ([]byte, error) {
+	if t.root == nil {
+		return nil, ErrEmptyKey
+	}
+	if len(path) == 0 {
+		return nil, ErrEmptyPath
+	}
+
+	// Start from the deepest cached ancestor
+	node, depth := t.root, 0
+	t.mu.RLock()
+	for i := len(path); i > 0; i-- {
+		if key, ok := t.cache[keyTreeCacheKey(path[:i])]; ok {
+			node, depth = key, i
+			break
+		}
+	}
+	t.mu.RUnlock()
+
+	for ; depth < len(path); depth++ {
+		child, err := HKDFExpand(node, keyTreeInfoPrefix+path[depth], KeyTreeKeySize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive key: %w", err)
+		}
+		node = child
+
+		t.mu.Lock()
+		t.cache[keyTreeCacheKey(path[:depth+1])] = child
+		t.mu.Unlock()
+	}
+
+	key := make([]byte, len(node))
+	copy(key, node)
+	return key, nil
+}
+
+// keyTreeCacheKey joins path labels with a separator that cannot be confused
+// with label contents, so ("ab", "c") and ("a", "bc") are cached separately.
+func keyTreeCacheKey(path []string) string {
+	var b strings.Builder
+	for _, label := range path {
+		b.WriteString(strconv.Itoa(len(label)))
+		b.WriteByte(':')
+		b.WriteString(label)
+		b.WriteByte('/')
+	}
+	return b.String()
+}
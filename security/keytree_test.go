@@ -0,0 +1,89 @@
+package security_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+var keyTreeRoot = []byte("0123456789abcdef0123456789abcdef")
+
+func mustDerive(t *testing.T, tree *security.KeyTree, path ...string) []byte {
+	t.Helper()
+	key, err := tree.Derive(path...)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, len(key), security.KeyTreeKeySize)
+	return key
+}
+
+func TestKeyTree_Deterministic(t *testing.T) {
+	tree := security.NewKeyTree(keyTreeRoot)
+	first := mustDerive(t, tree, "tenant42", "encryption")
+	second := mustDerive(t, tree, "tenant42", "encryption")
+	tst.AssertDeepEqual(t, first, second)
+
+	// A fresh tree with no cached nodes derives the same key
+	fresh := mustDerive(t, security.NewKeyTree(keyTreeRoot), "tenant42", "encryption")
+	tst.AssertDeepEqual(t, first, fresh)
+
+	// Deriving a descendant of a cached node gives the same result as a cold derivation
+	warm := security.NewKeyTree(keyTreeRoot)
+	mustDerive(t, warm, "tenant42")
+	tst.AssertDeepEqual(t, mustDerive(t, warm, "tenant42", "encryption", "v1"),
+		mustDerive(t, security.NewKeyTree(keyTreeRoot), "tenant42", "encryption", "v1"))
+}
+
+func TestKeyTree_DifferentPathsDiffer(t *testing.T) {
+	tree := security.NewKeyTree(keyTreeRoot)
+	paths := [][]string{
+		{"tenant42"},
+		{"tenant42", "encryption"},
+		{"tenant42", "signing"},
+		{"tenant43", "encryption"},
+		{"encryption", "tenant42"},
+		{"ab", "c"},
+		{"a", "bc"},
+	}
+
+	keys := make([][]byte, len(paths))
+	for i, path := range paths {
+		keys[i] = mustDerive(t, tree, path...)
+	}
+	for i := range keys {
+		for j := i + 1; j < len(keys); j++ {
+			tst.AssertFalse(t, bytes.Equal(keys[i], keys[j]), fmt.Sprintf("paths %v and %v should derive different keys", paths[i], paths[j]))
+		}
+	}
+
+	other := mustDerive(t, security.NewKeyTree([]byte("another root key of 32 bytes....")), "tenant42", "encryption")
+	tst.AssertFalse(t, bytes.Equal(keys[1], other), "different roots should derive different keys")
+}
+
+func TestKeyTree_SiblingsIndependent(t *testing.T) {
+	tree := security.NewKeyTree(keyTreeRoot)
+	before := mustDerive(t, tree, "tenant42", "encryption")
+
+	// Deriving and tampering with a sibling does not affect the other branch
+	sibling := mustDerive(t, tree, "tenant42", "signing")
+	for i := range sibling {
+		sibling[i] = 0
+	}
+	after := mustDerive(t, tree, "tenant42", "encryption")
+	tst.AssertDeepEqual(t, before, after)
+
+	// Returned keys are copies, so modifying one does not corrupt the cache
+	parent := mustDerive(t, tree, "tenant42")
+	parent[0] ^= 0xff
+	tst.AssertDeepEqual(t, mustDerive(t, tree, "tenant42", "encryption"), before)
+}
+
+func TestKeyTree_Errors(t *testing.T) {
+	_, err := security.NewKeyTree(keyTreeRoot).Derive()
+	tst.AssertErrorIs(t, err, security.ErrEmptyPath)
+
+	_, err = security.NewKeyTree(nil).Derive("tenant42")
+	tst.AssertErrorIs(t, err, security.ErrEmptyKey)
+}
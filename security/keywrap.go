@@ -0,0 +1,94 @@
+package security
+
+import (
+	"crypto/aes"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+)
+
+// defaultKeyWrapIV is the RFC 3394 default initial value.
+var defaultKeyWrapIV = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6}
+
+// WrapKey wraps key under the key-encryption key kek using the AES Key Wrap
+// algorithm (RFC 3394). Unlike Encrypt, the output is deterministic and
+// interoperable with KMS and HSM systems that implement AES-KW. kek must be
+// 16, 24, or 32 bytes and key must be at least 16 bytes and a multiple of 8.
+// The wrapped key is 8 bytes longer than key.
+func WrapKey(kek, key []byte) ([]byte, error) {
+	if len(kek) != 16 && len(kek) != 24 && len(kek) != 32 {
+		return nil, ErrInvalidKeySize
+	}
+	if len(key) < 16 || len(key)%8 != 0 {
+		return nil, fmt.Errorf("key to wrap must be at least 16 bytes and a multiple of 8: %w", ErrInvalidKeySize)
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	n := len(key) / 8
+	out := make([]byte, len(key)+8)
+	copy(out[:8], defaultKeyWrapIV)
+	copy(out[8:], key)
+
+	buf := make([]byte, 16)
+	for j := 0; j < 6; j++ {
+		for i := 1; i <= n; i++ {
+			// B = AES(K, A | R[i])
+			copy(buf[:8], out[:8])
+			copy(buf[8:], out[i*8:(i+1)*8])
+			block.Encrypt(buf, buf)
+
+			// A = MSB(64, B) ^ t where t = (n*j)+i; R[i] = LSB(64, B)
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(out[:8], binary.BigEndian.Uint64(buf[:8])^t)
+			copy(out[i*8:(i+1)*8], buf[8:])
+		}
+	}
+
+	return out, nil
+}
+
+// UnwrapKey reverses WrapKey, returning the key wrapped under kek. It returns
+// ErrDecryptionFailed if the integrity check fails, which happens when kek is
+// wrong or wrapped has been modified.
+func UnwrapKey(kek, wrapped []byte) ([]byte, error) {
+	if len(kek) != 16 && len(kek) != 24 && len(kek) != 32 {
+		return nil, ErrInvalidKeySize
+	}
+	if len(wrapped) < 24 || len(wrapped)%8 != 0 {
+		return nil, ErrInvalidCiphertext
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	n := len(wrapped)/8 - 1
+	a := make([]byte, 8)
+	copy(a, wrapped[:8])
+	r := make([]byte, len(wrapped)-8)
+	copy(r, wrapped[8:])
+
+	buf := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			// B = AES-1(K, (A ^ t) | R[i]) where t = n*j+i
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(buf[:8], binary.BigEndian.Uint64(a)^t)
+			copy(buf[8:], r[(i-1)*8:i*8])
+			block.Decrypt(buf, buf)
+
+			copy(a, buf[:8])
+			copy(r[(i-1)*8:i*8], buf[8:])
+		}
+	}
+
+	if subtle.ConstantTimeCompare(a, defaultKeyWrapIV) != 1 {
+		return nil, ErrDecryptionFailed
+	}
+	return r, nil
+}
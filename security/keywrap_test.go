@@ -0,0 +1,100 @@
+package security_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	tst.RequireNoError(t, err)
+	return b
+}
+
+// Test vectors from RFC 3394, section 4
+func TestWrapKey_RFC3394Vectors(t *testing.T) {
+	tests := []struct {
+		name    string
+		kek     string
+		key     string
+		wrapped string
+	}{
+		{
+			name:    "4.1 128-bit key with 128-bit KEK",
+			kek:     "000102030405060708090A0B0C0D0E0F",
+			key:     "00112233445566778899AABBCCDDEEFF",
+			wrapped: "1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5",
+		},
+		{
+			name:    "4.2 128-bit key with 192-bit KEK",
+			kek:     "000102030405060708090A0B0C0D0E0F1011121314151617",
+			key:     "00112233445566778899AABBCCDDEEFF",
+			wrapped: "96778B25AE6CA435F92B5B97C050AED2468AB8A17AD84E5D",
+		},
+		{
+			name:    "4.3 128-bit key with 256-bit KEK",
+			kek:     "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+			key:     "00112233445566778899AABBCCDDEEFF",
+			wrapped: "64E8C3F9CE0F5BA263E9777905818A2A93C8191E7D6E8AE7",
+		},
+		{
+			name:    "4.4 192-bit key with 192-bit KEK",
+			kek:     "000102030405060708090A0B0C0D0E0F1011121314151617",
+			key:     "00112233445566778899AABBCCDDEEFF0001020304050607",
+			wrapped: "031D33264E15D33268F24EC260743EDCE1C6C7DDEE725A936BA814915C6762D2",
+		},
+		{
+			name:    "4.5 192-bit key with 256-bit KEK",
+			kek:     "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+			key:     "00112233445566778899AABBCCDDEEFF0001020304050607",
+			wrapped: "A8F9BC1612C68B3FF6E6F4FBE30E71E4769C8B80A32CB8958CD5D17D6B254DA1",
+		},
+		{
+			name:    "4.6 256-bit key with 256-bit KEK",
+			kek:     "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+			key:     "00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F",
+			wrapped: "28C9F404C4B810F4CBCCB35CFB87F8263F5786E2D80ED326CBC7F0E71A99F43BFB988B9B7A02DD21",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kek, key, want := mustHex(t, tt.kek), mustHex(t, tt.key), mustHex(t, tt.wrapped)
+
+			wrapped, err := security.WrapKey(kek, key)
+			tst.RequireNoError(t, err)
+			tst.AssertDeepEqual(t, wrapped, want)
+
+			unwrapped, err := security.UnwrapKey(kek, wrapped)
+			tst.RequireNoError(t, err)
+			tst.AssertDeepEqual(t, unwrapped, key)
+		})
+	}
+}
+
+func TestUnwrapKey_Errors(t *testing.T) {
+	kek := mustHex(t, "000102030405060708090A0B0C0D0E0F")
+	wrapped := mustHex(t, "1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5")
+
+	tampered := append([]byte{}, wrapped...)
+	tampered[10] ^= 0x01
+	_, err := security.UnwrapKey(kek, tampered)
+	tst.AssertErrorIs(t, err, security.ErrDecryptionFailed)
+
+	otherKEK := mustHex(t, "0F0E0D0C0B0A09080706050403020100")
+	_, err = security.UnwrapKey(otherKEK, wrapped)
+	tst.AssertErrorIs(t, err, security.ErrDecryptionFailed)
+
+	_, err = security.UnwrapKey(kek, wrapped[:20])
+	tst.AssertErrorIs(t, err, security.ErrInvalidCiphertext)
+
+	_, err = security.WrapKey(kek[:10], kek)
+	tst.AssertErrorIs(t, err, security.ErrInvalidKeySize)
+
+	_, err = security.WrapKey(kek, []byte("short"))
+	tst.AssertErrorIs(t, err, security.ErrInvalidKeySize)
+}
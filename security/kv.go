@@ -0,0 +1,69 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+)
+
+// sealKVNonceInfo separates the nonce-derivation key from other uses of the
+// sealing key.
+const sealKVNonceInfo = "go-utils/sealkv-nonce"
+
+// SealKV encrypts a small key-value bundle, such as a set of secrets stored
+// in config, with AES-GCM under key and returns it base64-encoded. The bundle
+// is serialized canonically with keys in sorted order, and the nonce is
+// derived from the key and the serialized bundle, so equal bundles always seal
+// to the same string regardless of map iteration order. This makes sealed
+// values diff-friendly but reveals whether two sealed bundles are equal. key
+// must be 16, 24, or 32 bytes.
+func SealKV(key []byte, kv map[string]string) (string, error) {
+	if kv == nil {
+		kv = map[string]string{}
+	}
+	// json.Marshal writes map keys in sorted order
+	plaintext, err := json.Marshal(kv)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize bundle: %w", err)
+	}
+
+	gcm, err := newAESGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonceKey := hmac.New(sha256.New, key)
+	nonceKey.Write([]byte(sealKVNonceInfo))
+	mac := hmac.New(sha256.New, nonceKey.Sum(nil))
+	mac.Write(plaintext)
+	nonce := mac.Sum(nil)[:gcm.NonceSize()]
+
+	return EncodeBase64(gcm.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// OpenKV decrypts a bundle sealed by SealKV. It returns ErrInvalidCiphertext
+// if sealed is not validly encoded and ErrDecryptionFailed if it has been
+// tampered with or was sealed under a different key.
+func OpenKV(key []byte, sealed string) (map[string]string, error) {
+	gcm, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := DecodeBase64(sealed)
+	if err != nil || len(data) < gcm.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	var kv map[string]string
+	if err := json.Unmarshal(plaintext, &kv); err != nil {
+		return nil, fmt.Errorf("failed to deserialize bundle: %w", err)
+	}
+	return kv, nil
+}
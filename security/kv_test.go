@@ -0,0 +1,77 @@
+package security_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestSealKV_RoundTrip(t *testing.T) {
+	key, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+
+	kv := map[string]string{"DB_PASSWORD": "hunter2", "API_TOKEN": "tok-123", "EMPTY": ""}
+	sealed, err := security.SealKV(key, kv)
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, strings.Contains(sealed, "hunter2"), "values should not be readable in the sealed bundle")
+
+	opened, err := security.OpenKV(key, sealed)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, opened, kv)
+
+	sealed, err = security.SealKV(key, nil)
+	tst.RequireNoError(t, err)
+	opened, err = security.OpenKV(key, sealed)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, len(opened), 0)
+}
+
+func TestSealKV_Stable(t *testing.T) {
+	key, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+
+	a := map[string]string{}
+	b := map[string]string{}
+	keys := []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot"}
+	for i := range keys {
+		a[keys[i]] = "value-" + keys[i]
+		j := len(keys) - 1 - i
+		b[keys[j]] = "value-" + keys[j]
+	}
+
+	sealedA, err := security.SealKV(key, a)
+	tst.RequireNoError(t, err)
+	sealedB, err := security.SealKV(key, b)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, sealedA, sealedB)
+
+	b["alpha"] = "changed"
+	sealedB, err = security.SealKV(key, b)
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, sealedA != sealedB, "different bundles should seal differently")
+}
+
+func TestOpenKV_Tampered(t *testing.T) {
+	key, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+	otherKey, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+
+	sealed, err := security.SealKV(key, map[string]string{"DB_PASSWORD": "hunter2"})
+	tst.RequireNoError(t, err)
+
+	raw, err := security.DecodeBase64(sealed)
+	tst.RequireNoError(t, err)
+	raw[len(raw)/2] ^= 0x01
+
+	_, err = security.OpenKV(key, security.EncodeBase64(raw))
+	tst.AssertErrorIs(t, err, security.ErrDecryptionFailed)
+	_, err = security.OpenKV(otherKey, sealed)
+	tst.AssertErrorIs(t, err, security.ErrDecryptionFailed)
+	_, err = security.OpenKV(key, "not base64!")
+	tst.AssertErrorIs(t, err, security.ErrInvalidCiphertext)
+	_, err = security.OpenKV(key[:5], sealed)
+	tst.AssertErrorIs(t, err, security.ErrInvalidKeySize)
+}
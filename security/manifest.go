@@ -0,0 +1,140 @@
+package security
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ErrInvalidManifest is returned when a manifest's signature does not match
+// its contents, meaning it was modified or signed under a different key
+var ErrInvalidManifest = errors.New("invalid manifest signature")
+
+// Manifest records the SHA-256 digest of every regular file under a directory,
+// signed with HMAC-SHA256 so that it cannot be edited to match tampered files.
+// It marshals to JSON for storage alongside (or away from) the directory.
+type Manifest struct {
+	// Files maps slash-separated paths relative to the root to hex-encoded
+	// SHA-256 digests.
+	Files map[string]string `json:"files"`
+	// Signature is the HMAC-SHA256 of the canonically serialized Files.
+	Signature []byte `json:"signature"`
+}
+
+// GenerateManifest walks root, hashes every regular file beneath it, and
+// returns a manifest signed with key. Symlinks and other non-regular files are
+// skipped. It returns ErrEmptyKey if key is empty.
+func GenerateManifest(key []byte, root string) (*Manifest, error) {
+	if len(key) == 0 {
+		return nil, ErrEmptyKey
+	}
+	files, err := hashTree(root)
+	if err != nil {
+		return nil, err
+	}
+	return &Manifest{Files: files, Signature: HMACSHA256(key, manifestPayload(files))}, nil
+}
+
+// VerifyManifest checks m's signature with key and compares it against the
+// current contents of root. It returns the sorted paths of files that were
+// modified, removed, or added since the manifest was generated; an empty
+// result means the directory is unchanged. It returns ErrInvalidManifest if
+// the manifest itself has been tampered with, in which case none of its
+// digests can be trusted.
+func VerifyManifest(key []byte, root string, m *Manifest) ([]string, error) {
+	if len(key) == 0 {
+		return nil, ErrEmptyKey
+	}
+	if m == nil || !VerifyHMACSHA256(key, manifestPayload(m.Files), m.Signature) {
+		return nil, ErrInvalidManifest
+	}
+
+	current, err := hashTree(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for path, digest := range m.Files {
+		if current[path] != digest {
+			changed = append(changed, path)
+		}
+	}
+	for path := range current {
+		if _, ok := m.Files[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// hashTree returns the hex-encoded SHA-256 digest of every regular file under
+// root, keyed by slash-separated relative path.
+func hashTree(root string) (map[string]string, error) {
+	files := make(map[string]string)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		digest, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = digest
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash directory: %w", err)
+	}
+	return files, nil
+}
+
+// hashFile returns the hex-encoded SHA-256 digest of the file at path.
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// manifestPayload serializes files in sorted path order so the signature does
+// not depend on map iteration order. Paths cannot contain NUL bytes, so the
+// separators are unambiguous.
+func manifestPayload(files map[string]string) []byte {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var buf bytes.Buffer
+	for _, path := range paths {
+		buf.WriteString(path)
+		buf.WriteByte(0)
+		buf.WriteString(files[path])
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,108 @@
+package security_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func writeManifestTree(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	tst.RequireNoError(t, os.MkdirAll(filepath.Join(root, "conf", "nested"), 0o755))
+	files := map[string]string{
+		"app.bin":                "binary contents",
+		"conf/settings.yaml":     "debug: false\n",
+		"conf/nested/extra.json": `{"a": 1}`,
+		"conf/nested/empty.txt":  "",
+		"README":                 "read me",
+	}
+	for name, content := range files {
+		tst.RequireNoError(t, os.WriteFile(filepath.Join(root, filepath.FromSlash(name)), []byte(content), 0o644))
+	}
+	return root
+}
+
+func TestManifest_Unchanged(t *testing.T) {
+	key, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+	root := writeManifestTree(t)
+
+	m, err := security.GenerateManifest(key, root)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, len(m.Files), 5)
+	tst.AssertEqual(t, m.Files["conf/nested/empty.txt"], "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+
+	changed, err := security.VerifyManifest(key, root, m)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, len(changed), 0)
+
+	// Survives a JSON round trip
+	data, err := json.Marshal(m)
+	tst.RequireNoError(t, err)
+	var loaded security.Manifest
+	tst.RequireNoError(t, json.Unmarshal(data, &loaded))
+	changed, err = security.VerifyManifest(key, root, &loaded)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, len(changed), 0)
+}
+
+func TestManifest_DetectsChanges(t *testing.T) {
+	key, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+	root := writeManifestTree(t)
+
+	m, err := security.GenerateManifest(key, root)
+	tst.RequireNoError(t, err)
+
+	// Modify, add, and remove one file each
+	tst.RequireNoError(t, os.WriteFile(filepath.Join(root, "conf", "settings.yaml"), []byte("debug: true\n"), 0o644))
+	tst.RequireNoError(t, os.WriteFile(filepath.Join(root, "conf", "nested", "backdoor.sh"), []byte("#!/bin/sh"), 0o755))
+	tst.RequireNoError(t, os.Remove(filepath.Join(root, "README")))
+
+	changed, err := security.VerifyManifest(key, root, m)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, changed, []string{"README", "conf/nested/backdoor.sh", "conf/settings.yaml"})
+}
+
+func TestManifest_Tampered(t *testing.T) {
+	key, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+	otherKey, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+	root := writeManifestTree(t)
+
+	m, err := security.GenerateManifest(key, root)
+	tst.RequireNoError(t, err)
+
+	// Rewriting a digest to match a modified file breaks the signature
+	newContent := []byte("debug: true\n")
+	tst.RequireNoError(t, os.WriteFile(filepath.Join(root, "conf", "settings.yaml"), newContent, 0o644))
+	forged, err := security.GenerateManifest(otherKey, root)
+	tst.RequireNoError(t, err)
+	m.Files["conf/settings.yaml"] = forged.Files["conf/settings.yaml"]
+	_, err = security.VerifyManifest(key, root, m)
+	tst.AssertErrorIs(t, err, security.ErrInvalidManifest)
+
+	_, err = security.VerifyManifest(key, root, forged)
+	tst.AssertErrorIs(t, err, security.ErrInvalidManifest)
+
+	_, err = security.VerifyManifest(key, root, nil)
+	tst.AssertErrorIs(t, err, security.ErrInvalidManifest)
+}
+
+func TestManifest_Errors(t *testing.T) {
+	root := writeManifestTree(t)
+
+	_, err := security.GenerateManifest(nil, root)
+	tst.AssertErrorIs(t, err, security.ErrEmptyKey)
+
+	key, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+	_, err = security.GenerateManifest(key, filepath.Join(root, "missing"))
+	tst.AssertErrorIs(t, err, os.ErrNotExist)
+}
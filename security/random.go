@@ -0,0 +1,66 @@
+package security
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// RandomInt returns a uniformly distributed random integer in [0, max) read
+// from crypto/rand. It returns ErrInvalidRange if max is not positive.
+func RandomInt(max int64) (int64, error) {
+	if max <= 0 {
+		return 0, fmt.Errorf("%w: max must be positive, got %d", ErrInvalidRange, max)
+	}
+	n, err := randomUint64n(uint64(max))
+	if err != nil {
+		return 0, err
+	}
+	return int64(n), nil
+}
+
+// RandomIntRange returns a uniformly distributed random integer in [min, max).
+// It returns ErrInvalidRange if max is not greater than min.
+func RandomIntRange(min, max int64) (int64, error) {
+	if max <= min {
+		return 0, fmt.Errorf("%w: max (%d) must be greater than min (%d)", ErrInvalidRange, max, min)
+	}
+	// Unsigned arithmetic keeps the span exact even when max-min overflows int64
+	n, err := randomUint64n(uint64(max) - uint64(min))
+	if err != nil {
+		return 0, err
+	}
+	return int64(uint64(min) + n), nil
+}
+
+// RandomChoice returns a uniformly chosen element of items. It returns
+// ErrInvalidRange if items is empty.
+func RandomChoice[T any](items []T) (T, error) {
+	var zero T
+	if len(items) == 0 {
+		return zero, fmt.Errorf("%w: cannot choose from an empty slice", ErrInvalidRange)
+	}
+	i, err := RandomInt(int64(len(items)))
+	if err != nil {
+		return zero, err
+	}
+	return items[i], nil
+}
+
+// randomUint64n returns a uniform random value in [0, n) for n > 0. Taking a
+// raw 64-bit value modulo n would favor small results whenever n does not
+// divide 2^64, so values below 2^64 mod n are rejected and redrawn.
+func randomUint64n(n uint64) (uint64, error) {
+	threshold := -n % n
+	var buf [8]byte
+	for {
+		if _, err := io.ReadFull(rand.Reader, buf[:]); err != nil {
+			return 0, fmt.Errorf("failed to generate random number: %w", err)
+		}
+		v := binary.BigEndian.Uint64(buf[:])
+		if v >= threshold {
+			return v % n, nil
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package security_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestRandomInt_Bounds(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		n, err := security.RandomInt(10)
+		tst.RequireNoError(t, err)
+		tst.AssertTrue(t, n >= 0 && n < 10, "RandomInt(10) out of range")
+	}
+
+	n, err := security.RandomInt(1)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, n, int64(0))
+
+	_, err = security.RandomInt(0)
+	tst.AssertErrorIs(t, err, security.ErrInvalidRange)
+	_, err = security.RandomInt(-5)
+	tst.AssertErrorIs(t, err, security.ErrInvalidRange)
+}
+
+func TestRandomIntRange_Bounds(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		n, err := security.RandomIntRange(-5, 5)
+		tst.RequireNoError(t, err)
+		tst.AssertTrue(t, n >= -5 && n < 5, "RandomIntRange(-5, 5) out of range")
+	}
+
+	// The span exceeds math.MaxInt64 and must not overflow
+	for i := 0; i < 100; i++ {
+		_, err := security.RandomIntRange(math.MinInt64, math.MaxInt64)
+		tst.RequireNoError(t, err)
+	}
+
+	_, err := security.RandomIntRange(5, 5)
+	tst.AssertErrorIs(t, err, security.ErrInvalidRange)
+	_, err = security.RandomIntRange(6, 5)
+	tst.AssertErrorIs(t, err, security.ErrInvalidRange)
+}
+
+func TestRandomInt_Uniform(t *testing.T) {
+	const buckets = 6
+	const samples = 60000
+	counts := make([]int, buckets)
+	for i := 0; i < samples; i++ {
+		n, err := security.RandomInt(buckets)
+		tst.RequireNoError(t, err)
+		counts[n]++
+	}
+
+	// Chi-squared test with 5 degrees of freedom; 35.89 is the critical value
+	// for p = 1e-6, so a uniform source fails about once per million runs
+	expected := float64(samples) / buckets
+	var chi2 float64
+	for _, c := range counts {
+		d := float64(c) - expected
+		chi2 += d * d / expected
+	}
+	if chi2 > 35.89 {
+		t.Errorf("distribution looks non-uniform: counts=%v chi2=%.2f", counts, chi2)
+	}
+}
+
+func TestRandomChoice(t *testing.T) {
+	items := []string{"a", "b", "c"}
+	seen := map[string]bool{}
+	for i := 0; i < 300; i++ {
+		v, err := security.RandomChoice(items)
+		tst.RequireNoError(t, err)
+		seen[v] = true
+	}
+	tst.AssertEqual(t, len(seen), len(items))
+
+	_, err := security.RandomChoice([]int{})
+	tst.AssertErrorIs(t, err, security.ErrInvalidRange)
+}
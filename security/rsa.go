@@ -0,0 +1,123 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrPlaintextTooLarge is returned when plaintext exceeds the RSA-OAEP limit
+	// for the key size
+	ErrPlaintextTooLarge = errors.New("plaintext too large for RSA key")
+	// ErrInvalidPEM is returned when PEM data has no block or an unexpected block
+	// type
+	ErrInvalidPEM = errors.New("invalid PEM data")
+)
+
+// RSA-OAEP Encryption/Decryption
+
+// EncryptRSA encrypts plaintext for the holder of pub using RSA-OAEP with
+// SHA-256. RSA only suits small secrets such as wrapped symmetric keys: the
+// plaintext may be at most MaxRSAPlaintextSize(pub) bytes (190 for a 2048-bit
+// key), and ErrPlaintextTooLarge is returned otherwise.
+func EncryptRSA(pub *rsa.PublicKey, plaintext []byte) ([]byte, error) {
+	if pub == nil {
+		return nil, ErrEmptyKey
+	}
+	if limit := MaxRSAPlaintextSize(pub); len(plaintext) > limit {
+		return nil, fmt.Errorf("%w: %d bytes exceeds the %d-byte limit for a %d-bit key",
+			ErrPlaintextTooLarge, len(plaintext), limit, pub.Size()*8)
+	}
+
+	ciphertext, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pub, plaintext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt with RSA: %w", err)
+	}
+	return ciphertext, nil
+}
+
+// DecryptRSA decrypts ciphertext produced by EncryptRSA. It returns
+// ErrDecryptionFailed if the ciphertext was tampered with or encrypted for a
+// different key.
+func DecryptRSA(priv *rsa.PrivateKey, ciphertext []byte) ([]byte, error) {
+	if priv == nil {
+		return nil, ErrEmptyKey
+	}
+	plaintext, err := rsa.DecryptOAEP(sha256.New(), nil, priv, ciphertext, nil)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return plaintext, nil
+}
+
+// MaxRSAPlaintextSize returns the largest plaintext EncryptRSA accepts for
+// pub: the key size in bytes minus the OAEP overhead of two SHA-256 digests
+// and two bytes.
+func MaxRSAPlaintextSize(pub *rsa.PublicKey) int {
+	return pub.Size() - 2*sha256.Size - 2
+}
+
+// ParseRSAPublicKeyPEM parses a PEM-encoded RSA public key in either PKIX
+// ("PUBLIC KEY") or PKCS #1 ("RSA PUBLIC KEY") form.
+func ParseRSAPublicKeyPEM(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidPEM
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: public key is %T, not RSA", ErrInvalidPEM, key)
+		}
+		return pub, nil
+	case "RSA PUBLIC KEY":
+		pub, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		return pub, nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected block type %q", ErrInvalidPEM, block.Type)
+	}
+}
+
+// ParseRSAPrivateKeyPEM parses a PEM-encoded, unencrypted RSA private key in
+// either PKCS #8 ("PRIVATE KEY") or PKCS #1 ("RSA PRIVATE KEY") form.
+func ParseRSAPrivateKeyPEM(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidPEM
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		priv, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: private key is %T, not RSA", ErrInvalidPEM, key)
+		}
+		return priv, nil
+	case "RSA PRIVATE KEY":
+		priv, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		return priv, nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected block type %q", ErrInvalidPEM, block.Type)
+	}
+}
@@ -0,0 +1,96 @@
+package security_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestEncryptDecryptRSA(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	tst.RequireNoError(t, err)
+
+	secret, err := security.GenerateAESKey(32)
+	tst.RequireNoError(t, err)
+	ciphertext, err := security.EncryptRSA(&priv.PublicKey, secret)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, len(ciphertext), 256)
+
+	decrypted, err := security.DecryptRSA(priv, ciphertext)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, decrypted, secret)
+
+	// The OAEP limit for a 2048-bit key with SHA-256 is 190 bytes
+	tst.AssertEqual(t, security.MaxRSAPlaintextSize(&priv.PublicKey), 190)
+	_, err = security.EncryptRSA(&priv.PublicKey, make([]byte, 190))
+	tst.RequireNoError(t, err)
+	_, err = security.EncryptRSA(&priv.PublicKey, make([]byte, 191))
+	tst.AssertErrorIs(t, err, security.ErrPlaintextTooLarge)
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	tst.RequireNoError(t, err)
+	_, err = security.DecryptRSA(other, ciphertext)
+	tst.AssertErrorIs(t, err, security.ErrDecryptionFailed)
+
+	ciphertext[10] ^= 0xff
+	_, err = security.DecryptRSA(priv, ciphertext)
+	tst.AssertErrorIs(t, err, security.ErrDecryptionFailed)
+
+	_, err = security.EncryptRSA(nil, secret)
+	tst.AssertErrorIs(t, err, security.ErrEmptyKey)
+	_, err = security.DecryptRSA(nil, ciphertext)
+	tst.AssertErrorIs(t, err, security.ErrEmptyKey)
+}
+
+func TestParseRSAKeysPEM(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	tst.RequireNoError(t, err)
+
+	pkixBytes, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	tst.RequireNoError(t, err)
+	pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(priv)
+	tst.RequireNoError(t, err)
+
+	publicPEMs := [][]byte{
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkixBytes}),
+		pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&priv.PublicKey)}),
+	}
+	for _, data := range publicPEMs {
+		pub, err := security.ParseRSAPublicKeyPEM(data)
+		tst.RequireNoError(t, err)
+		tst.AssertTrue(t, pub.Equal(&priv.PublicKey), "parsed public key should match")
+	}
+
+	privatePEMs := [][]byte{
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes}),
+		pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)}),
+	}
+	for _, data := range privatePEMs {
+		parsed, err := security.ParseRSAPrivateKeyPEM(data)
+		tst.RequireNoError(t, err)
+		tst.AssertTrue(t, parsed.Equal(priv), "parsed private key should match")
+	}
+
+	_, err = security.ParseRSAPublicKeyPEM([]byte("not pem"))
+	tst.AssertErrorIs(t, err, security.ErrInvalidPEM)
+	_, err = security.ParseRSAPrivateKeyPEM(publicPEMs[0])
+	tst.AssertErrorIs(t, err, security.ErrInvalidPEM)
+
+	// Non-RSA keys are rejected
+	edPub, edPriv, err := ed25519.GenerateKey(rand.Reader)
+	tst.RequireNoError(t, err)
+	edPubBytes, err := x509.MarshalPKIXPublicKey(edPub)
+	tst.RequireNoError(t, err)
+	edPrivBytes, err := x509.MarshalPKCS8PrivateKey(edPriv)
+	tst.RequireNoError(t, err)
+	_, err = security.ParseRSAPublicKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: edPubBytes}))
+	tst.AssertErrorIs(t, err, security.ErrInvalidPEM)
+	_, err = security.ParseRSAPrivateKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edPrivBytes}))
+	tst.AssertErrorIs(t, err, security.ErrInvalidPEM)
+}
@@ -0,0 +1,85 @@
+package security
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrSecretNotFound is returned when a secret provider cannot resolve a secret
+var ErrSecretNotFound = errors.New("secret not found")
+
+// SecretProvider resolves named secrets from a backing store such as the
+// environment, a directory of files, or a key management service.
+type SecretProvider interface {
+	// GetSecret returns the secret value for name, or an error wrapping
+	// ErrSecretNotFound if no such secret exists.
+	GetSecret(name string) ([]byte, error)
+}
+
+// EnvSecretProvider resolves secrets from environment variables.
+type EnvSecretProvider struct {
+	// Prefix is prepended to the secret name to form the variable name
+	// (e.g., Prefix "APP_" and name "DB_PASSWORD" reads APP_DB_PASSWORD).
+	Prefix string
+}
+
+// NewEnvSecretProvider creates a SecretProvider backed by environment variables.
+func NewEnvSecretProvider(prefix string) *EnvSecretProvider {
+	return &EnvSecretProvider{Prefix: prefix}
+}
+
+// GetSecret returns the value of the environment variable Prefix+name.
+// A variable that is set but empty is treated as missing.
+func (p *EnvSecretProvider) GetSecret(name string) ([]byte, error) {
+	if name == "" {
+		return nil, fmt.Errorf("secret name is empty")
+	}
+
+	value, ok := os.LookupEnv(p.Prefix + name)
+	if !ok || value == "" {
+		return nil, fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+	}
+	return []byte(value), nil
+}
+
+// FileSecretProvider resolves secrets from files in a directory, one file per
+// secret, as used by Docker and Kubernetes secret mounts.
+type FileSecretProvider struct {
+	// Dir is the directory containing the secret files.
+	Dir string
+}
+
+// NewFileSecretProvider creates a SecretProvider backed by files in dir.
+func NewFileSecretProvider(dir string) *FileSecretProvider {
+	return &FileSecretProvider{Dir: dir}
+}
+
+// GetSecret reads the file named name from the provider's directory. A single
+// trailing "\n" or "\r\n" is trimmed since secret files are commonly written
+// with one; any other bytes, including further newlines, are kept. Names
+// containing path separators are rejected.
+func (p *FileSecretProvider) GetSecret(name string) ([]byte, error) {
+	if name == "" {
+		return nil, fmt.Errorf("secret name is empty")
+	}
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid secret name: %s", name)
+	}
+
+	data, err := os.ReadFile(filepath.Join(p.Dir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+		}
+		return nil, fmt.Errorf("failed to read secret %s: %w", name, err)
+	}
+
+	if trimmed, ok := bytes.CutSuffix(data, []byte("\n")); ok {
+		data, _ = bytes.CutSuffix(trimmed, []byte("\r"))
+	}
+	return data, nil
+}
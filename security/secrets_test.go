@@ -0,0 +1,57 @@
+package security_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestEnvSecretProvider(t *testing.T) {
+	t.Setenv("TEST_APP_DB_PASSWORD", "hunter2")
+
+	var provider security.SecretProvider = security.NewEnvSecretProvider("TEST_APP_")
+
+	secret, err := provider.GetSecret("DB_PASSWORD")
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, secret, []byte("hunter2"))
+
+	_, err = provider.GetSecret("MISSING_SECRET")
+	tst.AssertErrorIs(t, err, security.ErrSecretNotFound)
+
+	_, err = provider.GetSecret("")
+	tst.AssertErrorContains(t, err, "secret name is empty")
+}
+
+func TestFileSecretProvider(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "api_key"), []byte("s3cr3t\n"), 0o600)
+	tst.RequireNoError(t, err)
+
+	var provider security.SecretProvider = security.NewFileSecretProvider(dir)
+
+	secret, err := provider.GetSecret("api_key")
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, secret, []byte("s3cr3t"))
+
+	// Only one trailing newline is trimmed
+	err = os.WriteFile(filepath.Join(dir, "multi"), []byte("line1\r\nline2\n\n"), 0o600)
+	tst.RequireNoError(t, err)
+	secret, err = provider.GetSecret("multi")
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, secret, []byte("line1\r\nline2\n"))
+
+	err = os.WriteFile(filepath.Join(dir, "binary"), []byte{0x01, '\r', '\r', '\n'}, 0o600)
+	tst.RequireNoError(t, err)
+	secret, err = provider.GetSecret("binary")
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, secret, []byte{0x01, '\r'})
+
+	_, err = provider.GetSecret("missing")
+	tst.AssertErrorIs(t, err, security.ErrSecretNotFound)
+
+	_, err = provider.GetSecret("../api_key")
+	tst.AssertErrorContains(t, err, "invalid secret name")
+}
@@ -0,0 +1,80 @@
+package security
+
+import "runtime"
+
+// SecureBytes holds sensitive bytes, such as a derived key or a decrypted
+// secret, and lets the caller wipe them once they are no longer needed.
+//
+// Wiping is best-effort: the Go runtime may have copied the data (for
+// example when a slice grows or a string is converted), and those copies
+// cannot be reached. Zero still shortens the time the bytes stay readable in
+// memory dumps, core files, and swap.
+type SecureBytes struct {
+	b []byte
+}
+
+// NewSecureBytes wraps b without copying it. The SecureBytes takes ownership
+// of b, so Zero overwrites the caller's backing array.
+func NewSecureBytes(b []byte) *SecureBytes {
+	return &SecureBytes{b: b}
+}
+
+// Bytes returns the wrapped bytes without copying them. The slice must not
+// be used after Zero is called. It returns nil once the bytes are zeroed.
+func (s *SecureBytes) Bytes() []byte {
+	if s == nil {
+		return nil
+	}
+	return s.b
+}
+
+// Len returns the number of wrapped bytes, or 0 once they are zeroed.
+func (s *SecureBytes) Len() int {
+	return len(s.Bytes())
+}
+
+// Zero overwrites the backing array with zeros and releases it. It is safe to
+// call more than once and on a nil SecureBytes.
+func (s *SecureBytes) Zero() {
+	if s == nil {
+		return
+	}
+	wipe(s.b)
+	s.b = nil
+}
+
+// wipe overwrites b with zeros. KeepAlive stops the compiler from treating
+// the writes as dead stores to memory that is never read again.
+func wipe(b []byte) {
+	clear(b)
+	runtime.KeepAlive(b)
+}
+
+// GenerateRandomKeySecure is like GenerateRandomKey but returns the key as
+// SecureBytes so it can be zeroed after use.
+func GenerateRandomKeySecure(length int) (*SecureBytes, error) {
+	key, err := GenerateRandomKey(length)
+	if err != nil {
+		return nil, err
+	}
+	return NewSecureBytes(key), nil
+}
+
+// DeriveKeySecure is like DeriveKey but returns the key as SecureBytes so it
+// can be zeroed after use. The salt is not secret and is returned as is.
+func DeriveKeySecure(password string, saltSize, iterations, keyLen int) (*SecureBytes, []byte, error) {
+	key, salt, err := DeriveKey(password, saltSize, iterations, keyLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewSecureBytes(key), salt, nil
+}
+
+// WithSecureBytes allocates an n-byte buffer, passes it to fn, and zeroes it
+// when fn returns, even if fn panics. fn must not retain the buffer. It
+// returns the error returned by fn.
+func WithSecureBytes(n int, fn func([]byte) error) error {
+	buf := make([]byte, n)
+	defer wipe(buf)
+	return fn(buf)
+}
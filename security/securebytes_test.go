@@ -0,0 +1,76 @@
+package security_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestSecureBytesZero(t *testing.T) {
+	raw := []byte("super-secret-key")
+	backing := raw[:cap(raw)]
+	sb := security.NewSecureBytes(raw)
+
+	tst.AssertDeepEqual(t, sb.Bytes(), []byte("super-secret-key"))
+	tst.AssertEqual(t, sb.Len(), 16)
+
+	sb.Zero()
+	tst.AssertTrue(t, bytes.Equal(backing, make([]byte, len(backing))), "backing array should be zeroed")
+	tst.AssertNil(t, sb.Bytes())
+	tst.AssertEqual(t, sb.Len(), 0)
+
+	// Zero is idempotent and nil-safe
+	sb.Zero()
+	var nilSB *security.SecureBytes
+	nilSB.Zero()
+	tst.AssertEqual(t, nilSB.Len(), 0)
+}
+
+func TestGenerateRandomKeySecure(t *testing.T) {
+	sb, err := security.GenerateRandomKeySecure(32)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, sb.Len(), 32)
+
+	key := sb.Bytes()
+	sb.Zero()
+	tst.AssertTrue(t, bytes.Equal(key, make([]byte, 32)), "key should be zeroed")
+}
+
+func TestDeriveKeySecure(t *testing.T) {
+	sb, salt, err := security.DeriveKeySecure("password", 16, 1000, 32)
+	tst.RequireNoError(t, err)
+	defer sb.Zero()
+
+	tst.AssertEqual(t, len(salt), 16)
+	tst.AssertDeepEqual(t, sb.Bytes(), security.DeriveKeyWithSalt("password", salt, 1000, 32))
+}
+
+func TestWithSecureBytes(t *testing.T) {
+	var seen []byte
+	errBoom := errors.New("boom")
+	err := security.WithSecureBytes(8, func(buf []byte) error {
+		seen = buf
+		copy(buf, "secret!!")
+		return errBoom
+	})
+	tst.AssertErrorIs(t, err, errBoom)
+	tst.AssertTrue(t, bytes.Equal(seen, make([]byte, 8)), "buffer should be zeroed after fn returns")
+}
+
+func TestWithSecureBytesPanic(t *testing.T) {
+	var seen []byte
+	func() {
+		defer func() {
+			tst.AssertNotNil(t, recover())
+		}()
+		_ = security.WithSecureBytes(8, func(buf []byte) error {
+			seen = buf
+			copy(buf, "secret!!")
+			panic("fn failed")
+		})
+	}()
+	tst.AssertTrue(t, bytes.Equal(seen, make([]byte, 8)), "buffer should be zeroed after fn panics")
+}
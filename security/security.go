@@ -3,18 +3,24 @@ package security
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/subtle"
+	"encoding/base32"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"strings"
 
 	"crypto/sha256"
 
 	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/hkdf"
 	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
 )
 
 var (
@@ -24,6 +30,10 @@ var (
 	ErrInvalidCiphertext = errors.New("invalid ciphertext")
 	// ErrDecryptionFailed is returned when decryption fails
 	ErrDecryptionFailed = errors.New("decryption failed")
+	// ErrLengthMismatch is returned when two inputs must have equal length but do not
+	ErrLengthMismatch = errors.New("length mismatch")
+	// ErrInvalidRange is returned when a random number range is empty
+	ErrInvalidRange = errors.New("invalid range")
 )
 
 // AES-GCM Encryption/Decryption
@@ -31,6 +41,60 @@ var (
 // Encrypt encrypts plaintext using AES-GCM with the provided key.
 // Returns the encrypted data with nonce prepended.
 func Encrypt(key []byte, plaintext []byte) ([]byte, error) {
+	return EncryptWithAAD(key, plaintext, nil)
+}
+
+// Decrypt decrypts ciphertext using AES-GCM with the provided key.
+// Expects the nonce to be prepended to the ciphertext.
+func Decrypt(key []byte, ciphertext []byte) ([]byte, error) {
+	return DecryptWithAAD(key, ciphertext, nil)
+}
+
+// EncryptWithAAD is like Encrypt but authenticates additional data aad along
+// with the ciphertext. aad is not encrypted or included in the output; the
+// same aad must be passed to DecryptWithAAD. Use it to bind a ciphertext to
+// its context, such as a tenant or record ID, when one key protects many
+// records.
+func EncryptWithAAD(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	ciphertext := gcm.Seal(nonce, nonce, plaintext, aad)
+	return ciphertext, nil
+}
+
+// DecryptWithAAD decrypts ciphertext produced by EncryptWithAAD. It returns
+// ErrDecryptionFailed if aad differs from the data used at encryption time,
+// even when the key is correct.
+func DecryptWithAAD(key, ciphertext, aad []byte) ([]byte, error) {
+	gcm, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := gcm.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, ErrInvalidCiphertext
+	}
+
+	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	return plaintext, nil
+}
+
+// newAESGCM returns an AES-GCM AEAD for a 16, 24, or 32-byte key.
+func newAESGCM(key []byte) (cipher.AEAD, error) {
 	if len(key) != 16 && len(key) != 24 && len(key) != 32 {
 		return nil, ErrInvalidKeySize
 	}
@@ -44,40 +108,55 @@ func Encrypt(key []byte, plaintext []byte) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GCM: %w", err)
 	}
+	return gcm, nil
+}
 
-	nonce := make([]byte, gcm.NonceSize())
+// ChaCha20-Poly1305 Encryption/Decryption
+
+// ChaCha20NonceSize is the size in bytes of the random nonce prepended to
+// ChaCha20-Poly1305 ciphertexts.
+const ChaCha20NonceSize = chacha20poly1305.NonceSize
+
+// EncryptChaCha20 encrypts plaintext using ChaCha20-Poly1305 with the provided
+// 32-byte key. It is faster than AES-GCM on platforms without AES hardware
+// acceleration. Returns the encrypted data with nonce prepended.
+func EncryptChaCha20(key []byte, plaintext []byte) ([]byte, error) {
+	if len(key) != chacha20poly1305.KeySize {
+		return nil, ErrInvalidKeySize
+	}
+
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	nonce := make([]byte, ChaCha20NonceSize)
 	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return nil, fmt.Errorf("failed to generate nonce: %w", err)
 	}
 
-	ciphertext := gcm.Seal(nonce, nonce, plaintext, nil)
+	ciphertext := aead.Seal(nonce, nonce, plaintext, nil)
 	return ciphertext, nil
 }
 
-// Decrypt decrypts ciphertext using AES-GCM with the provided key.
-// Expects the nonce to be prepended to the ciphertext.
-func Decrypt(key []byte, ciphertext []byte) ([]byte, error) {
-	if len(key) != 16 && len(key) != 24 && len(key) != 32 {
+// DecryptChaCha20 decrypts ciphertext using ChaCha20-Poly1305 with the
+// provided 32-byte key. Expects the nonce to be prepended to the ciphertext.
+func DecryptChaCha20(key []byte, ciphertext []byte) ([]byte, error) {
+	if len(key) != chacha20poly1305.KeySize {
 		return nil, ErrInvalidKeySize
 	}
 
-	block, err := aes.NewCipher(key)
+	aead, err := chacha20poly1305.New(key)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cipher: %w", err)
 	}
 
-	gcm, err := cipher.NewGCM(block)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create GCM: %w", err)
-	}
-
-	nonceSize := gcm.NonceSize()
-	if len(ciphertext) < nonceSize {
+	if len(ciphertext) < ChaCha20NonceSize {
 		return nil, ErrInvalidCiphertext
 	}
 
-	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	nonce, ciphertext := ciphertext[:ChaCha20NonceSize], ciphertext[ChaCha20NonceSize:]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
 	if err != nil {
 		return nil, ErrDecryptionFailed
 	}
@@ -103,7 +182,49 @@ func DeriveKey(password string, saltSize, iterations, keyLen int) (key []byte, s
 
 // DeriveKeyWithSalt derives a key from a password and existing salt using PBKDF2 with SHA-256.
 func DeriveKeyWithSalt(password string, salt []byte, iterations, keyLen int) []byte {
-	return pbkdf2.Key([]byte(password), salt, iterations, keyLen, sha256.New)
+	return DeriveKeyWithHash(password, salt, iterations, keyLen, sha256.New)
+}
+
+// DeriveKeyWithHash is like DeriveKeyWithSalt but uses hashFn as the PBKDF2
+// PRF, e.g. sha512.New, or sha1.New for compatibility with systems that
+// standardized on it. Keys derived with different hashes are unrelated.
+func DeriveKeyWithHash(password string, salt []byte, iterations, keyLen int, hashFn func() hash.Hash) []byte {
+	return pbkdf2.Key([]byte(password), salt, iterations, keyLen, hashFn)
+}
+
+// scrypt Key Derivation
+
+// DeriveKeyScrypt derives a key from a password using scrypt with a new random
+// salt of saltSize bytes. Unlike PBKDF2, scrypt is memory-hard, which makes
+// brute-forcing passwords on GPUs and ASICs expensive. N is the CPU/memory cost
+// and must be a power of two greater than 1 (recommended: 32768 or higher);
+// r is the block size (recommended: 8) and p the parallelization (recommended:
+// 1). Memory use is about 128*N*r bytes.
+func DeriveKeyScrypt(password string, saltSize, N, r, p, keyLen int) (key []byte, salt []byte, err error) {
+	salt = make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key, err = DeriveKeyScryptWithSalt(password, salt, N, r, p, keyLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, salt, nil
+}
+
+// DeriveKeyScryptWithSalt derives a key from a password and existing salt
+// using scrypt. See DeriveKeyScrypt for the parameters.
+func DeriveKeyScryptWithSalt(password string, salt []byte, N, r, p, keyLen int) ([]byte, error) {
+	if N <= 1 || N&(N-1) != 0 {
+		return nil, fmt.Errorf("invalid scrypt cost N=%d: must be a power of two greater than 1", N)
+	}
+
+	key, err := scrypt.Key([]byte(password), salt, N, r, p, keyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	return key, nil
 }
 
 // HKDF Key Derivation
@@ -143,6 +264,31 @@ func DeriveKeyHKDF(masterKey []byte, salt, info string, keyLength int) ([]byte,
 	return key, nil
 }
 
+// HKDFExpand derives a subkey from an existing pseudorandom key (PRK) using only
+// the HKDF-Expand step with SHA-256.
+//
+// Use DeriveKeyHKDF (extract then expand) when the input keying material is not
+// uniformly random, such as a Diffie-Hellman shared secret or a passphrase-like
+// value. Use HKDFExpand when the input is already a uniformly random key of at
+// least 32 bytes (e.g., from GenerateRandomKey or a previous HKDF extraction);
+// many independent subkeys can then be derived from it by varying info.
+func HKDFExpand(prk []byte, info string, keyLen int) ([]byte, error) {
+	if len(prk) < sha256.Size {
+		return nil, ErrInvalidKeySize
+	}
+	if keyLen <= 0 {
+		return nil, fmt.Errorf("invalid key length: %d", keyLen)
+	}
+
+	reader := hkdf.Expand(sha256.New, prk, []byte(info))
+	key := make([]byte, keyLen)
+	if _, err := io.ReadFull(reader, key); err != nil {
+		return nil, fmt.Errorf("failed to expand key: %w", err)
+	}
+
+	return key, nil
+}
+
 // Random Key Generation
 
 // GenerateRandomKey generates a cryptographically secure random key of the specified length.
@@ -192,6 +338,65 @@ func VerifyPassword(password, hash string) bool {
 	return err == nil
 }
 
+// bcryptPrefix is the algorithm prefix of hashes produced by HashPassword.
+const bcryptPrefix = "$2a$"
+
+// PasswordHashCost returns the bcrypt cost factor stored in hash.
+func PasswordHashCost(hash string) (int, error) {
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read password hash cost: %w", err)
+	}
+	return cost, nil
+}
+
+// PasswordNeedsRehash reports whether hash should be replaced by a fresh hash
+// at desiredCost, because its cost is lower or it was not produced by
+// HashPassword's bcrypt variant. Call it after VerifyPassword succeeds, while
+// the plaintext password is still available to re-hash.
+func PasswordNeedsRehash(hash string, desiredCost int) (bool, error) {
+	if !strings.HasPrefix(hash, bcryptPrefix) {
+		return true, nil
+	}
+	cost, err := PasswordHashCost(hash)
+	if err != nil {
+		return false, err
+	}
+	return cost < desiredCost, nil
+}
+
+// HashPasswordPeppered hashes a password with bcrypt after first applying
+// HMAC-SHA256 keyed with pepper, a server-side secret stored outside the
+// database (e.g., in a secret manager). A leaked hash cannot be cracked
+// without the pepper. The HMAC output is base64 encoded before hashing, which
+// keeps it within bcrypt's 72-byte input limit for passwords of any length.
+//
+// Rotating the pepper invalidates every existing hash. To rotate, keep the old
+// pepper available, verify with the new pepper first and fall back to the old
+// one, and re-hash with the new pepper after a successful login.
+func HashPasswordPeppered(password string, pepper []byte) (string, error) {
+	if len(pepper) == 0 {
+		return "", ErrEmptyKey
+	}
+	return HashPassword(pepperPassword(password, pepper))
+}
+
+// VerifyPasswordPeppered compares a plaintext password with a hash produced by
+// HashPasswordPeppered using the same pepper.
+func VerifyPasswordPeppered(password, hash string, pepper []byte) bool {
+	if len(pepper) == 0 {
+		return false
+	}
+	return VerifyPassword(pepperPassword(password, pepper), hash)
+}
+
+// pepperPassword returns the base64-encoded HMAC-SHA256 of password keyed with pepper.
+func pepperPassword(password string, pepper []byte) string {
+	mac := hmac.New(sha256.New, pepper)
+	mac.Write([]byte(password))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
 // Constant-time Secure Comparison
 
 // SecureCompare performs a constant-time comparison of two byte slices.
@@ -206,6 +411,58 @@ func SecureCompareString(a, b string) bool {
 	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
 }
 
+// ConstantTimeSelect returns a copy of a if cond is 1 and a copy of b if cond
+// is 0, without branching on cond. The behavior is undefined if cond takes any
+// other value. Like ConstantTimeCopy, it returns ErrLengthMismatch if a and b
+// have different lengths.
+func ConstantTimeSelect(cond int, a, b []byte) ([]byte, error) {
+	if len(a) != len(b) {
+		return nil, ErrLengthMismatch
+	}
+	out := make([]byte, len(b))
+	copy(out, b)
+	subtle.ConstantTimeCopy(cond, out, a)
+	return out, nil
+}
+
+// ConstantTimeByteEq returns 1 if x == y and 0 otherwise, in constant time.
+func ConstantTimeByteEq(x, y byte) int {
+	return subtle.ConstantTimeByteEq(x, y)
+}
+
+// ConstantTimeCopy copies src into dst if cond is 1 and leaves dst unchanged
+// if cond is 0, in constant time. Unlike subtle.ConstantTimeCopy, it returns
+// ErrLengthMismatch instead of panicking when the slices differ in length.
+func ConstantTimeCopy(cond int, dst, src []byte) error {
+	if len(dst) != len(src) {
+		return ErrLengthMismatch
+	}
+	subtle.ConstantTimeCopy(cond, dst, src)
+	return nil
+}
+
+// ConstantTimeLookup returns the value stored under key in table, such as
+// the secret for a presented token, without the timing leaks of a map lookup.
+// Every entry is visited and keys are compared by their SHA-256 digests, so
+// the work depends only on the size of the table, not on whether or where key
+// is present or how long it is. The tradeoff is O(n) hashing per lookup
+// instead of O(1); keep tables small or use it only where keys are secret.
+func ConstantTimeLookup(table map[string][]byte, key string) ([]byte, bool) {
+	target := sha256.Sum256([]byte(key))
+
+	var value []byte
+	found := 0
+	for k, v := range table {
+		digest := sha256.Sum256([]byte(k))
+		match := subtle.ConstantTimeCompare(digest[:], target[:])
+		// Select by indexing rather than branching on match; keys are
+		// unique, so at most one entry matches
+		value = [2][]byte{value, v}[match]
+		found |= match
+	}
+	return value, found == 1
+}
+
 // Base64 Encoding/Decoding
 
 // EncodeBase64 encodes data to base64 string using standard encoding.
@@ -235,3 +492,36 @@ func DecodeBase64URL(encoded string) ([]byte, error) {
 	}
 	return decoded, nil
 }
+
+// Base32 Encoding/Decoding
+
+// EncodeBase32 encodes data to base32 string using the standard RFC 4648 alphabet.
+func EncodeBase32(data []byte) string {
+	return base32.StdEncoding.EncodeToString(data)
+}
+
+// DecodeBase32 decodes a base32 string using the standard RFC 4648 alphabet.
+func DecodeBase32(encoded string) ([]byte, error) {
+	decoded, err := base32.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base32: %w", err)
+	}
+	return decoded, nil
+}
+
+// EncodeBase32NoPadding encodes data to base32 string without "=" padding,
+// the form used for TOTP secrets in otpauth:// URIs. The standard alphabet
+// is already URL-safe, so this is also the variant to use in URLs.
+func EncodeBase32NoPadding(data []byte) string {
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(data)
+}
+
+// DecodeBase32NoPadding decodes an unpadded base32 string using the standard
+// RFC 4648 alphabet.
+func DecodeBase32NoPadding(encoded string) ([]byte, error) {
+	decoded, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base32 without padding: %w", err)
+	}
+	return decoded, nil
+}
@@ -2,9 +2,15 @@ package security_test
 
 import (
 	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/julianstephens/go-utils/security"
 	tst "github.com/julianstephens/go-utils/tests"
@@ -93,6 +99,71 @@ func TestDecryptWithWrongKey(t *testing.T) {
 	tst.AssertTrue(t, err == security.ErrDecryptionFailed, "Should return ErrDecryptionFailed")
 }
 
+func TestEncryptDecryptWithAAD(t *testing.T) {
+	key, err := security.GenerateAESKey(32)
+	tst.RequireNoError(t, err)
+
+	plaintext := []byte("tenant record")
+	aad := []byte("tenant:42")
+
+	ciphertext, err := security.EncryptWithAAD(key, plaintext, aad)
+	tst.RequireNoError(t, err)
+
+	decrypted, err := security.DecryptWithAAD(key, ciphertext, aad)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, decrypted, plaintext)
+
+	// The right key with the wrong context must fail
+	_, err = security.DecryptWithAAD(key, ciphertext, []byte("tenant:43"))
+	tst.AssertTrue(t, err == security.ErrDecryptionFailed, "Should return ErrDecryptionFailed for mismatched AAD")
+	_, err = security.Decrypt(key, ciphertext)
+	tst.AssertTrue(t, err == security.ErrDecryptionFailed, "Should return ErrDecryptionFailed when AAD is omitted")
+
+	// Encrypt/Decrypt are the nil-AAD case
+	ciphertext, err = security.Encrypt(key, plaintext)
+	tst.RequireNoError(t, err)
+	decrypted, err = security.DecryptWithAAD(key, ciphertext, nil)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, decrypted, plaintext)
+}
+
+func TestEncryptDecryptChaCha20(t *testing.T) {
+	key, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+
+	plaintext := []byte("Hello, World! This is a test message.")
+
+	ciphertext, err := security.EncryptChaCha20(key, plaintext)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, len(ciphertext), security.ChaCha20NonceSize+len(plaintext)+16)
+
+	decrypted, err := security.DecryptChaCha20(key, ciphertext)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, decrypted, plaintext)
+
+	_, err = security.EncryptChaCha20(key[:16], plaintext)
+	tst.AssertTrue(t, err == security.ErrInvalidKeySize, "Should return ErrInvalidKeySize for a 16-byte key")
+	_, err = security.DecryptChaCha20(key, ciphertext[:security.ChaCha20NonceSize-1])
+	tst.AssertTrue(t, err == security.ErrInvalidCiphertext, "Should return ErrInvalidCiphertext")
+}
+
+func TestDecryptChaCha20WithWrongKey(t *testing.T) {
+	key1, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+	key2, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+
+	plaintext := []byte("secret message")
+
+	// Encrypt with key1
+	ciphertext, err := security.EncryptChaCha20(key1, plaintext)
+	tst.RequireNoError(t, err)
+
+	// Try to decrypt with key2
+	_, err = security.DecryptChaCha20(key2, ciphertext)
+	tst.AssertTrue(t, err == security.ErrDecryptionFailed, "Should return ErrDecryptionFailed")
+}
+
 func TestEncryptDecryptEmptyData(t *testing.T) {
 	key, err := security.GenerateAESKey(32)
 	tst.RequireNoError(t, err)
@@ -166,6 +237,71 @@ func TestDeriveKeyDifferentSalts(t *testing.T) {
 	tst.AssertFalse(t, bytes.Equal(key1, key2), "Different salts should produce different keys")
 }
 
+func TestDeriveKeyWithHash(t *testing.T) {
+	password := "password"
+	salt := []byte("salt")
+
+	// RFC 6070 PBKDF2-HMAC-SHA1 test vector
+	key := security.DeriveKeyWithHash(password, salt, 2, 20, sha1.New)
+	tst.AssertEqual(t, hex.EncodeToString(key), "ea6c014dc72d6f8ccd1ed92ace1d41f0d8de8957")
+
+	// The SHA-256 default matches DeriveKeyWithSalt
+	sha256Key := security.DeriveKeyWithHash(password, salt, 1000, 32, sha256.New)
+	tst.AssertDeepEqual(t, sha256Key, security.DeriveKeyWithSalt(password, salt, 1000, 32))
+
+	sha512Key := security.DeriveKeyWithHash(password, salt, 1000, 32, sha512.New)
+	tst.AssertDeepEqual(t, sha512Key, security.DeriveKeyWithHash(password, salt, 1000, 32, sha512.New))
+	tst.AssertEqual(t, len(sha512Key), 32)
+	tst.AssertFalse(t, bytes.Equal(sha256Key, sha512Key), "SHA-256 and SHA-512 should derive different keys")
+}
+
+func TestDeriveKeyScryptWithSalt(t *testing.T) {
+	password := "my_secure_password"
+	salt := []byte("fixed_salt_16byt")
+
+	key1, err := security.DeriveKeyScryptWithSalt(password, salt, 1024, 8, 1, 32)
+	tst.RequireNoError(t, err)
+	key2, err := security.DeriveKeyScryptWithSalt(password, salt, 1024, 8, 1, 32)
+	tst.RequireNoError(t, err)
+
+	tst.AssertTrue(t, len(key1) == 32, "Key length should match requested length")
+	tst.AssertDeepEqual(t, key1, key2)
+
+	other, err := security.DeriveKeyScryptWithSalt("other_password", salt, 1024, 8, 1, 32)
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, bytes.Equal(key1, other), "Different passwords should produce different keys")
+
+	// RFC 7914 test vector
+	key, err := security.DeriveKeyScryptWithSalt("password", []byte("NaCl"), 1024, 8, 16, 64)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, hex.EncodeToString(key), "fdbabe1c9d3472007856e7190d01e9fe7c6ad7cbc8237830e77376634b373162"+
+		"2eaf30d92e22a3886ff109279d9830dac727afb94a83ee6d8360cbdfa2cc0640")
+}
+
+func TestDeriveKeyScrypt(t *testing.T) {
+	key1, salt1, err := security.DeriveKeyScrypt("same_password", 16, 1024, 8, 1, 32)
+	tst.RequireNoError(t, err)
+	key2, salt2, err := security.DeriveKeyScrypt("same_password", 16, 1024, 8, 1, 32)
+	tst.RequireNoError(t, err)
+
+	tst.AssertEqual(t, len(salt1), 16)
+	tst.AssertFalse(t, bytes.Equal(salt1, salt2), "Different calls should produce different salts")
+	tst.AssertFalse(t, bytes.Equal(key1, key2), "Different salts should produce different keys")
+
+	sameKey, err := security.DeriveKeyScryptWithSalt("same_password", salt1, 1024, 8, 1, 32)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, key1, sameKey)
+}
+
+func TestDeriveKeyScryptInvalidN(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 1000, -16} {
+		t.Run(fmt.Sprintf("N%d", n), func(t *testing.T) {
+			_, _, err := security.DeriveKeyScrypt("password", 16, n, 8, 1, 32)
+			tst.AssertErrorContains(t, err, "must be a power of two greater than 1")
+		})
+	}
+}
+
 // Test Random Key Generation
 
 func TestGenerateRandomKey(t *testing.T) {
@@ -275,6 +411,70 @@ func TestVerifyPasswordDifferentHashes(t *testing.T) {
 	tst.AssertTrue(t, security.VerifyPassword(password, hash2), "Password should verify against second hash")
 }
 
+func TestPasswordHashCost(t *testing.T) {
+	hash, err := security.HashPasswordWithCost("password", bcrypt.MinCost)
+	tst.RequireNoError(t, err)
+
+	cost, err := security.PasswordHashCost(hash)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, cost, bcrypt.MinCost)
+
+	_, err = security.PasswordHashCost("not-a-hash")
+	tst.AssertErrorContains(t, err, "failed to read password hash cost")
+}
+
+func TestPasswordNeedsRehash(t *testing.T) {
+	hash, err := security.HashPasswordWithCost("password", bcrypt.MinCost)
+	tst.RequireNoError(t, err)
+
+	needs, err := security.PasswordNeedsRehash(hash, bcrypt.MinCost)
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, needs, "Hash at the desired cost should not need rehashing")
+
+	needs, err = security.PasswordNeedsRehash(hash, bcrypt.MinCost+1)
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, needs, "Hash below the desired cost should need rehashing")
+
+	needs, err = security.PasswordNeedsRehash("$2y$"+hash[4:], bcrypt.MinCost)
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, needs, "Hash with a different algorithm prefix should need rehashing")
+
+	_, err = security.PasswordNeedsRehash("$2a$xx$invalid", bcrypt.MinCost)
+	tst.AssertNotNil(t, err)
+}
+
+func TestHashPasswordPeppered(t *testing.T) {
+	password := "my_secure_password"
+	pepperA := []byte("pepper-a-0123456789")
+	pepperB := []byte("pepper-b-0123456789")
+
+	hashA, err := security.HashPasswordPeppered(password, pepperA)
+	tst.RequireNoError(t, err)
+	hashB, err := security.HashPasswordPeppered(password, pepperB)
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, hashA == hashB, "Different peppers should produce different hashes")
+
+	tst.AssertTrue(t, security.VerifyPasswordPeppered(password, hashA, pepperA), "Correct pepper should verify")
+	tst.AssertTrue(t, security.VerifyPasswordPeppered(password, hashB, pepperB), "Correct pepper should verify")
+	tst.AssertFalse(t, security.VerifyPasswordPeppered(password, hashA, pepperB), "Wrong pepper should not verify")
+	tst.AssertFalse(t, security.VerifyPasswordPeppered("wrong", hashA, pepperA), "Wrong password should not verify")
+	tst.AssertFalse(t, security.VerifyPassword(password, hashA), "Peppered hash should not verify without pepper")
+	tst.AssertFalse(t, security.VerifyPasswordPeppered(password, hashA, nil), "Empty pepper should not verify")
+
+	_, err = security.HashPasswordPeppered(password, nil)
+	tst.AssertErrorIs(t, err, security.ErrEmptyKey)
+}
+
+func TestHashPasswordPepperedLongPassword(t *testing.T) {
+	// Passwords longer than bcrypt's 72-byte limit are still fully significant
+	pepper := []byte("pepper")
+	long := strings.Repeat("a", 100)
+
+	hash, err := security.HashPasswordPeppered(long+"x", pepper)
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, security.VerifyPasswordPeppered(long+"y", hash, pepper), "Suffix beyond 72 bytes should matter")
+}
+
 // Test Constant-time Secure Comparison
 
 func TestSecureCompare(t *testing.T) {
@@ -327,6 +527,96 @@ func TestSecureCompareStringEmpty(t *testing.T) {
 	tst.AssertFalse(t, security.SecureCompareString(a, c), "Empty and non-empty strings should not be equal")
 }
 
+func TestConstantTimeSelect(t *testing.T) {
+	a := []byte("aaaa")
+	b := []byte("bbbb")
+
+	got, err := security.ConstantTimeSelect(1, a, b)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, got, a)
+	got, err = security.ConstantTimeSelect(0, a, b)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, got, b)
+
+	// Inputs must not be modified
+	tst.AssertDeepEqual(t, a, []byte("aaaa"))
+	tst.AssertDeepEqual(t, b, []byte("bbbb"))
+
+	_, err = security.ConstantTimeSelect(1, []byte("a"), []byte("bb"))
+	tst.AssertErrorIs(t, err, security.ErrLengthMismatch)
+}
+
+func TestConstantTimeByteEq(t *testing.T) {
+	tst.AssertEqual(t, security.ConstantTimeByteEq('x', 'x'), 1)
+	tst.AssertEqual(t, security.ConstantTimeByteEq('x', 'y'), 0)
+}
+
+func TestConstantTimeLookup(t *testing.T) {
+	table := map[string][]byte{
+		"tok_alpha": []byte("secret-a"),
+		"tok_beta":  []byte("secret-b"),
+		"":          []byte("empty-key"),
+	}
+
+	value, ok := security.ConstantTimeLookup(table, "tok_beta")
+	tst.AssertTrue(t, ok, "present key should be found")
+	tst.AssertDeepEqual(t, value, []byte("secret-b"))
+
+	value, ok = security.ConstantTimeLookup(table, "")
+	tst.AssertTrue(t, ok, "empty key should be found when present")
+	tst.AssertDeepEqual(t, value, []byte("empty-key"))
+
+	value, ok = security.ConstantTimeLookup(table, "tok_gamma")
+	tst.AssertFalse(t, ok, "absent key should not be found")
+	tst.AssertNil(t, value)
+
+	_, ok = security.ConstantTimeLookup(nil, "tok_alpha")
+	tst.AssertFalse(t, ok, "nil table should not find anything")
+}
+
+// TestConstantTimeLookupTiming compares wall-clock times, which is unreliable
+// under load or -race, so it only runs when GO_UTILS_TIMING_TESTS is set.
+func TestConstantTimeLookupTiming(t *testing.T) {
+	if os.Getenv("GO_UTILS_TIMING_TESTS") == "" {
+		t.Skip("set GO_UTILS_TIMING_TESTS=1 to run timing comparisons")
+	}
+
+	table := make(map[string][]byte, 1000)
+	for i := range 1000 {
+		table[fmt.Sprintf("token-%04d", i)] = []byte("secret")
+	}
+
+	measure := func(key string) time.Duration {
+		start := time.Now()
+		for range 100 {
+			security.ConstantTimeLookup(table, key)
+		}
+		return time.Since(start)
+	}
+	measure("warm-up")
+
+	// Both lookups visit every entry, so their cost should be comparable;
+	// the bound is loose to tolerate scheduler noise
+	present, absent := measure("token-0500"), measure("missing")
+	ratio := float64(present) / float64(absent)
+	tst.AssertTrue(t, ratio > 0.33 && ratio < 3,
+		fmt.Sprintf("present/absent lookup time ratio %.2f should be close to 1", ratio))
+}
+
+func TestConstantTimeCopy(t *testing.T) {
+	dst := []byte("0000")
+	src := []byte("1111")
+
+	tst.RequireNoError(t, security.ConstantTimeCopy(0, dst, src))
+	tst.AssertDeepEqual(t, dst, []byte("0000"))
+
+	tst.RequireNoError(t, security.ConstantTimeCopy(1, dst, src))
+	tst.AssertDeepEqual(t, dst, []byte("1111"))
+
+	err := security.ConstantTimeCopy(1, make([]byte, 2), src)
+	tst.AssertErrorIs(t, err, security.ErrLengthMismatch)
+}
+
 // Test Base64 Encoding/Decoding
 
 func TestEncodeDecodeBase64(t *testing.T) {
@@ -415,6 +705,58 @@ func TestBase64VsBase64URL(t *testing.T) {
 	tst.AssertDeepEqual(t, stdDecoded, urlDecoded)
 }
 
+// Test Base32 Encoding/Decoding
+
+func TestEncodeDecodeBase32(t *testing.T) {
+	// RFC 4648 section 10 test vector
+	tst.AssertEqual(t, security.EncodeBase32([]byte("foobar")), "MZXW6YTBOI======")
+	tst.AssertEqual(t, security.EncodeBase32NoPadding([]byte("foobar")), "MZXW6YTBOI")
+
+	data := []byte("Hello, World! This is a test message for base32 encoding.")
+
+	decoded, err := security.DecodeBase32(security.EncodeBase32(data))
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, decoded, data)
+
+	encoded := security.EncodeBase32NoPadding(data)
+	tst.AssertFalse(t, strings.Contains(encoded, "="), "Unpadded encoding should not contain padding")
+	decoded, err = security.DecodeBase32NoPadding(encoded)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, decoded, data)
+}
+
+func TestEncodeDecodeBase32Empty(t *testing.T) {
+	data := []byte("")
+
+	decoded, err := security.DecodeBase32(security.EncodeBase32(data))
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, decoded, data)
+
+	decoded, err = security.DecodeBase32NoPadding(security.EncodeBase32NoPadding(data))
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, decoded, data)
+}
+
+func TestDecodeBase32InvalidInput(t *testing.T) {
+	invalidInputs := []string{
+		"invalid base32!",
+		"MZXW6YTBOI======invalid",
+		"mzxw6ytboi======",
+		"MZXW6YTBO1======",
+	}
+
+	for _, input := range invalidInputs {
+		t.Run("Invalid_"+input[:min(10, len(input))], func(t *testing.T) {
+			_, err := security.DecodeBase32(input)
+			tst.AssertTrue(t, err != nil, "Should return error for invalid base32")
+		})
+	}
+
+	// Padded input is rejected by the unpadded decoder
+	_, err := security.DecodeBase32NoPadding("MZXW6YTBOI======")
+	tst.AssertTrue(t, err != nil, "Should return error for padded input")
+}
+
 // Integration Tests
 
 func TestEncryptDecryptWithDerivedKey(t *testing.T) {
@@ -535,3 +877,38 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+func TestHKDFExpand(t *testing.T) {
+	prk, err := security.GenerateRandomKey(32)
+	tst.RequireNoError(t, err)
+
+	encKey, err := security.HKDFExpand(prk, "encryption", 32)
+	tst.RequireNoError(t, err)
+	macKey, err := security.HKDFExpand(prk, "authentication", 32)
+	tst.RequireNoError(t, err)
+
+	tst.AssertEqual(t, len(encKey), 32)
+	tst.AssertFalse(t, bytes.Equal(encKey, macKey), "Different info should yield different keys")
+
+	again, err := security.HKDFExpand(prk, "encryption", 32)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, again, encKey)
+
+	// A shorter output is a prefix of the longer one for the same info
+	short, err := security.HKDFExpand(prk, "encryption", 16)
+	tst.RequireNoError(t, err)
+	tst.AssertDeepEqual(t, short, encKey[:16])
+}
+
+func TestHKDFExpandInvalidInput(t *testing.T) {
+	_, err := security.HKDFExpand(make([]byte, 16), "info", 32)
+	tst.AssertErrorIs(t, err, security.ErrInvalidKeySize)
+
+	prk := make([]byte, 32)
+	_, err = security.HKDFExpand(prk, "info", 0)
+	tst.AssertErrorContains(t, err, "invalid key length")
+
+	// HKDF-SHA256 can produce at most 255*32 bytes
+	_, err = security.HKDFExpand(prk, "info", 255*32+1)
+	tst.AssertErrorContains(t, err, "failed to expand key")
+}
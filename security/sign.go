@@ -0,0 +1,121 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// ErrEmptyKey is returned when a signing key is empty
+var ErrEmptyKey = errors.New("key cannot be empty")
+
+// Detached signatures
+//
+// A detached signature is stored separately from the data it signs (for
+// example, a file plus a ".sig" file), so the payload is left unchanged.
+
+// SignDetached returns an HMAC-SHA256 signature of message using key. The
+// signature is not embedded in message and can be stored alongside it.
+func SignDetached(key, message []byte) ([]byte, error) {
+	if len(key) == 0 {
+		return nil, ErrEmptyKey
+	}
+	return HMACSHA256(key, message), nil
+}
+
+// VerifyDetached reports whether sig is a valid HMAC-SHA256 signature of
+// message for key. The comparison is constant-time.
+func VerifyDetached(key, message, sig []byte) bool {
+	expected, err := SignDetached(key, message)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(expected, sig)
+}
+
+// HMACSHA256 returns the HMAC-SHA256 of message using key.
+func HMACSHA256(key, message []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(message)
+	return mac.Sum(nil)
+}
+
+// VerifyHMACSHA256 reports whether expectedMAC is the HMAC-SHA256 of message
+// using key, such as a webhook signature. The comparison is constant-time. It
+// returns false for an empty key, since anyone can compute such a MAC.
+func VerifyHMACSHA256(key, message, expectedMAC []byte) bool {
+	if len(key) == 0 {
+		return false
+	}
+	return SecureCompare(HMACSHA256(key, message), expectedMAC)
+}
+
+// VerifyHMACSHA256Hex is like VerifyHMACSHA256 but takes the expected MAC
+// hex-encoded, as most webhook providers send it. Upper and lower case hex are
+// accepted. It returns an error if hexMAC is not valid hex, and false for an
+// empty key.
+func VerifyHMACSHA256Hex(key, message []byte, hexMAC string) (bool, error) {
+	expected, err := hex.DecodeString(hexMAC)
+	if err != nil {
+		return false, fmt.Errorf("invalid hex MAC: %w", err)
+	}
+	return VerifyHMACSHA256(key, message, expected), nil
+}
+
+// HMACVerifier checks an HMAC-SHA256 signature over data streamed through it,
+// so large payloads can be verified without buffering them. Write the data
+// (for example with io.Copy), then call Verify.
+type HMACVerifier struct {
+	mac      hash.Hash
+	expected []byte
+}
+
+// NewHMACVerifier returns a verifier for data signed with key whose expected
+// HMAC-SHA256 is expectedMAC, as produced by SignDetached. With an empty key
+// Verify always returns false.
+func NewHMACVerifier(key, expectedMAC []byte) *HMACVerifier {
+	v := &HMACVerifier{expected: append([]byte(nil), expectedMAC...)}
+	if len(key) > 0 {
+		v.mac = hmac.New(sha256.New, key)
+	}
+	return v
+}
+
+// Write adds p to the data being verified. It never returns an error.
+func (v *HMACVerifier) Write(p []byte) (int, error) {
+	if v.mac != nil {
+		v.mac.Write(p)
+	}
+	return len(p), nil
+}
+
+// Verify reports whether the data written so far matches the expected MAC.
+// The comparison is constant-time. Call it once all data has been written.
+func (v *HMACVerifier) Verify() bool {
+	if v.mac == nil {
+		return false
+	}
+	return hmac.Equal(v.mac.Sum(nil), v.expected)
+}
+
+// SignEd25519 returns a detached Ed25519 signature of message. priv must be a
+// 64-byte Ed25519 private key.
+func SignEd25519(priv, message []byte) ([]byte, error) {
+	if len(priv) != ed25519.PrivateKeySize {
+		return nil, ErrInvalidKeySize
+	}
+	return ed25519.Sign(ed25519.PrivateKey(priv), message), nil
+}
+
+// VerifyEd25519 reports whether sig is a valid Ed25519 signature of message
+// for the 32-byte public key pub. Malformed keys or signatures return false.
+func VerifyEd25519(pub, message, sig []byte) bool {
+	if len(pub) != ed25519.PublicKeySize || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+	return ed25519.Verify(ed25519.PublicKey(pub), message, sig)
+}
@@ -0,0 +1,123 @@
+package security_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestSignDetached(t *testing.T) {
+	key := []byte("shared-secret")
+	message := []byte("release-v1.2.3.tar.gz contents")
+
+	sig, err := security.SignDetached(key, message)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, len(sig), 32)
+	tst.AssertTrue(t, security.VerifyDetached(key, message, sig), "valid signature should verify")
+
+	tampered := append([]byte{}, message...)
+	tampered[0] ^= 0xff
+	tst.AssertFalse(t, security.VerifyDetached(key, tampered, sig), "tampered message should not verify")
+	tst.AssertFalse(t, security.VerifyDetached([]byte("other-secret"), message, sig), "wrong key should not verify")
+	tst.AssertFalse(t, security.VerifyDetached(key, message, sig[:16]), "truncated signature should not verify")
+
+	_, err = security.SignDetached(nil, message)
+	tst.AssertErrorIs(t, err, security.ErrEmptyKey)
+	tst.AssertFalse(t, security.VerifyDetached(nil, message, sig), "empty key should not verify")
+}
+
+func TestHMACSHA256(t *testing.T) {
+	// RFC 4231 test case 2
+	key := []byte("Jefe")
+	message := []byte("what do ya want for nothing?")
+	const want = "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+
+	mac := security.HMACSHA256(key, message)
+	tst.AssertEqual(t, hex.EncodeToString(mac), want)
+	tst.AssertTrue(t, security.VerifyHMACSHA256(key, message, mac), "valid MAC should verify")
+	tst.AssertFalse(t, security.VerifyHMACSHA256(key, []byte("tampered"), mac), "tampered message should not verify")
+	tst.AssertFalse(t, security.VerifyHMACSHA256(key, message, mac[:16]), "truncated MAC should not verify")
+
+	ok, err := security.VerifyHMACSHA256Hex(key, message, want)
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, ok, "valid hex MAC should verify")
+
+	ok, err = security.VerifyHMACSHA256Hex(key, message, strings.ToUpper(want))
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, ok, "uppercase hex MAC should verify")
+
+	ok, err = security.VerifyHMACSHA256Hex([]byte("other"), message, want)
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, ok, "wrong key should not verify")
+
+	_, err = security.VerifyHMACSHA256Hex(key, message, "not-hex")
+	tst.AssertErrorContains(t, err, "invalid hex MAC")
+
+	// A MAC under an empty key proves nothing, so it never verifies
+	emptyMAC := security.HMACSHA256(nil, message)
+	tst.AssertFalse(t, security.VerifyHMACSHA256(nil, message, emptyMAC), "empty key should not verify")
+	ok, err = security.VerifyHMACSHA256Hex([]byte{}, message, hex.EncodeToString(emptyMAC))
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, ok, "empty key should not verify")
+}
+
+func TestHMACVerifier(t *testing.T) {
+	key := []byte("shared-secret")
+	data := make([]byte, 1<<20)
+	_, err := rand.Read(data)
+	tst.RequireNoError(t, err)
+
+	sig, err := security.SignDetached(key, data)
+	tst.RequireNoError(t, err)
+
+	v := security.NewHMACVerifier(key, sig)
+	n, err := io.Copy(v, bytes.NewReader(data))
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, n, int64(len(data)))
+	tst.AssertTrue(t, v.Verify(), "streamed data should verify")
+
+	altered := append([]byte{}, data...)
+	altered[len(altered)/2] ^= 0x01
+	v = security.NewHMACVerifier(key, sig)
+	_, err = io.Copy(v, bytes.NewReader(altered))
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, v.Verify(), "altered data should not verify")
+
+	v = security.NewHMACVerifier(key, sig)
+	_, err = io.Copy(v, bytes.NewReader(data[:len(data)-1]))
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, v.Verify(), "truncated data should not verify")
+
+	v = security.NewHMACVerifier(nil, sig)
+	_, err = v.Write(data)
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, v.Verify(), "empty key should not verify")
+}
+
+func TestSignEd25519(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	tst.RequireNoError(t, err)
+	message := []byte("payload")
+
+	sig, err := security.SignEd25519(priv, message)
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, security.VerifyEd25519(pub, message, sig), "valid signature should verify")
+	tst.AssertFalse(t, security.VerifyEd25519(pub, []byte("payloaD"), sig), "tampered message should not verify")
+
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, security.VerifyEd25519(otherPub, message, sig), "wrong public key should not verify")
+
+	// Malformed inputs return false or an error instead of panicking
+	tst.AssertFalse(t, security.VerifyEd25519(pub[:10], message, sig), "short public key")
+	tst.AssertFalse(t, security.VerifyEd25519(pub, message, sig[:10]), "short signature")
+	_, err = security.SignEd25519(priv[:10], message)
+	tst.AssertErrorIs(t, err, security.ErrInvalidKeySize)
+}
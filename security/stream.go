@@ -0,0 +1,245 @@
+package security
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// StreamFrameSize is the maximum plaintext size of one frame written by an
+// encrypting writer.
+const StreamFrameSize = 64 * 1024
+
+// streamLengthSize is the size of the big-endian length that prefixes each
+// sealed frame.
+const streamLengthSize = 4
+
+// Streaming encryption
+//
+// NewEncryptingWriter and NewDecryptingReader encrypt data too large to hold
+// in memory. The stream starts with a random base nonce, followed by frames of
+// up to StreamFrameSize plaintext bytes, each stored as its sealed length and
+// its AES-GCM ciphertext. Frame i is sealed with the base nonce XORed with i,
+// so frames cannot be reordered, and the last frame is authenticated as final,
+// so truncation at a frame boundary is detected.
+
+var (
+	streamFrameAAD = []byte{0}
+	streamFinalAAD = []byte{1}
+)
+
+// streamNonce returns the nonce for frame counter derived from base.
+func streamNonce(base []byte, counter uint64) []byte {
+	nonce := append([]byte(nil), base...)
+	tail := nonce[len(nonce)-8:]
+	binary.BigEndian.PutUint64(tail, binary.BigEndian.Uint64(tail)^counter)
+	return nonce
+}
+
+// encryptingWriter buffers plaintext and writes it as sealed frames.
+type encryptingWriter struct {
+	w       io.Writer
+	gcm     cipher.AEAD
+	base    []byte
+	counter uint64
+	buf     []byte
+	closed  bool
+	err     error
+}
+
+// NewEncryptingWriter returns a writer that encrypts everything written to it
+// with AES-GCM under key and writes the result to w in frames, so arbitrarily
+// large streams can be encrypted in constant memory. Close must be called to
+// flush the final frame; it does not close w. key must be 16, 24, or 32 bytes.
+func NewEncryptingWriter(w io.Writer, key []byte) (io.WriteCloser, error) {
+	gcm, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	base := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, base); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	if _, err := w.Write(base); err != nil {
+		return nil, fmt.Errorf("failed to write stream header: %w", err)
+	}
+
+	return &encryptingWriter{
+		w:    w,
+		gcm:  gcm,
+		base: base,
+		buf:  make([]byte, 0, StreamFrameSize),
+	}, nil
+}
+
+// Write encrypts p, writing a frame each time StreamFrameSize bytes have been
+// buffered.
+func (e *encryptingWriter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("write to closed encrypting writer")
+	}
+	if e.err != nil {
+		return 0, e.err
+	}
+
+	written := 0
+	for len(p) > 0 {
+		if len(e.buf) == StreamFrameSize {
+			if err := e.writeFrame(false); err != nil {
+				return written, err
+			}
+		}
+		n := min(len(p), StreamFrameSize-len(e.buf))
+		e.buf = append(e.buf, p[:n]...)
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+// Close writes the buffered data as the final frame. Closing more than once
+// has no effect.
+func (e *encryptingWriter) Close() error {
+	if e.closed {
+		return e.err
+	}
+	e.closed = true
+	if e.err != nil {
+		return e.err
+	}
+	return e.writeFrame(true)
+}
+
+// writeFrame seals the buffered plaintext and writes it to the underlying
+// writer.
+func (e *encryptingWriter) writeFrame(final bool) error {
+	aad := streamFrameAAD
+	if final {
+		aad = streamFinalAAD
+	}
+
+	frame := make([]byte, streamLengthSize, streamLengthSize+len(e.buf)+e.gcm.Overhead())
+	frame = e.gcm.Seal(frame, streamNonce(e.base, e.counter), e.buf, aad)
+	binary.BigEndian.PutUint32(frame, uint32(len(frame)-streamLengthSize))
+
+	if _, err := e.w.Write(frame); err != nil {
+		e.err = fmt.Errorf("failed to write frame: %w", err)
+		return e.err
+	}
+	e.counter++
+	e.buf = e.buf[:0]
+	return nil
+}
+
+// decryptingReader reads sealed frames and returns their plaintext.
+type decryptingReader struct {
+	r       io.Reader
+	gcm     cipher.AEAD
+	base    []byte
+	counter uint64
+	buf     []byte
+	done    bool
+	err     error
+}
+
+// NewDecryptingReader returns a reader that decrypts a stream produced by
+// NewEncryptingWriter. It reads the stream header from r immediately and
+// returns ErrInvalidCiphertext if it is missing. Reads return
+// ErrDecryptionFailed if a frame has been corrupted, reordered, or removed, or
+// if the stream was truncated; plaintext already returned is authentic.
+func NewDecryptingReader(r io.Reader, key []byte) (io.Reader, error) {
+	gcm, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	base := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(r, base); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, ErrInvalidCiphertext
+		}
+		return nil, fmt.Errorf("failed to read stream header: %w", err)
+	}
+
+	return &decryptingReader{r: r, gcm: gcm, base: base}, nil
+}
+
+// Read returns decrypted plaintext, reading and opening frames as needed.
+func (d *decryptingReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		if d.done {
+			d.err = d.checkTrailing()
+			continue
+		}
+		d.err = d.readFrame()
+	}
+
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+// readFrame reads and opens the next frame into buf.
+func (d *decryptingReader) readFrame() error {
+	var length [streamLengthSize]byte
+	if _, err := io.ReadFull(d.r, length[:]); err != nil {
+		return d.readError(err)
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size < uint32(d.gcm.Overhead()) || size > uint32(StreamFrameSize+d.gcm.Overhead()) {
+		return ErrDecryptionFailed
+	}
+
+	frame := make([]byte, size)
+	if _, err := io.ReadFull(d.r, frame); err != nil {
+		return d.readError(err)
+	}
+
+	// Open into a new buffer: a failed Open may clear its output, and the
+	// frame is needed again to try it as the final frame
+	nonce := streamNonce(d.base, d.counter)
+	plaintext, err := d.gcm.Open(nil, nonce, frame, streamFrameAAD)
+	if err != nil {
+		plaintext, err = d.gcm.Open(nil, nonce, frame, streamFinalAAD)
+		if err != nil {
+			return ErrDecryptionFailed
+		}
+		d.done = true
+	}
+	d.counter++
+	d.buf = plaintext
+	return nil
+}
+
+// checkTrailing returns io.EOF if nothing follows the final frame.
+func (d *decryptingReader) checkTrailing() error {
+	var b [1]byte
+	for {
+		n, err := d.r.Read(b[:])
+		if n > 0 {
+			return ErrDecryptionFailed
+		}
+		if errors.Is(err, io.EOF) {
+			return io.EOF
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read stream: %w", err)
+		}
+	}
+}
+
+// readError maps a read failure inside a frame to the error returned to the
+// caller. A stream that ends before its final frame has been truncated.
+func (d *decryptingReader) readError(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrDecryptionFailed
+	}
+	return fmt.Errorf("failed to read stream: %w", err)
+}
@@ -0,0 +1,117 @@
+package security_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+// encryptStream encrypts data with a streaming writer, writing it in uneven
+// chunks to exercise frame buffering.
+func encryptStream(t *testing.T, key, data []byte) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	w, err := security.NewEncryptingWriter(&out, key)
+	tst.RequireNoError(t, err)
+	for len(data) > 0 {
+		n := min(len(data), 10_000)
+		_, err := w.Write(data[:n])
+		tst.RequireNoError(t, err)
+		data = data[n:]
+	}
+	tst.RequireNoError(t, w.Close())
+	return out.Bytes()
+}
+
+func decryptStream(key, ciphertext []byte) ([]byte, error) {
+	r, err := security.NewDecryptingReader(bytes.NewReader(ciphertext), key)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestStreamingEncryption_RoundTrip(t *testing.T) {
+	key, err := security.GenerateAESKey(32)
+	tst.RequireNoError(t, err)
+
+	sizes := []int{0, 1, security.StreamFrameSize - 1, security.StreamFrameSize, 3*security.StreamFrameSize + 123}
+	for _, size := range sizes {
+		data := make([]byte, size)
+		_, err := rand.Read(data)
+		tst.RequireNoError(t, err)
+
+		ciphertext := encryptStream(t, key, data)
+		decrypted, err := decryptStream(key, ciphertext)
+		tst.RequireNoError(t, err)
+		tst.AssertTrue(t, bytes.Equal(decrypted, data), "decrypted stream should match the original")
+	}
+}
+
+func TestStreamingEncryption_Tampering(t *testing.T) {
+	key, err := security.GenerateAESKey(32)
+	tst.RequireNoError(t, err)
+	otherKey, err := security.GenerateAESKey(32)
+	tst.RequireNoError(t, err)
+
+	data := make([]byte, 2*security.StreamFrameSize+500)
+	_, err = rand.Read(data)
+	tst.RequireNoError(t, err)
+	ciphertext := encryptStream(t, key, data)
+
+	// Locate the three frames: 12-byte header, then length-prefixed frames
+	const header = 12
+	frameLen := 4 + int(binary.BigEndian.Uint32(ciphertext[header:]))
+	first := ciphertext[header : header+frameLen]
+	second := ciphertext[header+frameLen : header+2*frameLen]
+	last := ciphertext[header+2*frameLen:]
+
+	corrupted := append([]byte{}, ciphertext...)
+	corrupted[header+frameLen+100] ^= 0x01
+
+	reordered := append([]byte{}, ciphertext[:header]...)
+	reordered = append(reordered, second...)
+	reordered = append(reordered, first...)
+	reordered = append(reordered, last...)
+
+	tests := []struct {
+		name       string
+		key        []byte
+		ciphertext []byte
+	}{
+		{"corrupted frame", key, corrupted},
+		{"reordered frames", key, reordered},
+		{"truncated at frame boundary", key, ciphertext[:header+2*frameLen]},
+		{"truncated mid-frame", key, ciphertext[:len(ciphertext)-10]},
+		{"trailing data", key, append(append([]byte{}, ciphertext...), 0)},
+		{"wrong key", otherKey, ciphertext},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decryptStream(tt.key, tt.ciphertext)
+			tst.AssertErrorIs(t, err, security.ErrDecryptionFailed)
+		})
+	}
+
+	_, err = decryptStream(key, ciphertext[:5])
+	tst.AssertErrorIs(t, err, security.ErrInvalidCiphertext)
+	_, err = security.NewEncryptingWriter(io.Discard, key[:10])
+	tst.AssertErrorIs(t, err, security.ErrInvalidKeySize)
+}
+
+func TestEncryptingWriter_WriteAfterClose(t *testing.T) {
+	key, err := security.GenerateAESKey(16)
+	tst.RequireNoError(t, err)
+
+	w, err := security.NewEncryptingWriter(io.Discard, key)
+	tst.RequireNoError(t, err)
+	tst.RequireNoError(t, w.Close())
+	tst.RequireNoError(t, w.Close())
+	_, err = w.Write([]byte("late"))
+	tst.AssertNotNil(t, err, "write after close should fail")
+}
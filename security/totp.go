@@ -0,0 +1,107 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidTOTPParams is returned when TOTP digits or period are out of range
+var ErrInvalidTOTPParams = errors.New("invalid TOTP parameters")
+
+// TOTPSecretSize is the length in bytes of secrets from GenerateTOTPSecret,
+// matching the HMAC-SHA1 output size recommended by RFC 4226.
+const TOTPSecretSize = 20
+
+// MaxTOTPSkew is the largest skew VerifyTOTP accepts. Each step of skew widens
+// the window of valid codes, and one or two steps cover realistic clock drift.
+const MaxTOTPSkew = 10
+
+// GenerateTOTPSecret returns a random TOTPSecretSize-byte secret. Encode it
+// with EncodeBase32NoPadding to share it with an authenticator app.
+func GenerateTOTPSecret() ([]byte, error) {
+	return GenerateRandomKey(TOTPSecretSize)
+}
+
+// GenerateTOTP returns the RFC 6238 time-based one-time password for secret
+// at time t, using HMAC-SHA1 as authenticator apps expect. digits must be
+// between 6 and 8 and period a positive whole number of seconds (30s is
+// conventional). It returns ErrEmptyKey if secret is empty and
+// ErrInvalidTOTPParams for out-of-range parameters or times before the Unix
+// epoch.
+func GenerateTOTP(secret []byte, t time.Time, digits int, period time.Duration) (string, error) {
+	counter, err := totpCounter(t, digits, period)
+	if err != nil {
+		return "", err
+	}
+	if len(secret) == 0 {
+		return "", ErrEmptyKey
+	}
+	return hotp(secret, uint64(counter), digits), nil
+}
+
+// VerifyTOTP reports whether code is the TOTP for secret at time t or at up
+// to skew periods before or after it, to allow for clock drift. Every
+// candidate is computed and compared in constant time. It returns false for
+// invalid parameters, as GenerateTOTP would reject them, and for a skew that
+// is negative or greater than MaxTOTPSkew.
+//
+// VerifyTOTP does not prevent a code from being used twice within its
+// window; callers that need that must record the last accepted time step.
+func VerifyTOTP(secret []byte, code string, t time.Time, skew, digits int, period time.Duration) bool {
+	counter, err := totpCounter(t, digits, period)
+	if err != nil || len(secret) == 0 || skew < 0 || skew > MaxTOTPSkew {
+		return false
+	}
+
+	match := 0
+	for offset := -int64(skew); offset <= int64(skew); offset++ {
+		if counter+offset < 0 {
+			continue
+		}
+		if SecureCompareString(hotp(secret, uint64(counter+offset), digits), code) {
+			match = 1
+		}
+	}
+	return match == 1
+}
+
+// totpCounter validates the TOTP parameters and returns the time step for t.
+func totpCounter(t time.Time, digits int, period time.Duration) (int64, error) {
+	if digits < 6 || digits > 8 {
+		return 0, fmt.Errorf("%w: digits must be between 6 and 8, got %d", ErrInvalidTOTPParams, digits)
+	}
+	if period < time.Second || period%time.Second != 0 {
+		return 0, fmt.Errorf(
+			"%w: period must be a positive whole number of seconds, got %s",
+			ErrInvalidTOTPParams,
+			period,
+		)
+	}
+	if t.Unix() < 0 {
+		return 0, fmt.Errorf("%w: time is before the Unix epoch", ErrInvalidTOTPParams)
+	}
+	return t.Unix() / int64(period/time.Second), nil
+}
+
+// hotp computes the RFC 4226 HOTP value for counter, zero-padded to digits.
+func hotp(secret []byte, counter uint64, digits int) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
@@ -0,0 +1,87 @@
+package security_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/julianstephens/go-utils/security"
+	tst "github.com/julianstephens/go-utils/tests"
+)
+
+func TestGenerateTOTP_RFC6238(t *testing.T) {
+	// RFC 6238 Appendix B, SHA-1 vectors
+	secret := []byte("12345678901234567890")
+	vectors := map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1111111111:  "14050471",
+		1234567890:  "89005924",
+		2000000000:  "69279037",
+		20000000000: "65353130",
+	}
+	for unix, want := range vectors {
+		code, err := security.GenerateTOTP(secret, time.Unix(unix, 0), 8, 30*time.Second)
+		tst.RequireNoError(t, err)
+		tst.AssertEqual(t, code, want)
+	}
+
+	// Six-digit codes are the last six digits of the eight-digit value
+	code, err := security.GenerateTOTP(secret, time.Unix(1111111109, 0), 6, 30*time.Second)
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, code, "081804")
+}
+
+func TestGenerateTOTP_InvalidParams(t *testing.T) {
+	secret, err := security.GenerateTOTPSecret()
+	tst.RequireNoError(t, err)
+	tst.AssertEqual(t, len(secret), security.TOTPSecretSize)
+	now := time.Now()
+
+	_, err = security.GenerateTOTP(secret, now, 5, 30*time.Second)
+	tst.AssertErrorIs(t, err, security.ErrInvalidTOTPParams)
+	_, err = security.GenerateTOTP(secret, now, 9, 30*time.Second)
+	tst.AssertErrorIs(t, err, security.ErrInvalidTOTPParams)
+	_, err = security.GenerateTOTP(secret, now, 6, 0)
+	tst.AssertErrorIs(t, err, security.ErrInvalidTOTPParams)
+	_, err = security.GenerateTOTP(secret, now, 6, 1500*time.Millisecond)
+	tst.AssertErrorIs(t, err, security.ErrInvalidTOTPParams)
+	_, err = security.GenerateTOTP(secret, time.Unix(-1, 0), 6, 30*time.Second)
+	tst.AssertErrorIs(t, err, security.ErrInvalidTOTPParams)
+	_, err = security.GenerateTOTP(nil, now, 6, 30*time.Second)
+	tst.AssertErrorIs(t, err, security.ErrEmptyKey)
+}
+
+func TestVerifyTOTP(t *testing.T) {
+	secret, err := security.GenerateTOTPSecret()
+	tst.RequireNoError(t, err)
+	period := 30 * time.Second
+	now := time.Unix(1700000000, 0)
+
+	code, err := security.GenerateTOTP(secret, now, 6, period)
+	tst.RequireNoError(t, err)
+
+	tst.AssertTrue(t, security.VerifyTOTP(secret, code, now, 0, 6, period), "current code should verify")
+
+	// One period of drift either way needs skew 1
+	tst.AssertFalse(t, security.VerifyTOTP(secret, code, now.Add(period), 0, 6, period), "drifted code should fail without skew")
+	tst.AssertTrue(t, security.VerifyTOTP(secret, code, now.Add(period), 1, 6, period), "late code should verify with skew 1")
+	tst.AssertTrue(t, security.VerifyTOTP(secret, code, now.Add(-period), 1, 6, period), "early code should verify with skew 1")
+	tst.AssertFalse(t, security.VerifyTOTP(secret, code, now.Add(2*period), 1, 6, period), "code outside the skew window should fail")
+
+	otherSecret, err := security.GenerateTOTPSecret()
+	tst.RequireNoError(t, err)
+	tst.AssertFalse(t, security.VerifyTOTP(otherSecret, code, now, 1, 6, period), "code for another secret should fail")
+	tst.AssertFalse(t, security.VerifyTOTP(secret, code+"0", now, 1, 6, period), "code of the wrong length should fail")
+	tst.AssertFalse(t, security.VerifyTOTP(secret, "", now, 1, 6, period), "empty code should fail")
+	tst.AssertFalse(t, security.VerifyTOTP(secret, code, now, -1, 6, period), "negative skew should fail")
+	tst.AssertTrue(t, security.VerifyTOTP(secret, code, now, security.MaxTOTPSkew, 6, period),
+		"maximum skew should verify")
+	tst.AssertFalse(t, security.VerifyTOTP(secret, code, now, security.MaxTOTPSkew+1, 6, period),
+		"excessive skew should fail")
+	tst.AssertFalse(t, security.VerifyTOTP(secret, code, now, 1, 4, period), "invalid digits should fail")
+
+	// Skew never reaches before the epoch
+	early, err := security.GenerateTOTP(secret, time.Unix(0, 0), 6, period)
+	tst.RequireNoError(t, err)
+	tst.AssertTrue(t, security.VerifyTOTP(secret, early, time.Unix(0, 0), 2, 6, period), "code at the epoch should verify")
+}
@@ -0,0 +1,141 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CheckDigitAlgo selects the check-digit scheme used by ValidateCheckDigit.
+type CheckDigitAlgo int
+
+const (
+	// CheckDigitLuhn is the mod-10 Luhn algorithm used by payment cards and IMEIs.
+	CheckDigitLuhn CheckDigitAlgo = iota
+	// CheckDigitVerhoeff detects all single-digit errors and adjacent transpositions.
+	CheckDigitVerhoeff
+	// CheckDigitDamm detects all single-digit errors and adjacent transpositions
+	// using a quasigroup table.
+	CheckDigitDamm
+)
+
+// String returns the name of the algorithm.
+func (a CheckDigitAlgo) String() string {
+	switch a {
+	case CheckDigitLuhn:
+		return "luhn"
+	case CheckDigitVerhoeff:
+		return "verhoeff"
+	case CheckDigitDamm:
+		return "damm"
+	default:
+		return "unknown"
+	}
+}
+
+// ValidateCheckDigit validates that the last digit of input is the correct check
+// digit for the preceding digits under algo. Spaces and hyphens are ignored, so
+// formatted values like "4111 1111 1111 1111" are accepted.
+func ValidateCheckDigit(input string, algo CheckDigitAlgo) error {
+	digits := make([]int, 0, len(input))
+	for _, ch := range input {
+		switch {
+		case ch >= '0' && ch <= '9':
+			digits = append(digits, int(ch-'0'))
+		case ch == ' ' || ch == '-':
+		default:
+			return NewValidationError(ModuleString, "check digit input contains non-digit characters",
+				"digits, spaces, and hyphens", input, ErrNotNumeric)
+		}
+	}
+	if len(digits) < 2 {
+		return NewValidationError(ModuleString, "check digit input is too short",
+			"at least 2 digits", len(digits), ErrTooShort)
+	}
+
+	var valid bool
+	switch algo {
+	case CheckDigitLuhn:
+		valid = luhnValid(digits)
+	case CheckDigitVerhoeff:
+		valid = verhoeffValid(digits)
+	case CheckDigitDamm:
+		valid = dammValid(digits)
+	default:
+		return fmt.Errorf("%w: unknown check digit algorithm %d", ErrInvalidInput, int(algo))
+	}
+
+	if !valid {
+		return NewValidationError(ModuleString, algo.String()+" check digit mismatch",
+			"valid check digit", strings.TrimSpace(input), ErrInvalidCheckDigit)
+	}
+	return nil
+}
+
+func luhnValid(digits []int) bool {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := digits[i]
+		if (len(digits)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+var verhoeffMultiply = [10][10]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 2, 3, 4, 0, 6, 7, 8, 9, 5},
+	{2, 3, 4, 0, 1, 7, 8, 9, 5, 6},
+	{3, 4, 0, 1, 2, 8, 9, 5, 6, 7},
+	{4, 0, 1, 2, 3, 9, 5, 6, 7, 8},
+	{5, 9, 8, 7, 6, 0, 4, 3, 2, 1},
+	{6, 5, 9, 8, 7, 1, 0, 4, 3, 2},
+	{7, 6, 5, 9, 8, 2, 1, 0, 4, 3},
+	{8, 7, 6, 5, 9, 3, 2, 1, 0, 4},
+	{9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+}
+
+var verhoeffPermute = [8][10]int{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+	{1, 5, 7, 6, 2, 8, 3, 0, 9, 4},
+	{5, 8, 0, 3, 7, 9, 6, 1, 4, 2},
+	{8, 9, 1, 6, 0, 4, 3, 5, 2, 7},
+	{9, 4, 5, 3, 1, 2, 6, 8, 7, 0},
+	{4, 2, 8, 6, 5, 7, 3, 9, 0, 1},
+	{2, 7, 9, 3, 8, 0, 6, 4, 1, 5},
+	{7, 0, 4, 6, 9, 1, 3, 2, 5, 8},
+}
+
+func verhoeffValid(digits []int) bool {
+	c := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		pos := len(digits) - 1 - i
+		c = verhoeffMultiply[c][verhoeffPermute[pos%8][digits[i]]]
+	}
+	return c == 0
+}
+
+var dammTable = [10][10]int{
+	{0, 3, 1, 7, 5, 9, 8, 6, 4, 2},
+	{7, 0, 9, 2, 1, 5, 4, 8, 6, 3},
+	{4, 2, 0, 6, 8, 7, 1, 3, 5, 9},
+	{1, 7, 5, 0, 9, 8, 3, 4, 2, 6},
+	{6, 1, 2, 3, 0, 4, 5, 9, 7, 8},
+	{3, 6, 7, 4, 2, 0, 9, 5, 8, 1},
+	{5, 8, 6, 9, 7, 2, 0, 1, 3, 4},
+	{8, 9, 4, 5, 3, 6, 2, 0, 1, 7},
+	{9, 4, 3, 8, 6, 1, 7, 2, 0, 5},
+	{2, 5, 8, 1, 4, 3, 6, 7, 9, 0},
+}
+
+func dammValid(digits []int) bool {
+	interim := 0
+	for _, d := range digits {
+		interim = dammTable[interim][d]
+	}
+	return interim == 0
+}
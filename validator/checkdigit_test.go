@@ -0,0 +1,55 @@
+package validator_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/julianstephens/go-utils/validator"
+)
+
+func TestValidateCheckDigit(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		algo    validator.CheckDigitAlgo
+		wantErr bool
+	}{
+		{"luhn valid card", "4111 1111 1111 1111", validator.CheckDigitLuhn, false},
+		{"luhn valid IMEI", "490154203237518", validator.CheckDigitLuhn, false},
+		{"luhn invalid", "4111 1111 1111 1112", validator.CheckDigitLuhn, true},
+		{"verhoeff valid", "2363", validator.CheckDigitVerhoeff, false},
+		{"verhoeff invalid", "2364", validator.CheckDigitVerhoeff, true},
+		{"verhoeff transposition", "3263", validator.CheckDigitVerhoeff, true},
+		{"damm valid", "5724", validator.CheckDigitDamm, false},
+		{"damm invalid", "5723", validator.CheckDigitDamm, true},
+		{"damm transposition", "7524", validator.CheckDigitDamm, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateCheckDigit(tt.input, tt.algo)
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("ValidateCheckDigit(%q, %s) should pass, got error: %v", tt.input, tt.algo, err)
+				}
+				return
+			}
+			var ve *validator.ValidationError
+			if !errors.As(err, &ve) || ve.Err != validator.ErrInvalidCheckDigit {
+				t.Errorf("ValidateCheckDigit(%q, %s) should fail with ErrInvalidCheckDigit, got %v", tt.input, tt.algo, err)
+			}
+		})
+	}
+}
+
+func TestValidateCheckDigit_InvalidInput(t *testing.T) {
+	if err := validator.ValidateCheckDigit("4111-abcd", validator.CheckDigitLuhn); err == nil {
+		t.Error("non-digit input should fail")
+	}
+	if err := validator.ValidateCheckDigit("7", validator.CheckDigitDamm); err == nil {
+		t.Error("single digit input should fail")
+	}
+	if err := validator.ValidateCheckDigit("5724", validator.CheckDigitAlgo(99)); !errors.Is(err, validator.ErrInvalidInput) {
+		t.Errorf("unknown algorithm should fail with ErrInvalidInput, got %v", err)
+	}
+}
@@ -40,6 +40,28 @@ func Any(validators ...func() error) error {
 	return lastErr
 }
 
+// Not returns a validator that inverts fn: it passes when fn fails and
+// returns ErrNegationFailed when fn passes
+func Not(fn func() error) func() error {
+	return func() error {
+		if err := fn(); err != nil {
+			return nil
+		}
+		return ErrNegationFailed
+	}
+}
+
+// When returns a validator that applies fn only if cond is true and passes
+// otherwise
+func When(cond bool, fn func() error) func() error {
+	return func() error {
+		if !cond {
+			return nil
+		}
+		return fn()
+	}
+}
+
 // ValidateSliceLength validates that a slice has the specified length
 func ValidateSliceLength[T any](input []T, length int) error {
 	if len(input) != length {
@@ -1,6 +1,7 @@
 package validator_test
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -146,6 +147,44 @@ func TestAny(t *testing.T) {
 	}
 }
 
+func TestNot(t *testing.T) {
+	// Test Not - should fail when the inner validator passes
+	if err := validator.Not(func() error { return nil })(); !errors.Is(err, validator.ErrNegationFailed) {
+		t.Errorf("Not with passing validator should return ErrNegationFailed, got: %v", err)
+	}
+
+	// Test Not - should pass when the inner validator fails
+	if err := validator.Not(func() error { return fmt.Errorf("test error") })(); err != nil {
+		t.Errorf("Not with failing validator should pass, got error: %v", err)
+	}
+
+	// Test Not composed with All
+	err := validator.All(
+		validator.Not(func() error { return validator.Parse().ValidateEmail("admin") }),
+		func() error { return validator.ValidateNonEmpty("admin") },
+	)
+	if err != nil {
+		t.Errorf("All with Not should pass, got error: %v", err)
+	}
+}
+
+func TestWhen(t *testing.T) {
+	failing := func() error { return fmt.Errorf("test error") }
+
+	// Test When - should always pass when the condition is false
+	if err := validator.When(false, failing)(); err != nil {
+		t.Errorf("When(false, ...) should pass, got error: %v", err)
+	}
+
+	// Test When - should apply the validator when the condition is true
+	if err := validator.When(true, failing)(); err == nil {
+		t.Error("When(true, failing) should fail")
+	}
+	if err := validator.When(true, func() error { return nil })(); err != nil {
+		t.Errorf("When(true, passing) should pass, got error: %v", err)
+	}
+}
+
 func TestValidateSliceLength(t *testing.T) {
 	// Test ValidateSliceLength - should pass
 	if err := validator.ValidateSliceLength([]int{1, 2, 3}, 3); err != nil {
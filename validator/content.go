@@ -0,0 +1,43 @@
+package validator
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// ValidateContentType sniffs the MIME type of data with http.DetectContentType
+// and validates that it is in allowed. Because the type comes from the bytes
+// themselves, a file whose extension or declared Content-Type was spoofed is
+// still caught. Entries in allowed are matched case-insensitively and ignore
+// parameters such as charset; an entry like "image/*" matches any subtype.
+// Only the first 512 bytes of data are examined.
+//
+// On failure it returns a *ValidationError whose Have field is the detected
+// type. Types http.DetectContentType does not recognize are reported as
+// "application/octet-stream".
+func ValidateContentType(data []byte, allowed ...string) error {
+	if len(data) == 0 {
+		return NewValidationError(ModuleParse, "content is empty", allowed, "", ErrEmptyInput)
+	}
+
+	detected := http.DetectContentType(data)
+	mediaType, _, err := mime.ParseMediaType(detected)
+	if err != nil {
+		mediaType = detected
+	}
+
+	for _, a := range allowed {
+		want, _, err := mime.ParseMediaType(a)
+		if err != nil {
+			want = strings.ToLower(strings.TrimSpace(a))
+		}
+		if want == mediaType {
+			return nil
+		}
+		if prefix, ok := strings.CutSuffix(want, "/*"); ok && strings.HasPrefix(mediaType, prefix+"/") {
+			return nil
+		}
+	}
+	return NewValidationError(ModuleParse, "content type not allowed", allowed, mediaType, ErrContentTypeNotAllowed)
+}
@@ -0,0 +1,58 @@
+package validator_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/julianstephens/go-utils/validator"
+)
+
+// pngHeader is the PNG signature followed by the start of an IHDR chunk.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01")
+
+func TestValidateContentType(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		allowed []string
+		wantErr bool
+	}{
+		{"png allowed", pngHeader, []string{"image/png"}, false},
+		{"png in list", pngHeader, []string{"application/pdf", "image/png"}, false},
+		{"png wildcard", pngHeader, []string{"image/*"}, false},
+		{"png case-insensitive", pngHeader, []string{"IMAGE/PNG"}, false},
+		{"png as pdf", pngHeader, []string{"application/pdf"}, true},
+		{"pdf allowed", []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3\n"), []string{"application/pdf"}, false},
+		{"text ignores charset", []byte("hello, world"), []string{"text/plain"}, false},
+		{"text with charset", []byte("hello, world"), []string{"text/plain; charset=utf-8"}, false},
+		{"html disguised as png", []byte("<!DOCTYPE html><html><script>alert(1)</script>"), []string{"image/png"}, true},
+		{"nothing allowed", pngHeader, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateContentType(tt.data, tt.allowed...)
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("ValidateContentType(%v) should pass, got error: %v", tt.allowed, err)
+				}
+				return
+			}
+			var ve *validator.ValidationError
+			if !errors.As(err, &ve) || ve.Err != validator.ErrContentTypeNotAllowed {
+				t.Errorf("ValidateContentType(%v) should fail with ErrContentTypeNotAllowed, got %v", tt.allowed, err)
+			}
+		})
+	}
+
+	err := validator.ValidateContentType(pngHeader, "application/pdf")
+	var ve *validator.ValidationError
+	if errors.As(err, &ve) && ve.Have != "image/png" {
+		t.Errorf("Have should be the detected type image/png, got %v", ve.Have)
+	}
+
+	err = validator.ValidateContentType(nil, "image/png")
+	if !errors.As(err, &ve) || ve.Err != validator.ErrEmptyInput {
+		t.Errorf("empty data should fail with ErrEmptyInput, got %v", err)
+	}
+}
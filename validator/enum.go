@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ValidateEnum validates that value is one of the allowed enum constants.
+// On failure it returns a *ValidationError whose Want field lists the allowed set.
+func ValidateEnum[T comparable](value T, allowed ...T) error {
+	for _, v := range allowed {
+		if value == v {
+			return nil
+		}
+	}
+	return NewValidationError(ModuleEnum, "value not in enum", allowed, value, ErrNotInSet)
+}
+
+// enumRegistry holds named enum sets registered with RegisterEnum
+var enumRegistry = struct {
+	sync.RWMutex
+	sets map[string][]string
+}{sets: make(map[string][]string)}
+
+// RegisterEnum registers a named set of allowed string values so rule-based
+// validation (e.g., a "oneof" struct rule) can look it up by name.
+// Registering an existing name replaces its allowed set.
+func RegisterEnum(name string, allowed ...string) {
+	values := make([]string, len(allowed))
+	copy(values, allowed)
+
+	enumRegistry.Lock()
+	defer enumRegistry.Unlock()
+	enumRegistry.sets[name] = values
+}
+
+// RegisteredEnum returns the allowed values registered under name.
+func RegisteredEnum(name string) ([]string, bool) {
+	enumRegistry.RLock()
+	defer enumRegistry.RUnlock()
+	values, ok := enumRegistry.sets[name]
+	if !ok {
+		return nil, false
+	}
+	out := make([]string, len(values))
+	copy(out, values)
+	return out, true
+}
+
+// ValidateRegisteredEnum validates value against the enum registered under name.
+func ValidateRegisteredEnum(name, value string) error {
+	allowed, ok := RegisteredEnum(name)
+	if !ok {
+		return fmt.Errorf("%w: enum %q is not registered", ErrInvalidInput, name)
+	}
+	return ValidateEnum(value, allowed...)
+}
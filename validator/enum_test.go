@@ -0,0 +1,75 @@
+package validator_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/go-utils/validator"
+)
+
+type orderStatus string
+
+const (
+	statusPending   orderStatus = "pending"
+	statusShipped   orderStatus = "shipped"
+	statusDelivered orderStatus = "delivered"
+)
+
+func TestValidateEnum(t *testing.T) {
+	allowed := []orderStatus{statusPending, statusShipped, statusDelivered}
+
+	if err := validator.ValidateEnum(statusShipped, allowed...); err != nil {
+		t.Errorf("ValidateEnum(shipped) should pass, got error: %v", err)
+	}
+
+	err := validator.ValidateEnum(orderStatus("cancelled"), allowed...)
+	if err == nil {
+		t.Fatal("ValidateEnum(cancelled) should fail")
+	}
+
+	var ve *validator.ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("ValidateEnum should return a ValidationError, got %T", err)
+	}
+	if ve.Module != validator.ModuleEnum {
+		t.Errorf("expected ModuleEnum, got %d", ve.Module)
+	}
+	if ve.Err != validator.ErrNotInSet {
+		t.Errorf("expected ErrNotInSet, got %v", ve.Err)
+	}
+	for _, want := range []string{"pending", "shipped", "delivered", "cancelled"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error message %q should contain %q", err.Error(), want)
+		}
+	}
+}
+
+func TestValidateEnumIntegers(t *testing.T) {
+	type level int
+	if err := validator.ValidateEnum(level(2), 1, 2, 3); err != nil {
+		t.Errorf("ValidateEnum(2) should pass, got error: %v", err)
+	}
+	if err := validator.ValidateEnum(level(4), 1, 2, 3); err == nil {
+		t.Error("ValidateEnum(4) should fail")
+	}
+}
+
+func TestRegisteredEnum(t *testing.T) {
+	validator.RegisterEnum("color", "red", "green", "blue")
+
+	allowed, ok := validator.RegisteredEnum("color")
+	if !ok || len(allowed) != 3 {
+		t.Fatalf("RegisteredEnum(color) = %v, %v", allowed, ok)
+	}
+
+	if err := validator.ValidateRegisteredEnum("color", "green"); err != nil {
+		t.Errorf("ValidateRegisteredEnum(color, green) should pass, got error: %v", err)
+	}
+	if err := validator.ValidateRegisteredEnum("color", "purple"); err == nil {
+		t.Error("ValidateRegisteredEnum(color, purple) should fail")
+	}
+	if err := validator.ValidateRegisteredEnum("size", "large"); err == nil {
+		t.Error("ValidateRegisteredEnum with unregistered enum should fail")
+	}
+}
@@ -9,6 +9,8 @@ const (
 	ModuleParse
 	ModuleString
 	ModuleNumber
+	ModuleEnum
+	ModuleSchema
 )
 
 var (
@@ -52,10 +54,18 @@ var (
 	ErrInvalidDate      = fmt.Errorf("invalid date")
 	ErrInvalidDuration  = fmt.Errorf("invalid duration")
 	ErrInvalidPhone     = fmt.Errorf("invalid phone number")
+	ErrInvalidSemver    = fmt.Errorf("invalid semantic version")
+	ErrSemverConstraint = fmt.Errorf("version does not satisfy constraint")
 	ErrNotInSet         = fmt.Errorf("value not in allowed set")
 	ErrSliceTooShort    = fmt.Errorf("slice is too short")
 	ErrSliceTooLong     = fmt.Errorf("slice is too long")
 	ErrFieldMismatch    = fmt.Errorf("field values do not match")
+	ErrNegationFailed   = fmt.Errorf("value passed a validator it must fail")
+	ErrSchemaViolation  = fmt.Errorf("value does not match schema")
+
+	ErrInvalidCheckDigit = fmt.Errorf("invalid check digit")
+
+	ErrContentTypeNotAllowed = fmt.Errorf("content type not allowed")
 
 	ErrNumberTooSmall   = fmt.Errorf("number is too small")
 	ErrNumberTooLarge   = fmt.Errorf("number is too large")
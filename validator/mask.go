@@ -0,0 +1,40 @@
+package validator
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// ValidateMaskedFormat validates that input matches a mask pattern such as
+// "****-****-####". In the pattern, '#' requires a digit and '*' requires a
+// masked character ('*'); any other pattern character must appear literally.
+// This suits inputs like partially-masked card numbers or redacted SSNs.
+func ValidateMaskedFormat(input, pattern string) error {
+	in, pat := []rune(input), []rune(pattern)
+	if len(in) != len(pat) {
+		return NewValidationError(ModuleString, "masked value length mismatch", pattern, input, ErrInvalidFormat)
+	}
+
+	for i, p := range pat {
+		ch := in[i]
+		var ok bool
+		switch p {
+		case '#':
+			ok = unicode.IsDigit(ch)
+		case '*':
+			ok = ch == '*'
+		default:
+			ok = ch == p
+		}
+		if !ok {
+			return NewValidationError(
+				ModuleString,
+				fmt.Sprintf("unexpected character %q at position %d", ch, i),
+				pattern,
+				input,
+				ErrInvalidFormat,
+			)
+		}
+	}
+	return nil
+}
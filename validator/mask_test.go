@@ -0,0 +1,44 @@
+package validator_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/julianstephens/go-utils/validator"
+)
+
+func TestValidateMaskedFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		pattern string
+		wantErr bool
+	}{
+		{"masked card number", "****-****-****-1234", "****-****-****-####", false},
+		{"redacted SSN", "***-**-6789", "***-**-####", false},
+		{"unmasked digit in masked position", "1234-****-****-1234", "****-****-****-####", true},
+		{"non-digit in required position", "****-****-****-12a4", "****-****-****-####", true},
+		{"wrong separator", "***.**.6789", "***-**-####", true},
+		{"too short", "***-**-678", "***-**-####", true},
+		{"too long", "***-**-67890", "***-**-####", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateMaskedFormat(tt.input, tt.pattern)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ValidateMaskedFormat(%q, %q) should fail", tt.input, tt.pattern)
+				}
+				var ve *validator.ValidationError
+				if !errors.As(err, &ve) || ve.Err != validator.ErrInvalidFormat {
+					t.Errorf("expected ValidationError wrapping ErrInvalidFormat, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ValidateMaskedFormat(%q, %q) should pass, got error: %v", tt.input, tt.pattern, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Rule validates a single string value. Rules are used by ValidateMap for
+// dynamic inputs, such as form submissions, whose fields are not known at
+// compile time. Any func(string) error can be used as a Rule.
+type Rule func(value string) error
+
+// Required returns a Rule that rejects empty or whitespace-only values.
+func Required() Rule {
+	return func(value string) error {
+		if strings.TrimSpace(value) == "" {
+			return NewValidationError(ModuleString, "value is required", "non-empty value", value, ErrEmptyInput)
+		}
+		return nil
+	}
+}
+
+// MinLength returns a Rule that requires at least min bytes.
+func MinLength(min int) Rule {
+	return func(value string) error {
+		return Strings[string]().ValidateMinLength(value, min)
+	}
+}
+
+// MaxLength returns a Rule that allows at most max bytes.
+func MaxLength(max int) Rule {
+	return func(value string) error {
+		return Strings[string]().ValidateMaxLength(value, max)
+	}
+}
+
+// Pattern returns a Rule that requires the value to match the regex pattern.
+func Pattern(pattern string) Rule {
+	return func(value string) error {
+		return Strings[string]().ValidatePattern(value, pattern)
+	}
+}
+
+// Email returns a Rule that requires a valid email address.
+func Email() Rule {
+	return Parse().ValidateEmail
+}
+
+// Int returns a Rule that requires a valid base-10 integer.
+func Int() Rule {
+	return Parse().ValidateInt
+}
+
+// In returns a Rule that requires the value to be one of allowed.
+func In(allowed ...string) Rule {
+	return func(value string) error {
+		return ValidateEnum(value, allowed...)
+	}
+}
+
+// Enum returns a Rule that validates against the enum registered under name
+// with RegisterEnum.
+func Enum(name string) Rule {
+	return func(value string) error {
+		return ValidateRegisteredEnum(name, value)
+	}
+}
+
+// ValidateMap validates values against per-field rules. Each field's rules run
+// in order and stop at the first failure. Fields with rules but no entry in
+// values are validated as the empty string, so Required catches missing fields;
+// entries without rules are ignored.
+//
+// The returned map holds one message per failing field and is nil when all
+// fields pass. The error wraps ErrInvalidInput and names the failing fields.
+func ValidateMap(values map[string]string, rules map[string][]Rule) (map[string]string, error) {
+	var failures map[string]string
+
+	for field, fieldRules := range rules {
+		value := values[field]
+		for _, rule := range fieldRules {
+			if rule == nil {
+				continue
+			}
+			if err := rule(value); err != nil {
+				if failures == nil {
+					failures = make(map[string]string)
+				}
+				failures[field] = ruleMessage(err)
+				break
+			}
+		}
+	}
+
+	if len(failures) == 0 {
+		return nil, nil
+	}
+
+	fields := make([]string, 0, len(failures))
+	for field := range failures {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return failures, fmt.Errorf("%w: fields failed validation: %s", ErrInvalidInput, strings.Join(fields, ", "))
+}
+
+// ruleMessage returns a short message suitable for showing next to a field.
+func ruleMessage(err error) string {
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return ve.Cause
+	}
+	return err.Error()
+}
@@ -0,0 +1,89 @@
+package validator_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/go-utils/validator"
+)
+
+func TestValidateMap(t *testing.T) {
+	rules := map[string][]validator.Rule{
+		"username": {validator.Required(), validator.MinLength(3)},
+		"email":    {validator.Required(), validator.Email()},
+		"plan":     {validator.In("free", "pro")},
+	}
+
+	values := map[string]string{
+		"username": "al",
+		"email":    "not-an-email",
+		"plan":     "pro",
+		"extra":    "ignored",
+	}
+
+	failures, err := validator.ValidateMap(values, rules)
+	if err == nil {
+		t.Fatal("ValidateMap should fail")
+	}
+	if !errors.Is(err, validator.ErrInvalidInput) {
+		t.Errorf("error should wrap ErrInvalidInput, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "email, username") {
+		t.Errorf("error should list failing fields, got %v", err)
+	}
+
+	if len(failures) != 2 {
+		t.Fatalf("expected 2 failures, got %d: %v", len(failures), failures)
+	}
+	if failures["username"] != "string below minimum length" {
+		t.Errorf("unexpected username message: %q", failures["username"])
+	}
+	if failures["email"] == "" || failures["email"] == failures["username"] {
+		t.Errorf("expected a distinct email message, got %q", failures["email"])
+	}
+}
+
+func TestValidateMap_Valid(t *testing.T) {
+	validator.RegisterEnum("test_map_region", "us", "eu")
+
+	rules := map[string][]validator.Rule{
+		"name":   {validator.Required(), validator.MaxLength(10)},
+		"age":    {validator.Int()},
+		"region": {validator.Enum("test_map_region")},
+		"code":   {validator.Pattern(`^[A-Z]{3}$`)},
+	}
+	values := map[string]string{"name": "alice", "age": "30", "region": "eu", "code": "ABC"}
+
+	failures, err := validator.ValidateMap(values, rules)
+	if err != nil {
+		t.Errorf("ValidateMap should pass, got %v", err)
+	}
+	if failures != nil {
+		t.Errorf("failures should be nil, got %v", failures)
+	}
+}
+
+func TestValidateMap_MissingRequired(t *testing.T) {
+	custom := func(value string) error {
+		if value == "forbidden" {
+			return errors.New("value is forbidden")
+		}
+		return nil
+	}
+	rules := map[string][]validator.Rule{
+		"title": {validator.Required()},
+		"tag":   {custom},
+	}
+
+	failures, err := validator.ValidateMap(map[string]string{"tag": "forbidden"}, rules)
+	if err == nil {
+		t.Fatal("ValidateMap should fail")
+	}
+	if failures["title"] != "value is required" {
+		t.Errorf("unexpected title message: %q", failures["title"])
+	}
+	if failures["tag"] != "value is forbidden" {
+		t.Errorf("unexpected tag message: %q", failures["tag"])
+	}
+}
@@ -0,0 +1,413 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+)
+
+// jsonSchema is a compiled JSON Schema. Only a subset of keywords is
+// supported; any other keyword is rejected when the schema is parsed so a
+// constraint is never silently skipped.
+type jsonSchema struct {
+	Type                 schemaTypes            `json:"type"`
+	Enum                 []any                  `json:"enum"`
+	Const                json.RawMessage        `json:"const"`
+	Required             []string               `json:"required"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	AdditionalProperties *schemaOrBool          `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	Pattern              string                 `json:"pattern"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	ExclusiveMinimum     *float64               `json:"exclusiveMinimum"`
+	ExclusiveMaximum     *float64               `json:"exclusiveMaximum"`
+
+	pattern *regexp.Regexp
+	konst   any
+}
+
+// schemaKeywords lists the keywords a schema may contain. Validation keywords
+// map to true; annotations that do not affect validation map to false and are
+// accepted but ignored.
+var schemaKeywords = map[string]bool{
+	"type": true, "enum": true, "const": true, "required": true,
+	"properties": true, "additionalProperties": true, "items": true,
+	"minItems": true, "maxItems": true, "minLength": true, "maxLength": true,
+	"pattern": true, "minimum": true, "maximum": true,
+	"exclusiveMinimum": true, "exclusiveMaximum": true,
+	"$schema": false, "$id": false, "$comment": false,
+	"title": false, "description": false, "default": false, "examples": false,
+}
+
+// UnmarshalJSON decodes a schema object, rejecting unsupported keywords.
+func (s *jsonSchema) UnmarshalJSON(data []byte) error {
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keywords); err != nil {
+		return fmt.Errorf("schema must be an object")
+	}
+	var unsupported []string
+	for keyword := range keywords {
+		if _, ok := schemaKeywords[keyword]; !ok {
+			unsupported = append(unsupported, keyword)
+		}
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return fmt.Errorf("unsupported keyword %q", unsupported[0])
+	}
+
+	// Decode through an alias type so this method is not called recursively
+	type plain jsonSchema
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// schemaTypes holds the "type" keyword, which may be a string or an array.
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+	var multi []string
+	if err := json.Unmarshal(data, &multi); err != nil {
+		return fmt.Errorf("type must be a string or array of strings")
+	}
+	*t = multi
+	return nil
+}
+
+// schemaOrBool holds "additionalProperties", which may be a boolean or a schema.
+type schemaOrBool struct {
+	allowed bool
+	schema  *jsonSchema
+}
+
+func (s *schemaOrBool) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &s.allowed); err == nil {
+		return nil
+	}
+	s.allowed = true
+	return json.Unmarshal(data, &s.schema)
+}
+
+// compile validates the schema's keywords and prepares patterns and constants.
+func (s *jsonSchema) compile() error {
+	for _, typ := range s.Type {
+		switch typ {
+		case "null", "boolean", "object", "array", "number", "integer", "string":
+		default:
+			return fmt.Errorf("unknown type %q", typ)
+		}
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		s.pattern = re
+	}
+	if s.Const != nil {
+		if err := json.Unmarshal(s.Const, &s.konst); err != nil {
+			return fmt.Errorf("invalid const: %w", err)
+		}
+	}
+	children := []*jsonSchema{s.Items}
+	if s.AdditionalProperties != nil {
+		children = append(children, s.AdditionalProperties.schema)
+	}
+	for _, prop := range s.Properties {
+		children = append(children, prop)
+	}
+	for _, child := range children {
+		if child == nil {
+			continue
+		}
+		if err := child.compile(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks a decoded JSON value against the schema, returning a
+// *ValidationError for the first violation found. path locates value within
+// the document as a JSON Pointer.
+func (s *jsonSchema) validate(value any, path string) error {
+	fail := func(cause string, want any) error {
+		if path != "" {
+			cause = path + ": " + cause
+		}
+		return NewValidationError(ModuleSchema, cause, want, value, ErrSchemaViolation)
+	}
+
+	if len(s.Type) > 0 {
+		matched := false
+		for _, typ := range s.Type {
+			if schemaTypeMatches(typ, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fail("wrong type", []string(s.Type))
+		}
+	}
+	if s.Enum != nil {
+		matched := false
+		for _, allowed := range s.Enum {
+			if reflect.DeepEqual(allowed, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fail("value not in enum", s.Enum)
+		}
+	}
+	if s.Const != nil && !reflect.DeepEqual(s.konst, value) {
+		return fail("value does not equal const", s.konst)
+	}
+
+	switch v := value.(type) {
+	case string:
+		n := utf8.RuneCountInString(v)
+		if s.MinLength != nil && n < *s.MinLength {
+			return fail("string is too short", *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			return fail("string is too long", *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			return fail("string does not match pattern", s.Pattern)
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			return fail("number is too small", *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			return fail("number is too large", *s.Maximum)
+		}
+		if s.ExclusiveMinimum != nil && v <= *s.ExclusiveMinimum {
+			return fail("number is not greater than exclusive minimum", *s.ExclusiveMinimum)
+		}
+		if s.ExclusiveMaximum != nil && v >= *s.ExclusiveMaximum {
+			return fail("number is not less than exclusive maximum", *s.ExclusiveMaximum)
+		}
+	case []any:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			return fail("array has too few items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			return fail("array has too many items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				if err := s.Items.validate(item, path+"/"+strconv.Itoa(i)); err != nil {
+					return err
+				}
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				return fail(fmt.Sprintf("missing required property %q", name), s.Required)
+			}
+		}
+		// Check properties in a stable order so the reported violation is deterministic
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			if !ok && s.AdditionalProperties != nil {
+				if !s.AdditionalProperties.allowed {
+					return fail(fmt.Sprintf("additional property %q is not allowed", name), nil)
+				}
+				prop = s.AdditionalProperties.schema
+			}
+			if prop == nil {
+				continue
+			}
+			if err := prop.validate(v[name], path+"/"+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// schemaTypeMatches reports whether a decoded JSON value has the JSON Schema type typ.
+func schemaTypeMatches(typ string, value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return typ == "null"
+	case bool:
+		return typ == "boolean"
+	case string:
+		return typ == "string"
+	case float64:
+		return typ == "number" || (typ == "integer" && v == math.Trunc(v))
+	case []any:
+		return typ == "array"
+	case map[string]any:
+		return typ == "object"
+	}
+	return false
+}
+
+// schemaRegistry holds schemas registered by name and by Go type
+var schemaRegistry = struct {
+	sync.RWMutex
+	named map[string]*jsonSchema
+	typed map[reflect.Type]*jsonSchema
+}{named: make(map[string]*jsonSchema), typed: make(map[reflect.Type]*jsonSchema)}
+
+// parseSchema parses and compiles a JSON Schema document.
+func parseSchema(schema string) (*jsonSchema, error) {
+	var s jsonSchema
+	if err := json.Unmarshal([]byte(schema), &s); err != nil {
+		return nil, fmt.Errorf("%w: invalid JSON schema: %v", ErrInvalidInput, err)
+	}
+	if err := s.compile(); err != nil {
+		return nil, fmt.Errorf("%w: invalid JSON schema: %v", ErrInvalidInput, err)
+	}
+	return &s, nil
+}
+
+// RegisterSchema registers a JSON Schema document under name so struct fields
+// tagged `jsonschema:"name"` are validated against it by Struct. Supported
+// keywords are type, enum, const, required, properties, additionalProperties,
+// items, minItems, maxItems, minLength, maxLength, pattern, minimum, maximum,
+// exclusiveMinimum, and exclusiveMaximum. The annotations $schema, $id,
+// $comment, title, description, default, and examples are accepted and
+// ignored. Any other keyword, such as $ref, allOf, or format, is rejected
+// with an error wrapping ErrInvalidInput. Registering an existing name
+// replaces its schema.
+func RegisterSchema(name, schema string) error {
+	s, err := parseSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	schemaRegistry.Lock()
+	defer schemaRegistry.Unlock()
+	schemaRegistry.named[name] = s
+	return nil
+}
+
+// RegisterTypeSchema registers a JSON Schema document for every struct field
+// of type T, so Struct validates such fields without a jsonschema tag. A tag
+// on the field takes precedence.
+func RegisterTypeSchema[T any](schema string) error {
+	s, err := parseSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	schemaRegistry.Lock()
+	defer schemaRegistry.Unlock()
+	schemaRegistry.typed[reflect.TypeFor[T]()] = s
+	return nil
+}
+
+// ValidateSchema validates value against the schema registered under name.
+// The value is marshaled to JSON first, so json struct tags and custom
+// marshalers apply.
+func ValidateSchema(name string, value any) error {
+	schemaRegistry.RLock()
+	s, ok := schemaRegistry.named[name]
+	schemaRegistry.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: schema %q is not registered", ErrInvalidInput, name)
+	}
+	return validateAgainstSchema(s, value)
+}
+
+// validateAgainstSchema marshals value to JSON and validates the result.
+func validateAgainstSchema(s *jsonSchema, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("%w: failed to marshal value: %v", ErrUnsupportedType, err)
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("%w: failed to decode value: %v", ErrUnsupportedType, err)
+	}
+	return s.validate(decoded, "")
+}
+
+// Struct validates the exported fields of a struct (or pointer to struct)
+// against JSON Schemas: fields tagged `jsonschema:"name"` use the schema
+// registered with RegisterSchema, and untagged fields whose type has a schema
+// registered with RegisterTypeSchema use that. Other fields are not checked.
+//
+// Every field is validated; the returned error joins one error per failing
+// field, each naming the field and wrapping a *ValidationError whose Err is
+// ErrSchemaViolation.
+func Struct(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return fmt.Errorf("%w: nil struct pointer", ErrInvalidInput)
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: expected struct, got %T", ErrUnsupportedType, v)
+	}
+
+	var errs []error
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		s, err := fieldSchema(field)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("field %s: %w", field.Name, err))
+			continue
+		}
+		if s == nil {
+			continue
+		}
+		if err := validateAgainstSchema(s, rv.Field(i).Interface()); err != nil {
+			errs = append(errs, fmt.Errorf("field %s: %w", field.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fieldSchema returns the schema that applies to field, or nil if none does.
+// The registry lock is held only for the lookup, not while the field is
+// marshaled, so a custom marshaler may itself use the registry.
+func fieldSchema(field reflect.StructField) (*jsonSchema, error) {
+	schemaRegistry.RLock()
+	defer schemaRegistry.RUnlock()
+
+	if name := field.Tag.Get("jsonschema"); name != "" {
+		s, ok := schemaRegistry.named[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: schema %q is not registered", ErrInvalidInput, name)
+		}
+		return s, nil
+	}
+	return schemaRegistry.typed[field.Type], nil
+}
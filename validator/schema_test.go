@@ -0,0 +1,181 @@
+package validator_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/julianstephens/go-utils/validator"
+)
+
+const addressSchema = `{
+	"type": "object",
+	"required": ["street", "zip"],
+	"properties": {
+		"street": {"type": "string", "minLength": 1},
+		"zip": {"type": "string", "pattern": "^[0-9]{5}$"},
+		"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 3}
+	},
+	"additionalProperties": false
+}`
+
+type schemaAddress struct {
+	Street string   `json:"street"`
+	Zip    string   `json:"zip"`
+	Tags   []string `json:"tags,omitempty"`
+}
+
+type schemaPort int
+
+type schemaOrder struct {
+	ID       string
+	Shipping schemaAddress `jsonschema:"test-address"`
+	Port     schemaPort
+}
+
+func registerTestSchemas(t *testing.T) {
+	t.Helper()
+	if err := validator.RegisterSchema("test-address", addressSchema); err != nil {
+		t.Fatalf("RegisterSchema failed: %v", err)
+	}
+	if err := validator.RegisterTypeSchema[schemaPort](`{"type": "integer", "minimum": 1, "maximum": 65535}`); err != nil {
+		t.Fatalf("RegisterTypeSchema failed: %v", err)
+	}
+}
+
+func TestStructSchemaPasses(t *testing.T) {
+	registerTestSchemas(t)
+
+	order := schemaOrder{
+		ID:       "o-1",
+		Shipping: schemaAddress{Street: "1 Main St", Zip: "12345", Tags: []string{"home"}},
+		Port:     8080,
+	}
+	if err := validator.Struct(order); err != nil {
+		t.Errorf("Struct should pass, got error: %v", err)
+	}
+	if err := validator.Struct(&order); err != nil {
+		t.Errorf("Struct should accept a pointer, got error: %v", err)
+	}
+}
+
+func TestStructSchemaFails(t *testing.T) {
+	registerTestSchemas(t)
+
+	order := schemaOrder{
+		Shipping: schemaAddress{Street: "1 Main St", Zip: "ABCDE"},
+		Port:     70000,
+	}
+	err := validator.Struct(order)
+	if err == nil {
+		t.Fatal("Struct should fail for an invalid zip and port")
+	}
+
+	var ve *validator.ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Struct should return a ValidationError, got %T", err)
+	}
+	if ve.Module != validator.ModuleSchema {
+		t.Errorf("expected ModuleSchema, got %d", ve.Module)
+	}
+	if ve.Err != validator.ErrSchemaViolation {
+		t.Errorf("expected ErrSchemaViolation, got %v", ve.Err)
+	}
+	for _, want := range []string{"field Shipping", "/zip", "pattern", "field Port", "too large"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error message %q should contain %q", err.Error(), want)
+		}
+	}
+}
+
+func TestValidateSchemaKeywords(t *testing.T) {
+	registerTestSchemas(t)
+
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"valid", schemaAddress{Street: "x", Zip: "12345"}, ""},
+		{"wrong type", "not an object", "wrong type"},
+		{"missing required", map[string]any{"street": "x"}, `missing required property "zip"`},
+		{"additional property", map[string]any{"street": "x", "zip": "12345", "extra": 1}, `additional property "extra"`},
+		{"too short", schemaAddress{Zip: "12345"}, "/street: string is too short"},
+		{"item type", map[string]any{"street": "x", "zip": "12345", "tags": []any{"a", 2}}, "/tags/1: wrong type"},
+		{"too many items", schemaAddress{Street: "x", Zip: "12345", Tags: []string{"a", "b", "c", "d"}}, "too many items"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateSchema("test-address", tt.value)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSchemaRegistrationErrors(t *testing.T) {
+	if err := validator.RegisterSchema("bad", `{"type": "strnig"}`); !errors.Is(err, validator.ErrInvalidInput) {
+		t.Errorf("unknown type should be rejected, got %v", err)
+	}
+	if err := validator.RegisterSchema("bad", `{"pattern": "("}`); !errors.Is(err, validator.ErrInvalidInput) {
+		t.Errorf("invalid pattern should be rejected, got %v", err)
+	}
+	for _, schema := range []string{
+		`{"$ref": "#/definitions/address"}`,
+		`{"type": "string", "format": "email"}`,
+		`{"properties": {"tags": {"items": {"anyOf": [{"type": "string"}]}}}}`,
+		`{"additionalProperties": {"minProperties": 1}}`,
+	} {
+		err := validator.RegisterSchema("bad", schema)
+		if !errors.Is(err, validator.ErrInvalidInput) || !strings.Contains(err.Error(), "unsupported keyword") {
+			t.Errorf("schema %s should be rejected for an unsupported keyword, got %v", schema, err)
+		}
+	}
+	if err := validator.RegisterSchema("annotated", `{"$schema": "https://json-schema.org/draft/2020-12/schema", "title": "Port", "description": "A TCP port", "type": "integer"}`); err != nil {
+		t.Errorf("annotations should be accepted, got %v", err)
+	}
+	if err := validator.ValidateSchema("never-registered", 1); !errors.Is(err, validator.ErrInvalidInput) {
+		t.Errorf("unregistered schema should fail, got %v", err)
+	}
+
+	type unregistered struct {
+		Field string `jsonschema:"never-registered"`
+	}
+	if err := validator.Struct(unregistered{}); !errors.Is(err, validator.ErrInvalidInput) {
+		t.Errorf("unregistered tag should fail, got %v", err)
+	}
+	if err := validator.Struct("not a struct"); !errors.Is(err, validator.ErrUnsupportedType) {
+		t.Errorf("non-struct should fail, got %v", err)
+	}
+}
+
+// lazyValue registers a schema from its marshaler, which needs the registry
+// write lock while Struct is validating.
+type lazyValue string
+
+func (v lazyValue) MarshalJSON() ([]byte, error) {
+	if err := validator.RegisterSchema("test-lazy-side", `{"type": "string"}`); err != nil {
+		return nil, err
+	}
+	return []byte(`"` + string(v) + `"`), nil
+}
+
+func TestStructSchemaMarshalerUsesRegistry(t *testing.T) {
+	if err := validator.RegisterSchema("test-lazy", `{"type": "string", "minLength": 1}`); err != nil {
+		t.Fatalf("RegisterSchema failed: %v", err)
+	}
+
+	type holder struct {
+		Value lazyValue `jsonschema:"test-lazy"`
+	}
+	if err := validator.Struct(holder{Value: "ok"}); err != nil {
+		t.Errorf("Struct should pass, got error: %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// semverPattern is the semantic versioning 2.0.0 grammar from semver.org.
+var semverPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`)
+
+// semver is a parsed semantic version. Build metadata is dropped because it
+// does not affect precedence.
+type semver struct {
+	major, minor, patch uint64
+	prerelease          []string
+}
+
+// parseSemver parses input as a semantic version, reporting whether it is valid.
+func parseSemver(input string) (semver, bool) {
+	m := semverPattern.FindStringSubmatch(input)
+	if m == nil {
+		return semver{}, false
+	}
+
+	var v semver
+	var err error
+	if v.major, err = strconv.ParseUint(m[1], 10, 64); err != nil {
+		return semver{}, false
+	}
+	if v.minor, err = strconv.ParseUint(m[2], 10, 64); err != nil {
+		return semver{}, false
+	}
+	if v.patch, err = strconv.ParseUint(m[3], 10, 64); err != nil {
+		return semver{}, false
+	}
+	if m[4] != "" {
+		v.prerelease = strings.Split(m[4], ".")
+	}
+	return v, true
+}
+
+// compare returns -1, 0, or 1 as v has lower, equal, or higher precedence
+// than other.
+func (v semver) compare(other semver) int {
+	for _, pair := range [][2]uint64{{v.major, other.major}, {v.minor, other.minor}, {v.patch, other.patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	// A version without a prerelease outranks one with a prerelease
+	switch {
+	case len(v.prerelease) == 0 && len(other.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(other.prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.prerelease) && i < len(other.prerelease); i++ {
+		if c := comparePrereleaseIdent(v.prerelease[i], other.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(v.prerelease) < len(other.prerelease):
+		return -1
+	case len(v.prerelease) > len(other.prerelease):
+		return 1
+	}
+	return 0
+}
+
+// comparePrereleaseIdent compares two prerelease identifiers: numeric ones
+// numerically, others lexically, with numeric identifiers ranking lower.
+func comparePrereleaseIdent(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		if an < bn {
+			return -1
+		} else if an > bn {
+			return 1
+		}
+		return 0
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// ValidateSemver validates that input is a semantic version
+// (major.minor.patch with optional -prerelease and +build metadata, e.g.
+// "1.4.0-rc.1+build.5"). A leading "v" is not accepted.
+func (pv *ParseValidator) ValidateSemver(input string) error {
+	if err := ValidateNonEmpty(input); err != nil {
+		return pv.Errorf("input cannot be empty", "non-empty string", input, ErrEmptyInput)
+	}
+	if _, ok := parseSemver(input); !ok {
+		return pv.Errorf("invalid semantic version", "major.minor.patch[-prerelease][+build]", input, ErrInvalidSemver)
+	}
+	return nil
+}
+
+// ValidateSemverConstraint validates that version satisfies constraint. A
+// constraint is a space-separated list of comparisons that must all hold,
+// each an operator (=, !=, >, >=, <, <=) followed by a version, e.g.
+// ">=1.2.0 <2.0.0". A version without an operator must match exactly.
+// Alternatives can be joined with "||", e.g. "<1.0.0 || >=1.5.0".
+func (pv *ParseValidator) ValidateSemverConstraint(version, constraint string) error {
+	if err := pv.ValidateSemver(version); err != nil {
+		return err
+	}
+	v, _ := parseSemver(version)
+
+	satisfied, err := semverSatisfies(v, constraint)
+	if err != nil {
+		return pv.Errorf("invalid semver constraint", "comparisons such as >=1.2.0 <2.0.0", constraint, err)
+	}
+	if !satisfied {
+		return pv.Errorf("version does not satisfy constraint", constraint, version, ErrSemverConstraint)
+	}
+	return nil
+}
+
+// semverOperators lists the comparison operators, longest first so that
+// ">=" is not read as ">".
+var semverOperators = []string{">=", "<=", "!=", ">", "<", "="}
+
+// semverOperatorSpace matches whitespace between an operator and its version,
+// so ">= 1.2.0" reads the same as ">=1.2.0".
+var semverOperatorSpace = regexp.MustCompile(`(>=|<=|!=|>|<|=)\s+`)
+
+// semverSatisfies reports whether v satisfies constraint.
+func semverSatisfies(v semver, constraint string) (bool, error) {
+	if strings.TrimSpace(constraint) == "" {
+		return false, ErrEmptyInput
+	}
+
+	constraint = semverOperatorSpace.ReplaceAllString(constraint, "$1")
+	satisfied := false
+	for _, alternative := range strings.Split(constraint, "||") {
+		comparisons := strings.Fields(alternative)
+		if len(comparisons) == 0 {
+			return false, fmt.Errorf("%w: empty alternative", ErrInvalidFormat)
+		}
+
+		all := true
+		for _, comparison := range comparisons {
+			op := "="
+			for _, candidate := range semverOperators {
+				if strings.HasPrefix(comparison, candidate) {
+					op = candidate
+					comparison = comparison[len(candidate):]
+					break
+				}
+			}
+			bound, ok := parseSemver(comparison)
+			if !ok {
+				return false, fmt.Errorf("%w: %q", ErrInvalidSemver, comparison)
+			}
+
+			c := v.compare(bound)
+			var holds bool
+			switch op {
+			case "=":
+				holds = c == 0
+			case "!=":
+				holds = c != 0
+			case ">":
+				holds = c > 0
+			case ">=":
+				holds = c >= 0
+			case "<":
+				holds = c < 0
+			case "<=":
+				holds = c <= 0
+			}
+			all = all && holds
+		}
+		satisfied = satisfied || all
+	}
+	return satisfied, nil
+}
@@ -0,0 +1,92 @@
+package validator_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/julianstephens/go-utils/validator"
+)
+
+func TestValidateSemver(t *testing.T) {
+	v := validator.Parse()
+
+	valid := []string{
+		"0.0.0",
+		"1.2.3",
+		"10.20.30",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-0.3.7",
+		"1.0.0-x.7.z.92",
+		"1.0.0+20130313144700",
+		"1.0.0-beta+exp.sha.5114f85",
+	}
+	for _, input := range valid {
+		if err := v.ValidateSemver(input); err != nil {
+			t.Errorf("ValidateSemver(%q) should pass, got error: %v", input, err)
+		}
+	}
+
+	invalid := []string{"", "1", "1.2", "v1.2.3", "01.2.3", "1.2.3-", "1.2.3-01", "1.2.3+", "1.2.3.4", "a.b.c"}
+	for _, input := range invalid {
+		err := v.ValidateSemver(input)
+		if err == nil {
+			t.Errorf("ValidateSemver(%q) should fail", input)
+			continue
+		}
+		var ve *validator.ValidationError
+		if !errors.As(err, &ve) {
+			t.Errorf("ValidateSemver(%q) should return a ValidationError, got %T", input, err)
+			continue
+		}
+		if input != "" && ve.Err != validator.ErrInvalidSemver {
+			t.Errorf("ValidateSemver(%q) should fail with ErrInvalidSemver, got %v", input, ve.Err)
+		}
+	}
+}
+
+func TestValidateSemverConstraint(t *testing.T) {
+	v := validator.Parse()
+
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"1.5.0", ">=1.2.0 <2.0.0", true},
+		{"1.2.0", ">= 1.2.0 < 2.0.0", true},
+		{"2.0.0", ">=1.2.0 <2.0.0", false},
+		{"1.1.9", ">=1.2.0 <2.0.0", false},
+		{"2.0.0-rc.1", "<2.0.0", true},
+		{"1.0.0-alpha.beta", ">1.0.0-alpha.1", true},
+		{"1.0.0-alpha", "<1.0.0-alpha.1", true},
+		{"1.0.0+build.1", "=1.0.0", true},
+		{"1.4.0", "1.4.0", true},
+		{"1.4.0", "!=1.4.0", false},
+		{"0.9.0", "<1.0.0 || >=1.5.0", true},
+		{"1.2.0", "<1.0.0 || >=1.5.0", false},
+	}
+	for _, tt := range tests {
+		err := v.ValidateSemverConstraint(tt.version, tt.constraint)
+		if tt.want && err != nil {
+			t.Errorf("%s should satisfy %q, got error: %v", tt.version, tt.constraint, err)
+		}
+		if !tt.want {
+			var ve *validator.ValidationError
+			if !errors.As(err, &ve) || ve.Err != validator.ErrSemverConstraint {
+				t.Errorf("%s should violate %q with ErrSemverConstraint, got %v", tt.version, tt.constraint, err)
+			}
+		}
+	}
+
+	if err := v.ValidateSemverConstraint("not-a-version", ">=1.0.0"); err == nil {
+		t.Error("invalid version should fail")
+	}
+	for _, constraint := range []string{"", ">=1.0", "~1.2.0", ">=1.0.0 ||"} {
+		err := v.ValidateSemverConstraint("1.0.0", constraint)
+		var ve *validator.ValidationError
+		if !errors.As(err, &ve) || ve.Err == validator.ErrSemverConstraint {
+			t.Errorf("constraint %q should be rejected as invalid, got %v", constraint, err)
+		}
+	}
+}